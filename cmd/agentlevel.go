@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// agentLevels are the autonomy levels agents work at, from the least to the
+// most autonomous. The guardrails of a level are the rule
+// autonomy/<level>, which local rules can override.
+var agentLevels = []string{"readonly-assistant", "pair-programmer", "autonomous-agent"}
+
+// flagAgentLevel selects the autonomy level, overriding the config's agentLevel.
+var flagAgentLevel string
+
+// addAgentLevelFlag registers --agent-level on a command that renders AGENTS.md.
+func addAgentLevelFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&flagAgentLevel,
+		"agent-level",
+		"",
+		"Autonomy level of the agents reading AGENTS.md ("+strings.Join(agentLevels, ", ")+"); adds the guardrails of the level (default from the config, none when unset)",
+	)
+}
+
+func init() {
+	addAgentLevelFlag(generateCmd)
+	addAgentLevelFlag(validateCmd)
+	addAgentLevelFlag(renderCmd)
+}
+
+// activeAgentLevel returns --agent-level, or the config's agentLevel when
+// unset ("" when neither is set).
+func activeAgentLevel() (string, error) {
+	level := flagAgentLevel
+	if level == "" {
+		level = cfg.AgentLevel
+	}
+	if level == "" {
+		return "", nil
+	}
+	for _, l := range agentLevels {
+		if strings.EqualFold(level, l) {
+			return l, nil
+		}
+	}
+	return "", fmt.Errorf("unknown agent level '%s' (available: %s)", level, strings.Join(agentLevels, ", "))
+}
+
+// agentLevelContent returns the guardrails section appended to AGENTS.md for
+// the active autonomy level ("" when none is set).
+func agentLevelContent() (string, error) {
+	level, err := activeAgentLevel()
+	if err != nil || level == "" {
+		return "", err
+	}
+	id := "autonomy/" + level
+	if !ruleExists(id) {
+		return "", fmt.Errorf("agent level '%s' has no guardrails (expected file: rules/%s.md)", level, id)
+	}
+	return mergeRules([]string{id}, nil)
+}
+
+// addAgentLevel appends the guardrails of the active autonomy level to the
+// AGENTS.md content.
+func addAgentLevel(agentsContent string) (string, error) {
+	guardrails, err := agentLevelContent()
+	if err != nil || guardrails == "" {
+		return agentsContent, err
+	}
+	return agentsContent + "\n\n---\n\n" + guardrails, nil
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"mime"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cego/ai-instructions/rules"
+)
+
+// Directory (next to the copilot output) that receives copied rule assets.
+const assetsDirName = "ai-instructions-assets"
+
+// Intermediate link prefix for assets until the output location is known.
+const assetRefPrefix = "ai-instructions-asset:"
+
+var flagInlineAssets bool
+
+var (
+	imageRefPattern = regexp.MustCompile(`(!\[[^\]]*\]\(\s*)([^)\s]+)`)
+	assetRefPattern = regexp.MustCompile(regexp.QuoteMeta(assetRefPrefix) + `([^)\s]+)`)
+)
+
+// rewriteRuleAssets replaces relative image references in a rule with either
+// data URIs (--inline-assets) or references to the embedded asset.
+func rewriteRuleAssets(id, content string) string {
+	return imageRefPattern.ReplaceAllStringFunc(content, func(m string) string {
+		parts := imageRefPattern.FindStringSubmatch(m)
+		target := parts[2]
+
+		u, err := url.Parse(target)
+		if err != nil || u.Scheme != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+			return m
+		}
+
+		name := path.Join(path.Dir(id), u.Path)
+		data, err := rules.Asset(name)
+		if err != nil {
+			return m
+		}
+
+		if flagInlineAssets {
+			mimeType := mime.TypeByExtension(path.Ext(name))
+			if mimeType == "" {
+				mimeType = "application/octet-stream"
+			}
+			return parts[1] + "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+		}
+		return parts[1] + assetRefPrefix + name
+	})
+}
+
+// resolveAssetLinks points asset references at assetsDir, relative to the file at outPath.
+func resolveAssetLinks(content, outPath, assetsDir string) string {
+	rel, err := filepath.Rel(filepath.Dir(outPath), assetsDir)
+	if err != nil {
+		rel = assetsDir
+	}
+	return assetRefPattern.ReplaceAllStringFunc(content, func(m string) string {
+		name := strings.TrimPrefix(m, assetRefPrefix)
+		return path.Join(filepath.ToSlash(rel), name)
+	})
+}
+
+// referencedAssets lists the embedded assets referenced by content.
+func referencedAssets(content string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, m := range assetRefPattern.FindAllStringSubmatch(content, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// writeAssets copies all assets referenced by content into assetsDir.
+func writeAssets(content, assetsDir string) error {
+	for _, name := range referencedAssets(content) {
+		data, err := rules.Asset(name)
+		if err != nil {
+			return err
+		}
+		if err := writeFileWithDirs(filepath.Join(assetsDir, filepath.FromSlash(name)), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assetsDirFor returns the assets directory next to the given output file.
+func assetsDirFor(outPath string) string {
+	return filepath.Join(filepath.Dir(outPath), assetsDirName)
+}
+
+// Existence probe for embedded assets
+func assetExists(name string) bool {
+	_, err := rules.Asset(name)
+	return err == nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/rules"
+)
+
+// flagAttribution appends the provenance of third-party rules to the output.
+var flagAttribution bool
+
+// addAttributionFlag registers --attribution on a command that renders instructions.
+func addAttributionFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flagAttribution,
+		"attribution",
+		false,
+		"Append an attribution section listing the author, source and license declared by the included rules",
+	)
+}
+
+func init() {
+	addAttributionFlag(generateCmd)
+	addAttributionFlag(validateCmd)
+	addAttributionFlag(renderCmd)
+	addAttributionFlag(exportCmd)
+}
+
+// attributionAppendix lists the rules among ids that declare provenance
+// ("" without --attribution or when none does).
+func attributionAppendix(ids []string) string {
+	if !flagAttribution {
+		return ""
+	}
+	var lines []string
+	for _, id := range ids {
+		r, err := rules.Load(id)
+		if err != nil || !r.Meta.HasProvenance() {
+			continue
+		}
+		var parts []string
+		if r.Meta.Author != "" {
+			parts = append(parts, "by "+r.Meta.Author)
+		}
+		if r.Meta.Source != "" {
+			parts = append(parts, "from "+r.Meta.Source)
+		}
+		if r.Meta.License != "" {
+			parts = append(parts, "licensed under "+r.Meta.License)
+		}
+		lines = append(lines, fmt.Sprintf("- `rules/%s.md`: %s", id, strings.Join(parts, ", ")))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "## Attribution\n\nParts of these instructions are based on third-party rules:\n\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/audit"
+	"github.com/cego/ai-instructions/internal/warnings"
+)
+
+var (
+	flagWithAudit    bool
+	flagAuditReports []string
+)
+
+// auditHeading is the heading of the audit section.
+const auditHeading = "Known vulnerable dependencies"
+
+// blankRuns matches the blank lines left where a section was removed.
+var blankRuns = regexp.MustCompile(`\n{3,}`)
+
+// withoutLiveAudit removes the audit section from the current content of a
+// generated file, and normalizes the blank lines of both contents. Live audit
+// results change as advisories are published, without any change to the
+// repository, so they are only compared when an --audit-report is given.
+func withoutLiveAudit(current []byte, expected string) ([]byte, string) {
+	if len(flagAuditReports) > 0 {
+		return current, expected
+	}
+	lines := strings.Split(string(current), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "## "+auditHeading {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && !strings.HasPrefix(lines[end], "#") && strings.TrimSpace(lines[end]) != "---" {
+			end++
+		}
+		lines = append(lines[:i], lines[end:]...)
+		stripped := blankRuns.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+		return []byte(stripped), blankRuns.ReplaceAllString(expected, "\n\n")
+	}
+	return current, expected
+}
+
+// maxAuditAdvisories limits the advisories listed per package.
+const maxAuditAdvisories = 3
+
+// buildAuditSection lists dependencies with known vulnerabilities, from the
+// --audit-report files or by running composer/npm audit in dir. It is empty
+// unless --with-audit or --audit-report is set.
+func buildAuditSection(dir string) string {
+	if !flagWithAudit && len(flagAuditReports) == 0 {
+		return ""
+	}
+
+	var findings []audit.Finding
+	if len(flagAuditReports) > 0 {
+		for _, path := range flagAuditReports {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				warnings.Add("audit", "%v", err)
+				continue
+			}
+			f, err := audit.Parse(data)
+			if err != nil {
+				warnings.Add("audit", "%s: %v", path, err)
+				continue
+			}
+			findings = append(findings, f...)
+		}
+	} else {
+		f, err := audit.Run(context.Background(), dir)
+		if err != nil {
+			warnings.Add("audit", "%v", err)
+		}
+		findings = f
+	}
+	if len(findings) == 0 {
+		return ""
+	}
+	audit.Sort(findings)
+
+	var b strings.Builder
+	b.WriteString("## " + auditHeading + "\n\n")
+	b.WriteString("Do not add code that depends on these packages; prefer upgrading them to a fixed version first.\n")
+	for _, f := range findings {
+		fmt.Fprintf(&b, "\n- `%s` (%s", f.Package, f.Ecosystem)
+		if f.Severity != "" {
+			b.WriteString(", " + f.Severity)
+		}
+		b.WriteString(")")
+		advisories := f.Advisories
+		if len(advisories) > maxAuditAdvisories {
+			advisories = append(advisories[:maxAuditAdvisories:maxAuditAdvisories], fmt.Sprintf("%d more", len(f.Advisories)-maxAuditAdvisories))
+		}
+		if len(advisories) > 0 {
+			b.WriteString(": " + strings.Join(advisories, "; "))
+		}
+	}
+	return b.String()
+}
+
+// addAuditFlags registers --with-audit and --audit-report on a command that renders instructions.
+func addAuditFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flagWithAudit,
+		"with-audit",
+		false,
+		"Run composer audit / npm audit and add a section listing dependencies with known vulnerabilities",
+	)
+	cmd.Flags().StringArrayVar(
+		&flagAuditReports,
+		"audit-report",
+		nil,
+		"Read a composer audit or npm audit JSON report (e.g. a CI artifact) instead of running the tools; implies --with-audit",
+	)
+}
+
+func init() {
+	addAuditFlags(generateCmd)
+	addAuditFlags(validateCmd)
+	addAuditFlags(exportCmd)
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/config"
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/diff"
+)
+
+var (
+	flagBatchRoot         string
+	flagBatchDashboardOut string
+)
+
+// repoReport is the validation result of one repository in batch mode.
+type repoReport struct {
+	Path  string
+	Stack string
+	Rules []string
+	Files []fileReport
+	// Uncovered lists detected technologies without any rules.
+	Uncovered []string
+	// Warnings are non-fatal problems found while validating (serve mode).
+	Warnings []string
+	Err      string
+}
+
+type fileReport struct {
+	Path   string
+	Status fileStatus
+	// Diff turns the current file into the expected one (outdated files only).
+	Diff string
+}
+
+// OK reports whether the file is up to date.
+func (f fileReport) OK() bool {
+	return f.Status == statusUpToDate
+}
+
+// UpToDate reports whether every generated file of the repository is current.
+func (r repoReport) UpToDate() bool {
+	if r.Err != "" {
+		return false
+	}
+	for _, f := range r.Files {
+		if !f.OK() {
+			return false
+		}
+	}
+	return true
+}
+
+func (s fileStatus) String() string {
+	switch s {
+	case statusMissing:
+		return "missing"
+	case statusOutdated:
+		return "outdated"
+	}
+	return "up to date"
+}
+
+var batchCmd = &cobra.Command{
+	Use:   "batch [repo...]",
+	Short: "Validate several repositories and summarize compliance",
+	Long: "Runs validate in every given repository (and every repository directly below --root) with\n" +
+		"that repository's config and local rules, and prints a summary. With --dashboard-out a static\n" +
+		"HTML dashboard of the results is written as well.",
+	GroupID: groupIntegration,
+	Example: "  ai-instructions batch --root ~/src --dashboard-out report.html",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repos := append([]string{}, args...)
+		if flagBatchRoot != "" {
+			found, err := findRepos(flagBatchRoot)
+			if err != nil {
+				return err
+			}
+			repos = append(repos, found...)
+		}
+		if len(repos) == 0 {
+			return fmt.Errorf("no repositories given (pass directories or --root)")
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		// Restore the working directory and its inputs afterwards
+		defer func() {
+			_ = os.Chdir(cwd)
+			_ = reloadInputs()
+		}()
+
+		var reports []repoReport
+		for _, repo := range repos {
+			abs := repo
+			if !filepath.IsAbs(abs) {
+				abs = filepath.Join(cwd, repo)
+			}
+			report := validateRepo(abs)
+			report.Path = repo
+			reports = append(reports, report)
+			printRepoReport(report)
+		}
+
+		failed := 0
+		for _, r := range reports {
+			if !r.UpToDate() {
+				failed++
+			}
+		}
+		fmt.Printf("\n%d of %d repositories up to date.\n", len(reports)-failed, len(reports))
+
+		if flagBatchDashboardOut != "" {
+			path := flagBatchDashboardOut
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(cwd, path)
+			}
+			if err := writeFileWithDirs(path, []byte(renderDashboard(reports))); err != nil {
+				return err
+			}
+			fmt.Printf("Dashboard written to %s\n", flagBatchDashboardOut)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("batch validation failed: %d of %d repositories not up to date", failed, len(reports))
+		}
+		return nil
+	},
+}
+
+// findRepos returns the directories directly below root that are git repositories.
+func findRepos(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var repos []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			repos = append(repos, dir)
+		}
+	}
+	sort.Strings(repos)
+	return repos, nil
+}
+
+// validateRepo runs the validate checks inside dir with the repository's own inputs.
+func validateRepo(dir string) repoReport {
+	var report repoReport
+	if err := os.Chdir(dir); err != nil {
+		report.Err = err.Error()
+		return report
+	}
+	if err := reloadInputs(); err != nil {
+		report.Err = err.Error()
+		return report
+	}
+
+	stack, err := detect.DetectStack(".")
+	if err != nil {
+		report.Err = fmt.Sprintf("stack detection failed: %v", err)
+		return report
+	}
+	report.Stack = stackSummary(stack)
+	report.Rules = buildGeneralRulesFromDetection(stack)
+
+	for _, t := range ruleTechnologies(stack) {
+		covered := false
+		for _, id := range report.Rules {
+			if strings.HasPrefix(id, ruleDir(t.Name)+"/") {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			report.Uncovered = append(report.Uncovered, stackEntryFor(t.Name).Label)
+		}
+	}
+	sort.Strings(report.Uncovered)
+
+	files, err := buildExpectedFiles(stack)
+	if err != nil {
+		report.Err = err.Error()
+		return report
+	}
+	// Repositories are untrusted (the webhook server checks pull requests), so
+	// every read stays below the repository: a path or symlink leading out of
+	// it must not put other files of the host into the report
+	root, err := os.OpenRoot(".")
+	if err != nil {
+		report.Err = err.Error()
+		return report
+	}
+	defer root.Close()
+	for _, f := range files {
+		if !config.LocalPath(f.Path) {
+			report.Err = fmt.Sprintf("expected file '%s' is outside the repository", f.Path)
+			report.Files = nil
+			return report
+		}
+		current, err := root.ReadFile(filepath.FromSlash(f.Path))
+		fr := fileReport{Path: f.Path, Status: fileStatusOf(current, err, f.Content)}
+		if fr.Status == statusOutdated && err == nil {
+			fr.Diff = diff.Unified(f.Path, f.Path, string(current), f.Content, 3)
+		}
+		report.Files = append(report.Files, fr)
+	}
+	return report
+}
+
+func printRepoReport(r repoReport) {
+	switch {
+	case r.Err != "":
+		fmt.Printf("ERROR     %s: %s\n", r.Path, r.Err)
+	case r.UpToDate():
+		fmt.Printf("OK        %s\n", r.Path)
+	default:
+		var stale []string
+		for _, f := range r.Files {
+			if !f.OK() {
+				stale = append(stale, fmt.Sprintf("%s (%s)", f.Path, f.Status))
+			}
+		}
+		fmt.Printf("OUTDATED  %s: %s\n", r.Path, strings.Join(stale, ", "))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+
+	batchCmd.Flags().StringVar(
+		&flagBatchRoot,
+		"root",
+		"",
+		"Also check every git repository directly below this directory",
+	)
+
+	batchCmd.Flags().StringVar(
+		&flagBatchDashboardOut,
+		"dashboard-out",
+		"",
+		"Write a static HTML dashboard of the results to this file (e.g. report.html)",
+	)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/gitmeta"
+	"github.com/cego/ai-instructions/internal/warnings"
+)
+
+var flagBranch string
+
+// branchEnvVars name the branch in CI, where the checkout is usually a
+// detached HEAD (GitHub Actions: head ref of pull requests first).
+var branchEnvVars = []string{"GITHUB_HEAD_REF", "GITHUB_REF_NAME", "CI_COMMIT_REF_NAME", "BRANCH_NAME"}
+
+var (
+	branchOnce     sync.Once
+	resolvedBranch string
+)
+
+// currentBranch returns the branch for the branch profiles: --branch, the CI
+// environment, then the checked out branch ("" when unknown).
+func currentBranch() string {
+	if flagBranch != "" {
+		return flagBranch
+	}
+	branchOnce.Do(func() {
+		for _, name := range branchEnvVars {
+			if v := os.Getenv(name); v != "" {
+				resolvedBranch = v
+				return
+			}
+		}
+		branch, err := gitmeta.CurrentBranch(".")
+		if err != nil {
+			warnings.Add("git", "could not read the current branch: %v", err)
+		}
+		resolvedBranch = branch
+	})
+	return resolvedBranch
+}
+
+// branchRules returns the rules of the config branch profiles matching the
+// current branch that are not in ids yet.
+func branchRules(ids []string) []string {
+	if len(cfg.Branches) == 0 {
+		return nil
+	}
+	branch := currentBranch()
+	if branch == "" {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	for _, id := range ids {
+		seen[id] = true
+	}
+	var out []string
+	for _, p := range cfg.Branches {
+		ok, err := path.Match(p.Pattern, branch)
+		if err != nil {
+			warnings.Add("config", "ignoring branch profile '%s': %v", p.Pattern, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		for _, id := range p.Rules {
+			switch {
+			case seen[id]:
+			case !ruleExists(id):
+				warnings.Add("config", "branch profile '%s': unknown rule '%s'", p.Pattern, id)
+			default:
+				seen[id] = true
+				out = append(out, id)
+			}
+		}
+	}
+	return out
+}
+
+// addBranchFlag registers --branch on a command that resolves branch profiles.
+func addBranchFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&flagBranch,
+		"branch",
+		"",
+		"Branch for the config branch profiles (default: from the CI environment or git HEAD)",
+	)
+}
+
+func init() {
+	addBranchFlag(generateCmd)
+	addBranchFlag(validateCmd)
+	addBranchFlag(exportCmd)
+}
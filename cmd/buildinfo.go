@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/rules"
+)
+
+// modulePath is used when the binary carries no module build info.
+const modulePath = "github.com/cego/ai-instructions"
+
+var flagBuildInfoJSON bool
+
+// buildInfo describes this binary for packaging pipelines (brew formulae, image labels).
+type buildInfo struct {
+	Module     string            `json:"module"`
+	Version    string            `json:"version"`
+	GoVersion  string            `json:"go_version"`
+	RulesHash  string            `json:"rules_hash"`
+	RulesCount int               `json:"rules_count"`
+	Targets    []buildInfoTarget `json:"targets"`
+}
+
+type buildInfoTarget struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Default bool   `json:"default"`
+}
+
+var buildInfoCmd = &cobra.Command{
+	Use:   "build-info",
+	Short: "Print module path, version, Go version, embedded rules hash and supported targets",
+	// Describes the binary only: the working directory's config and local rules are ignored
+	GroupID: groupIntrospection,
+	Example: "  ai-instructions build-info --json",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		rules.SetLocalDir("")
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := collectBuildInfo()
+		if err != nil {
+			return err
+		}
+
+		if flagBuildInfoJSON {
+			out, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
+		}
+
+		fmt.Printf("Module:     %s\n", info.Module)
+		fmt.Printf("Version:    %s\n", info.Version)
+		fmt.Printf("Go version: %s\n", info.GoVersion)
+		fmt.Printf("Rules:      %d (hash %s)\n", info.RulesCount, info.RulesHash)
+		fmt.Println("Targets:")
+		for _, t := range info.Targets {
+			suffix := ""
+			if t.Default {
+				suffix = " (default)"
+			}
+			fmt.Printf("- %s: %s%s\n", t.Name, t.Path, suffix)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(buildInfoCmd)
+
+	buildInfoCmd.Flags().BoolVar(
+		&flagBuildInfoJSON,
+		"json",
+		false,
+		"Output as JSON",
+	)
+}
+
+func collectBuildInfo() (buildInfo, error) {
+	info := buildInfo{
+		Module:    modulePath,
+		Version:   cliVersion(),
+		GoVersion: runtime.Version(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Path != "" {
+		info.Module = bi.Main.Path
+	}
+
+	count, err := rules.Count()
+	if err != nil {
+		return info, err
+	}
+	info.RulesCount = count
+	if info.RulesHash, err = rules.Hash(); err != nil {
+		return info, err
+	}
+
+	defaults := map[string]bool{}
+	for _, name := range defaultTargets {
+		defaults[name] = true
+	}
+	for _, t := range targets {
+		info.Targets = append(info.Targets, buildInfoTarget{Name: t.Name, Path: t.Path, Default: defaults[t.Name]})
+	}
+	return info, nil
+}
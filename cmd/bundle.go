@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/archive"
+	"github.com/cego/ai-instructions/internal/config"
+	"github.com/cego/ai-instructions/rules"
+)
+
+// Layout of a bundle archive.
+const (
+	bundleRulesDir = "rules"
+	bundleConfig   = "config.yaml"
+	bundleInfo     = "bundle.json"
+)
+
+// rulesSourceScheme prefixes bundle paths given to --rules-source.
+const rulesSourceScheme = "file:"
+
+var (
+	flagBundleOut   string
+	flagRulesSource string
+)
+
+// rulesSourceDir is where the --rules-source bundle is extracted ("" when unused).
+var rulesSourceDir string
+
+// bundleMeta describes a bundle (bundle.json).
+type bundleMeta struct {
+	Version   string `json:"version"`
+	RulesHash string `json:"rules_hash"`
+	Rules     int    `json:"rules"`
+	Config    bool   `json:"config"`
+}
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Package the rules and config into one archive for offline use (--rules-source file:<bundle>)",
+	Long: "Packages the embedded rules, the local rules (--rules-dir) and the config file into a\n" +
+		"single tar.gz archive. On machines without access to the rule sources, pass it with\n" +
+		"--rules-source file:<bundle> to use exactly these rules and this config.",
+	GroupID: groupRules,
+	Example: "  ai-instructions bundle --out rules.tar.gz\n" +
+		"  ai-instructions generate --rules-source file:rules.tar.gz",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snapshot, err := rules.Snapshot()
+		if err != nil {
+			return err
+		}
+		files := map[string][]byte{}
+		for name, data := range snapshot {
+			files[bundleRulesDir+"/"+name] = data
+		}
+
+		hash, err := rules.Hash()
+		if err != nil {
+			return err
+		}
+		ids, err := rules.List()
+		if err != nil {
+			return err
+		}
+		meta := bundleMeta{Version: version, RulesHash: hash, Rules: len(ids)}
+
+		configData, err := config.ReadFile(flagConfig)
+		switch {
+		case err == nil:
+			files[bundleConfig] = configData
+			meta.Config = true
+		case !os.IsNotExist(err):
+			return err
+		}
+
+		info, err := json.MarshalIndent(meta, "", "  ")
+		if err != nil {
+			return err
+		}
+		files[bundleInfo] = append(info, '\n')
+
+		var buf bytes.Buffer
+		if err := archive.WriteTarGz(&buf, files); err != nil {
+			return err
+		}
+		if err := writeFileWithDirs(flagBundleOut, buf.Bytes()); err != nil {
+			return err
+		}
+		fmt.Printf("Bundle with %d rules (hash %s) written to %s\n", meta.Rules, hash, flagBundleOut)
+		if !meta.Config {
+			fmt.Printf("No config file (%s) found; the bundle contains rules only.\n", flagConfig)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+
+	bundleCmd.Flags().StringVar(
+		&flagBundleOut,
+		"out",
+		"ai-instructions-bundle.tar.gz",
+		"Bundle archive to write",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&flagRulesSource,
+		"rules-source",
+		"",
+		"Use the rules and config of a bundle instead of the embedded rules, e.g. file:bundle.tar.gz",
+	)
+}
+
+// openRulesSource extracts the bundle named by source and returns the
+// directory it was extracted to.
+func openRulesSource(source string) (string, error) {
+	path, ok := strings.CutPrefix(source, rulesSourceScheme)
+	if !ok || path == "" {
+		return "", fmt.Errorf("unsupported rules source '%s' (expected %s<bundle.tar.gz>)", source, rulesSourceScheme)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp("", "ai-instructions-bundle-")
+	if err != nil {
+		return "", err
+	}
+	if err := archive.Extract(data, dir); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("rules source %s: %w", path, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, bundleInfo)); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("rules source %s is not an ai-instructions bundle (no %s)", path, bundleInfo)
+	}
+	rulesSourceDir = dir
+	return dir, nil
+}
+
+// closeRulesSource removes the extracted bundle, if any.
+func closeRulesSource() {
+	if rulesSourceDir != "" {
+		os.RemoveAll(rulesSourceDir)
+		rulesSourceDir = ""
+	}
+}
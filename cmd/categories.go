@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/rules"
+)
+
+// Rule categories rendered to their own files, besides the main instructions.
+const (
+	categoryReview        = "review"
+	categoryCommitMessage = "commit-message"
+	categoryPullRequest   = "pull-request"
+)
+
+var categories = []string{categoryReview, categoryCommitMessage, categoryPullRequest}
+
+// filterAudience keeps the rules written for reviewers (reviewer=true) or for
+// authors (reviewer=false, the default audience).
+func filterAudience(ids []string, reviewer bool) []string {
+	var out []string
+	for _, id := range ids {
+		r, err := rules.Load(id)
+		if err != nil {
+			continue
+		}
+		if r.Meta.IsReviewer() == reviewer {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// Category rules: git/<category> followed by <framework>/<category> for the
+// detected stack. Review rules (audience: reviewer) may also be general.md files.
+func buildCategoryRulesFromDetection(stack *detect.DetectedStack, category string) []string {
+	files := []string{category}
+	if category == categoryReview {
+		files = []string{"general", category}
+	}
+
+	var ids []string
+	addIfExists(&ids, "git/"+category)
+	for _, file := range files {
+		for _, t := range ruleTechnologies(stack) {
+			addRuleFilesFor(&ids, ruleDir(t.Name), t.Version, file)
+		}
+	}
+
+	ids = filterApplicable(ids, stack)
+	for _, file := range files {
+		ids = append(ids, conditionalRules(stack, "/"+file, ids)...)
+	}
+	return filterExpired(filterAudience(filterOptional(ids), category == categoryReview))
+}
+
+func buildCategoryRulesFromFlags(category string) []string {
+	var ids []string
+	addIfExists(&ids, "git/"+category)
+	for _, r := range flagRules {
+		r = filepath.ToSlash(strings.TrimSpace(r))
+		if r == "" {
+			continue
+		}
+		addIfExists(&ids, r+"/"+category)
+		if strings.HasSuffix(r, "/"+category) {
+			addIfExists(&ids, r)
+		}
+	}
+	return filterExpired(filterAudience(ids, category == categoryReview))
+}
+
+// categoryRuleIDs resolves the rules of every category, from detection or from
+// --rule flags when stack is nil.
+func categoryRuleIDs(stack *detect.DetectedStack) map[string][]string {
+	out := map[string][]string{}
+	for _, c := range categories {
+		if stack == nil {
+			out[c] = buildCategoryRulesFromFlags(c)
+		} else {
+			out[c] = buildCategoryRulesFromDetection(stack, c)
+		}
+	}
+	return out
+}
+
+// buildCategoryContents merges the rules of every category (categories
+// without rules are omitted). The commit message rules are preceded by the
+// commit conventions detected in stack (nil without detection).
+func buildCategoryContents(ids map[string][]string, stack *detect.DetectedStack) (map[string]string, error) {
+	out := map[string]string{}
+	for c, catIDs := range ids {
+		if len(catIDs) == 0 {
+			continue
+		}
+		content, err := loadAndMergeRules(catIDs)
+		if err != nil {
+			return nil, err
+		}
+		if c == categoryCommitMessage {
+			if section := buildCommitConventionsSection(stack); section != "" {
+				content = section + "\n\n" + content
+			}
+		}
+		out[c] = content
+	}
+	return out, nil
+}
+
+// joinCategoryContents appends the category contents to content, e.g. to
+// collect the assets referenced by every output.
+func joinCategoryContents(content string, categoryContents map[string]string) string {
+	for _, c := range categories {
+		if cc := categoryContents[c]; cc != "" {
+			content += "\n\n" + cc
+		}
+	}
+	return content
+}
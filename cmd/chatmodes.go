@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"path"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/rules"
+)
+
+// Rules below chatModesPrefix are personas rendered as Copilot chat modes
+// (chatModesDir) and, with the claude-agents target, Claude Code subagents.
+const (
+	chatModesPrefix = "chatmodes/"
+	chatModesDir    = ".github/chatmodes"
+	claudeAgentsDir = ".claude/agents"
+)
+
+// applicableChatModes returns the personas whose `when:` condition holds for the stack.
+func applicableChatModes(stack *detect.DetectedStack) ([]*rules.Rule, error) {
+	ids, err := rules.Glob(chatModesPrefix + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*rules.Rule
+	for _, id := range filterApplicable(ids, stack) {
+		r, err := rules.Load(id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// buildChatModeFiles renders .github/chatmodes/<name>.chatmode.md per persona.
+func buildChatModeFiles(stack *detect.DetectedStack, _ []target) ([]renderedFile, error) {
+	modes, err := applicableChatModes(stack)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []renderedFile
+	for _, r := range modes {
+		content := frontMatter([][2]string{
+			{"description", yamlQuote(r.Meta.Description)},
+			{"tools", yamlList(r.Meta.Tools)},
+			{"model", yamlQuote(r.Meta.Model)},
+		}) + rewriteRuleAssets(r.ID, r.Body)
+
+		files = append(files, renderedFile{
+			Label:   "COPILOT CHAT MODE",
+			Path:    path.Join(chatModesDir, path.Base(r.ID)+".chatmode.md"),
+			Content: content,
+		})
+	}
+	return files, nil
+}
+
+// buildClaudeAgentFiles renders the same personas as Claude Code subagents
+// (.claude/agents/<name>.md). Tools are product specific and left out, so
+// the subagents inherit all tools.
+func buildClaudeAgentFiles(stack *detect.DetectedStack, _ []target) ([]renderedFile, error) {
+	modes, err := applicableChatModes(stack)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []renderedFile
+	for _, r := range modes {
+		name := path.Base(r.ID)
+		description := r.Meta.Description
+		if description == "" {
+			description = strings.ReplaceAll(name, "-", " ")
+		}
+
+		content := frontMatter([][2]string{
+			{"name", name},
+			{"description", yamlQuote(description)},
+		}) + rewriteRuleAssets(r.ID, r.Body)
+
+		files = append(files, renderedFile{
+			Label:   "CLAUDE AGENT",
+			Path:    path.Join(claudeAgentsDir, name+".md"),
+			Content: content,
+		})
+	}
+	return files, nil
+}
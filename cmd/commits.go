@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+)
+
+// commitRuleLabels describe the common commitlint rules; other enabled rules
+// are listed by name.
+var commitRuleLabels = map[string]string{
+	"type-enum":              "**Types:** %s",
+	"scope-enum":             "**Scopes:** %s",
+	"type-case":              "**Type case:** %s",
+	"scope-case":             "**Scope case:** %s",
+	"subject-case":           "**Subject case:** %s",
+	"header-max-length":      "**Header:** at most %s characters",
+	"header-min-length":      "**Header:** at least %s characters",
+	"subject-max-length":     "**Subject:** at most %s characters",
+	"body-max-line-length":   "**Body lines:** at most %s characters",
+	"footer-max-line-length": "**Footer lines:** at most %s characters",
+}
+
+// commitParts are the labels of the parts of a commit message.
+var commitParts = map[string]string{"header": "Header", "subject": "Subject", "body": "Body", "footer": "Footer"}
+
+// commitRuleImplied are the rules the format line already covers.
+var commitRuleImplied = map[string]bool{"type-empty": true, "subject-empty": true}
+
+// releaseEffects explain how each release tool reads the commits.
+var releaseEffects = map[string]string{
+	"semantic-release":       "publishes releases from the commit history: `fix` and `perf` commits make a patch release, `feat` a minor release and breaking changes a major release; other types do not release",
+	"release-please":         "opens release pull requests from the commit history: `fix` commits make a patch release, `feat` a minor release and breaking changes a major release",
+	"standard-version":       "bumps the version and writes CHANGELOG.md from the commits: `fix` commits make a patch release, `feat` a minor release and breaking changes a major release",
+	"commit-and-tag-version": "bumps the version and writes CHANGELOG.md from the commits: `fix` commits make a patch release, `feat` a minor release and breaking changes a major release",
+	"conventional-changelog": "writes CHANGELOG.md from the commits, with `feat` and `fix` commits and breaking changes as its entries",
+}
+
+// buildCommitConventionsSection states the commit message rules the project
+// enforces with commitlint and the release tooling reading the commits, so
+// that commit messages pass the commit-msg hook and release correctly. It
+// is empty when neither was detected, leaving the general commit message
+// rules to apply.
+func buildCommitConventionsSection(stack *detect.DetectedStack) string {
+	if stack == nil || stack.Commits.Empty() {
+		return ""
+	}
+	c := stack.Commits
+
+	var b strings.Builder
+	b.WriteString("## Commit conventions\n\n")
+	if c.Commitlint != "" {
+		fmt.Fprintf(&b, "Commit messages are linted by commitlint (`%s`", c.Commitlint)
+		if len(c.Extends) > 0 {
+			fmt.Fprintf(&b, ", extending %s", codeList(c.Extends))
+		}
+		b.WriteString("). Follow these rules instead of general commit message advice")
+		if c.Hook != "" {
+			fmt.Fprintf(&b, "; the %s hook rejects commits that break them", c.Hook)
+		}
+		b.WriteString(".\n")
+	} else {
+		fmt.Fprintf(&b, "Commit messages are not linted, but %s reads them. Follow the Conventional Commits format instead of general commit message advice.\n", c.Release)
+	}
+
+	conventional := c.Commitlint == "" || c.Rule("type-enum") != nil
+	if conventional {
+		b.WriteString("\n- **Format:** `type(scope): subject`, e.g. `fix(auth): reject expired tokens`")
+		switch scope := c.Rule("scope-empty"); {
+		case scope != nil && scope.Never:
+			b.WriteString("; the scope is required")
+		case scope != nil:
+			b.WriteString("; leave out the scope")
+		default:
+			b.WriteString("; the scope is optional")
+		}
+		if c.Commitlint == "" {
+			fmt.Fprintf(&b, "\n- **Types:** %s", codeList([]string{"feat", "fix", "docs", "refactor", "perf", "test", "build", "ci", "chore"}))
+		}
+	}
+	for _, rule := range c.Rules {
+		if commitRuleImplied[rule.Name] || rule.Name == "scope-empty" {
+			continue
+		}
+		b.WriteString("\n- " + commitRuleLine(rule))
+	}
+	if c.Commitlint != "" && len(c.Rules) == 0 {
+		fmt.Fprintf(&b, "\n- See `%s` for the enforced rules.", c.Commitlint)
+	}
+	if conventional {
+		b.WriteString("\n- **Breaking changes:** add `!` after the type or scope (`feat(api)!: …`) or a `BREAKING CHANGE:` footer.")
+	}
+
+	if effect, ok := releaseEffects[c.Release]; ok {
+		fmt.Fprintf(&b, "\n\n%s", c.Release)
+		if c.ReleaseConfig != "" {
+			fmt.Fprintf(&b, " (`%s`)", c.ReleaseConfig)
+		}
+		fmt.Fprintf(&b, " %s.", effect)
+		if len(c.ChangelogTypes) > 0 {
+			fmt.Fprintf(&b, " The changelog lists %s commits.", codeList(c.ChangelogTypes))
+		}
+		b.WriteString(" Choose the type by the effect of the change on users, and write the subject for the changelog reader.")
+	}
+	return b.String()
+}
+
+// commitRuleLine describes a commitlint rule, e.g. "**Header:** at most 100
+// characters" or "**Subject case:** not sentence-case, upper-case".
+func commitRuleLine(rule detect.CommitRule) string {
+	value := strings.Join(rule.Value, ", ")
+	if strings.HasSuffix(rule.Name, "-enum") {
+		value = codeList(rule.Value)
+	}
+
+	var line string
+	switch format, ok := commitRuleLabels[rule.Name]; {
+	case ok && rule.Never && strings.Contains(format, "%s characters"):
+		// A negated length limit is unusual enough to quote as configured
+		line = fmt.Sprintf("`%s`: never %s", rule.Name, value)
+	case ok && rule.Never:
+		line = fmt.Sprintf(format, "not "+value)
+	case ok:
+		line = fmt.Sprintf(format, value)
+	case rule.Name == "subject-full-stop" || rule.Name == "header-full-stop":
+		part := strings.TrimSuffix(rule.Name, "-full-stop")
+		if rule.Never {
+			line = fmt.Sprintf("**%s:** does not end with `%s`", commitParts[part], value)
+		} else {
+			line = fmt.Sprintf("**%s:** ends with `%s`", commitParts[part], value)
+		}
+	case rule.Name == "body-leading-blank" || rule.Name == "footer-leading-blank":
+		part := strings.TrimSuffix(rule.Name, "-leading-blank")
+		if rule.Never {
+			line = fmt.Sprintf("**%s:** no blank line before it", commitParts[part])
+		} else {
+			line = fmt.Sprintf("**%s:** a blank line before it", commitParts[part])
+		}
+	default:
+		applicable := "always"
+		if rule.Never {
+			applicable = "never"
+		}
+		line = fmt.Sprintf("`%s`: %s", rule.Name, applicable)
+		if value != "" {
+			line += " " + value
+		}
+	}
+	if rule.Level == "warning" {
+		line += " (warning)"
+	}
+	return line
+}
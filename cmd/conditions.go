@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/condition"
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/warnings"
+	"github.com/cego/ai-instructions/rules"
+)
+
+// stackVars exposes the detected stack to `when:` conditions as stack.<Field>,
+// and the file each version came from as stack.<Field>Manifest (e.g.
+// stack.PHPManifest == "composer.json").
+func stackVars(stack *detect.DetectedStack) map[string]string {
+	vars := map[string]string{}
+	for name, version := range stack.Values() {
+		vars["stack."+name] = version
+	}
+	if stack != nil {
+		for _, t := range stack.Technologies {
+			vars["stack."+detect.FieldName(t.Name)+"Manifest"] = t.Manifest()
+		}
+	}
+	// stack.I18n lists the i18n libraries of localized projects
+	if stack != nil && stack.I18n != nil {
+		vars["stack.I18n"] = strings.Join(stack.I18n.Libraries, ",")
+	}
+	return vars
+}
+
+// ruleApplies evaluates the rule's `when:` condition (rules without one always apply).
+func ruleApplies(id string, stack *detect.DetectedStack) bool {
+	r, err := rules.Load(id)
+	if err != nil || r.Meta.When == "" {
+		return true
+	}
+	ok, err := condition.Eval(r.Meta.When, stackVars(stack))
+	if err != nil {
+		warnings.Add("rules", "ignoring rule '%s': invalid condition: %v", id, err)
+		return false
+	}
+	return ok
+}
+
+// filterApplicable drops rules whose `when:` condition does not hold for the stack.
+func filterApplicable(ids []string, stack *detect.DetectedStack) []string {
+	var out []string
+	for _, id := range ids {
+		if ruleApplies(id, stack) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// conditionalRules returns rules ending in suffix (e.g. "/general") that declare
+// a `when:` condition which holds for the stack, so cross-cutting rules activate
+// on combinations of detected technologies.
+func conditionalRules(stack *detect.DetectedStack, suffix string, exclude []string) []string {
+	names, err := rules.List()
+	if err != nil {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	for _, id := range exclude {
+		seen[id] = true
+	}
+
+	var out []string
+	for _, id := range names {
+		if seen[id] || !strings.HasSuffix(id, suffix) {
+			continue
+		}
+		r, err := rules.Load(id)
+		if err != nil || r.Meta.When == "" {
+			continue
+		}
+		if ruleApplies(id, stack) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
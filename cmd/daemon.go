@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/policy"
+	"github.com/cego/ai-instructions/internal/rpc"
+	"github.com/cego/ai-instructions/internal/warnings"
+	"github.com/cego/ai-instructions/internal/watch"
+)
+
+var flagDaemonSocket string
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run a local JSON-RPC server on a unix socket for editor plugins",
+	Long: "Serves newline-delimited JSON-RPC 2.0 on a unix socket so that editor extensions can show live\n" +
+		"previews without spawning the CLI for every keystroke. Methods (params: {\"path\": project root}):\n" +
+		"detect, resolve, render (optionally {\"file\": path} for one file), validate and invalidate.\n" +
+		"Results are cached per project until its config, rules or any file examined by detection change.",
+	GroupID: groupIntegration,
+	Example: "  ai-instructions daemon --socket /tmp/ai-instructions.sock\n" +
+		"  echo '{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"render\",\"params\":{\"path\":\".\"}}' | nc -U /tmp/ai-instructions.sock",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		l, err := listenUnix(flagDaemonSocket)
+		if err != nil {
+			return err
+		}
+		d := &daemon{cwd: cwd, projects: map[string]*daemonProject{}}
+		server := &rpc.Server{Methods: d.methods()}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			_ = l.Close()
+		}()
+
+		fmt.Printf("Listening on %s (Ctrl+C to stop)\n", flagDaemonSocket)
+		defer func() {
+			_ = os.Chdir(cwd)
+		}()
+		return server.Serve(l)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+
+	daemonCmd.Flags().StringVar(
+		&flagDaemonSocket,
+		"socket",
+		daemonSocketPath(),
+		"Unix socket to listen on",
+	)
+}
+
+// daemonSocketPath is the default socket of the daemon: one per user, in
+// $XDG_RUNTIME_DIR when set.
+func daemonSocketPath() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, fmt.Sprintf("ai-instructions-%d.sock", os.Getuid()))
+}
+
+// listenUnix listens on path, replacing a stale socket left by a daemon that
+// did not shut down, but not one that still answers. The socket is created in
+// a private directory and only moved to path once it is 0600, so other users
+// can never connect to it.
+func listenUnix(path string) (net.Listener, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("a daemon is already listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".ai-instructions-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "sock")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	// Closing would remove tmp, not the socket at path
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, 0o600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return &unixSocket{Listener: l, path: path}, nil
+}
+
+// unixSocket removes the socket file when the listener is closed.
+type unixSocket struct {
+	net.Listener
+	path string
+}
+
+func (l *unixSocket) Close() error {
+	// Before closing: Serve returns as soon as the listener is closed
+	_ = os.Remove(l.path)
+	return l.Listener.Close()
+}
+
+// daemon answers requests one at a time: detection and rendering use the
+// working directory and the loaded config/rules, which are process-wide.
+type daemon struct {
+	cwd string
+	mu  sync.Mutex
+	// current is the project whose config and rules are loaded.
+	current  string
+	projects map[string]*daemonProject
+}
+
+// methods returns the JSON-RPC methods of the daemon.
+func (d *daemon) methods() map[string]rpc.Handler {
+	return map[string]rpc.Handler{
+		"ping":       func(json.RawMessage) (any, error) { return map[string]string{"version": version}, nil },
+		"detect":     d.handle(d.detect),
+		"resolve":    d.handle(d.resolve),
+		"render":     d.handle(d.render),
+		"validate":   d.handle(d.validate),
+		"invalidate": d.invalidate,
+	}
+}
+
+// daemonProject caches the results for one project root. They stay valid
+// while the fingerprint of inputs is unchanged.
+type daemonProject struct {
+	inputs      []string
+	fingerprint string
+	stack       *detect.DetectedStack
+	warnings    []warnings.Warning
+	// files is rendered on the first render or validate request.
+	files []renderedFile
+}
+
+type daemonParams struct {
+	Path string `json:"path"`
+	File string `json:"file"`
+}
+
+// handle decodes the params and runs fn inside the project, loaded from the
+// cache when it is still fresh.
+func (d *daemon) handle(fn func(*daemonProject, daemonParams) (any, error)) rpc.Handler {
+	return func(raw json.RawMessage) (any, error) {
+		var params daemonParams
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, rpc.InvalidParams("%v", err)
+		}
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		p, err := d.load(d.root(params.Path))
+		if err != nil {
+			return nil, err
+		}
+		return fn(p, params)
+	}
+}
+
+// root resolves a project path against the daemon's working directory.
+func (d *daemon) root(path string) string {
+	if path == "" {
+		return d.cwd
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(d.cwd, path)
+	}
+	return filepath.Clean(path)
+}
+
+// load switches to root and returns its cached project, detecting the stack
+// again when one of its inputs changed.
+func (d *daemon) load(root string) (*daemonProject, error) {
+	if err := os.Chdir(root); err != nil {
+		return nil, err
+	}
+	if p, ok := d.projects[root]; ok && watch.Fingerprint(p.inputs) == p.fingerprint {
+		if d.current != root {
+			if err := d.reload(root); err != nil {
+				return nil, err
+			}
+		}
+		return p, nil
+	}
+	delete(d.projects, root)
+
+	warnings.Reset()
+	if err := d.reload(root); err != nil {
+		return nil, err
+	}
+	detect.StartTrace()
+	stack, err := detect.DetectStack(".")
+	events := detect.StopTrace()
+	if err != nil {
+		return nil, fmt.Errorf("stack detection failed: %w", err)
+	}
+
+	// Every file detection looked at (or looked for), plus the config, the
+	// policy and local rules, decides whether the cache is still valid
+	inputs := []string{filepath.Join(root, flagConfig), filepath.Join(root, flagRulesDir)}
+	if path := policyPath(policy.DefaultPath); filepath.IsAbs(path) {
+		inputs = append(inputs, path)
+	} else {
+		inputs = append(inputs, filepath.Join(root, path))
+	}
+	seen := map[string]bool{}
+	for _, e := range events {
+		switch e.Action {
+		case detect.TraceExamine, detect.TraceMissing, detect.TraceError:
+		default:
+			continue
+		}
+		path := e.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		if !seen[path] {
+			seen[path] = true
+			inputs = append(inputs, path)
+		}
+	}
+	p := &daemonProject{inputs: inputs, stack: stack, warnings: warnings.List()}
+	p.fingerprint = watch.Fingerprint(inputs)
+	d.projects[root] = p
+	return p, nil
+}
+
+// reload loads the config and local rules of root, the current directory.
+func (d *daemon) reload(root string) error {
+	d.current = ""
+	if err := reloadInputs(); err != nil {
+		return err
+	}
+	d.current = root
+	return nil
+}
+
+// rendered returns (and caches) every file generate would write.
+func (d *daemon) rendered(p *daemonProject) ([]renderedFile, error) {
+	if p.files == nil {
+		warnings.Reset()
+		files, err := buildExpectedFiles(p.stack)
+		if err != nil {
+			return nil, err
+		}
+		p.warnings = append(p.warnings, warnings.List()...)
+		p.files = files
+	}
+	return p.files, nil
+}
+
+func (d *daemon) detect(p *daemonProject, _ daemonParams) (any, error) {
+	return detectOutput{p.stack, p.warnings}, nil
+}
+
+func (d *daemon) resolve(p *daemonProject, _ daemonParams) (any, error) {
+	agents := []string{}
+	for _, af := range buildAgentRulesFromDetection(p.stack) {
+		agents = append(agents, af.ID)
+	}
+	return map[string]any{
+		"general":    buildGeneralRulesFromDetection(p.stack),
+		"categories": categoryRuleIDs(p.stack),
+		"agents":     agents,
+	}, nil
+}
+
+type daemonFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content,omitempty"`
+	Status  string `json:"status,omitempty"`
+}
+
+func (d *daemon) render(p *daemonProject, params daemonParams) (any, error) {
+	files, err := d.rendered(p)
+	if err != nil {
+		return nil, err
+	}
+	var out []daemonFile
+	for _, f := range files {
+		if params.File == "" || filepath.ToSlash(filepath.Clean(params.File)) == f.Path {
+			out = append(out, daemonFile{Path: f.Path, Content: f.Content})
+		}
+	}
+	if params.File != "" && len(out) == 0 {
+		return nil, rpc.InvalidParams("'%s' is not a generated file", params.File)
+	}
+	return map[string]any{"files": out, "warnings": p.warnings}, nil
+}
+
+func (d *daemon) validate(p *daemonProject, _ daemonParams) (any, error) {
+	files, err := d.rendered(p)
+	if err != nil {
+		return nil, err
+	}
+	upToDate := true
+	out := []daemonFile{}
+	for _, f := range files {
+		status := compareFileStatus(f.Path, f.Content)
+		if status != statusUpToDate {
+			upToDate = false
+		}
+		out = append(out, daemonFile{Path: f.Path, Status: status.String()})
+	}
+	return map[string]any{"up_to_date": upToDate, "files": out}, nil
+}
+
+// invalidate drops the cache of one project, or of all without a path.
+func (d *daemon) invalidate(raw json.RawMessage) (any, error) {
+	var params daemonParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, rpc.InvalidParams("%v", err)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if params.Path == "" {
+		d.projects = map[string]*daemonProject{}
+	} else {
+		delete(d.projects, d.root(params.Path))
+	}
+	return map[string]any{}, nil
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dashboardTemplate is the self-contained HTML page written by batch --dashboard-out.
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>AI instructions compliance</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #1f2328; }
+h1 { margin-bottom: .25rem; }
+.meta { color: #59636e; margin-top: 0; }
+.cards { display: flex; gap: 1rem; margin: 1.5rem 0; }
+.card { border: 1px solid #d1d9e0; border-radius: 6px; padding: 1rem 1.5rem; min-width: 8rem; }
+.card strong { display: block; font-size: 2rem; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2rem; }
+th, td { border-bottom: 1px solid #d1d9e0; padding: .5rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.ok { color: #1a7f37; font-weight: 600; }
+.bad { color: #d1242f; font-weight: 600; }
+ul { margin: 0; padding-left: 1.2rem; }
+</style>
+</head>
+<body>
+<h1>AI instructions compliance</h1>
+<p class="meta">Generated {{.Generated}} by ai-instructions {{.Version}}</p>
+
+<div class="cards">
+<div class="card"><strong>{{.Total}}</strong>repositories</div>
+<div class="card"><strong class="ok">{{.UpToDate}}</strong>up to date</div>
+<div class="card"><strong class="bad">{{.Outdated}}</strong>outdated</div>
+<div class="card"><strong class="bad">{{.Errors}}</strong>errors</div>
+</div>
+
+<h2>Repositories</h2>
+<table>
+<tr><th>Repository</th><th>Status</th><th>Stack</th><th>Files</th><th>Uncovered</th></tr>
+{{range .Repos}}<tr>
+<td>{{.Path}}</td>
+<td>{{if .Err}}<span class="bad">error</span><br>{{.Err}}{{else if .UpToDate}}<span class="ok">up to date</span>{{else}}<span class="bad">outdated</span>{{end}}</td>
+<td>{{.Stack}}</td>
+<td><ul>{{range .Files}}<li>{{.Path}}: {{if .OK}}<span class="ok">{{.Status}}</span>{{else}}<span class="bad">{{.Status}}</span>{{end}}</li>{{end}}</ul></td>
+<td>{{range .Uncovered}}{{.}} {{end}}</td>
+</tr>
+{{end}}</table>
+
+<h2>Rule coverage</h2>
+<table>
+<tr><th>Rule</th><th>Repositories</th></tr>
+{{range .Coverage}}<tr><td>{{.Rule}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>
+
+{{if .Uncovered}}<h2>Technologies without rules</h2>
+<table>
+<tr><th>Technology</th><th>Repositories</th></tr>
+{{range .Uncovered}}<tr><td>{{.Rule}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>{{end}}
+</body>
+</html>
+`))
+
+type dashboardCount struct {
+	Rule  string
+	Count int
+}
+
+// renderDashboard renders the batch results as a static HTML page.
+func renderDashboard(reports []repoReport) string {
+	data := struct {
+		Generated, Version                string
+		Total, UpToDate, Outdated, Errors int
+		Repos                             []repoReport
+		Coverage, Uncovered               []dashboardCount
+	}{
+		Generated: time.Now().Format("2006-01-02 15:04 MST"),
+		Version:   version,
+		Total:     len(reports),
+		Repos:     reports,
+	}
+
+	rulesUsed := map[string]int{}
+	uncovered := map[string]int{}
+	for _, r := range reports {
+		switch {
+		case r.Err != "":
+			data.Errors++
+		case r.UpToDate():
+			data.UpToDate++
+		default:
+			data.Outdated++
+		}
+		for _, id := range r.Rules {
+			rulesUsed[id]++
+		}
+		for _, tech := range r.Uncovered {
+			uncovered[tech]++
+		}
+	}
+	data.Coverage = sortedCounts(rulesUsed)
+	data.Uncovered = sortedCounts(uncovered)
+
+	var b strings.Builder
+	if err := dashboardTemplate.Execute(&b, data); err != nil {
+		return "<pre>" + template.HTMLEscapeString(err.Error()) + "</pre>"
+	}
+	return b.String()
+}
+
+// sortedCounts orders counts by count (descending), then name.
+func sortedCounts(m map[string]int) []dashboardCount {
+	var out []dashboardCount
+	for k, v := range m {
+		out = append(out, dashboardCount{Rule: k, Count: v})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Rule < out[j].Rule
+	})
+	return out
+}
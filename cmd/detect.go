@@ -1,37 +1,92 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/warnings"
 )
 
+var (
+	flagDetectJSON  bool
+	flagDetectTrace string
+)
+
+// detectOutput is the JSON printed by detect --json. Warnings are included
+// (and ignored by render --stack).
+type detectOutput struct {
+	*detect.DetectedStack
+	Warnings []warnings.Warning `json:"warnings,omitempty"`
+}
+
 var detectCmd = &cobra.Command{
-	Use:   "detect",
-	Short: "Detect project stack from composer.json and package.json",
+	Use:     "detect",
+	Short:   "Detect project stack from composer.json, package.json, go.mod, Python manifests, Gemfile and build files",
+	GroupID: groupIntrospection,
+	Example: "  ai-instructions detect\n" +
+		"  ai-instructions detect --json\n" +
+		"\n" +
+		"  # Explain why a version was (not) picked\n" +
+		"  ai-instructions detect --trace detect-trace.json",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagDetectTrace != "" {
+			detect.StartTrace()
+		}
 		stack, err := detect.DetectStack(".")
+		if flagDetectTrace != "" {
+			if err := writeDetectTrace(flagDetectTrace, stack, detect.StopTrace()); err != nil {
+				return err
+			}
+		}
 		if err != nil {
-			return err
+			return fmt.Errorf("stack detection failed: %w", err)
+		}
+
+		if flagDetectJSON {
+			out, err := json.MarshalIndent(detectOutput{stack, warnings.List()}, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
 		}
 
 		fmt.Println("Detected stack:")
-		if stack.PHP != "" {
-			fmt.Printf("- PHP: %s\n", stack.PHP)
+		for _, line := range stackLines(stack, false) {
+			fmt.Printf("- %s\n", line)
 		}
-		if stack.Laravel != "" {
-			fmt.Printf("- Laravel: %s\n", stack.Laravel)
+		if len(stack.Languages) > 0 {
+			fmt.Printf("- Languages: %s\n", languageSummary(stack.Languages, 0))
 		}
-		if stack.Nuxt != "" {
-			fmt.Printf("- Nuxt: %s\n", stack.Nuxt)
+		if stack.Hooks != nil {
+			for _, hook := range sortedKeys(stack.Hooks.Husky) {
+				fmt.Printf("- Git hook (husky): %s\n", hook)
+			}
+			if len(stack.Hooks.LintStaged) > 0 || stack.Hooks.LintStagedConfig != "" {
+				fmt.Println("- Git hook: lint-staged")
+			}
 		}
-		if stack.Vue != "" {
-			fmt.Printf("- Vue: %s\n", stack.Vue)
+		if stack.I18n != nil {
+			fmt.Printf("- i18n: %s", strings.Join(stack.I18n.Libraries, ", "))
+			if len(stack.I18n.Locales) > 0 {
+				fmt.Printf(" (locales: %s)", strings.Join(stack.I18n.Locales, ", "))
+			}
+			fmt.Println()
 		}
-		if stack.NuxtUI != "" {
-			fmt.Printf("- Nuxt UI: %s\n", stack.NuxtUI)
+		if c := stack.Commits; c != nil {
+			var tools []string
+			if c.Commitlint != "" {
+				tools = append(tools, "commitlint ("+c.Commitlint+")")
+			}
+			if c.Release != "" {
+				tools = append(tools, c.Release)
+			}
+			fmt.Printf("- Commit conventions: %s\n", strings.Join(tools, ", "))
 		}
 
 		return nil
@@ -40,4 +95,34 @@ var detectCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(detectCmd)
+
+	detectCmd.Flags().BoolVar(
+		&flagDetectJSON,
+		"json",
+		false,
+		"Print the detected stack as JSON (the input format of render --stack)",
+	)
+
+	detectCmd.Flags().StringVar(
+		&flagDetectTrace,
+		"trace",
+		"",
+		"Write a JSON log of every file examined and every candidate value accepted or rejected to this file",
+	)
+}
+
+// writeDetectTrace writes the detection decision log (and the resulting stack) as JSON.
+func writeDetectTrace(path string, stack *detect.DetectedStack, events []detect.TraceEvent) error {
+	out, err := json.MarshalIndent(struct {
+		Stack  *detect.DetectedStack `json:"stack"`
+		Events []detect.TraceEvent   `json:"events"`
+	}{stack, events}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileWithDirs(path, append(out, '\n')); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Detection trace (%d steps) written to %s\n", len(events), path)
+	return nil
 }
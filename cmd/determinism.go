@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/diff"
+)
+
+// flagDeterminism is how often validate renders the expected files to check
+// that generation is deterministic (0 disables the check).
+var flagDeterminism int
+
+// defaultDeterminismRuns is used for a bare --determinism.
+const defaultDeterminismRuns = 3
+
+func init() {
+	validateCmd.Flags().IntVar(
+		&flagDeterminism,
+		"determinism",
+		0,
+		fmt.Sprintf("Also run detection and generation N times in memory (--determinism=N, %d without a value) and fail if the outputs differ", defaultDeterminismRuns),
+	)
+	validateCmd.Flags().Lookup("determinism").NoOptDefVal = fmt.Sprint(defaultDeterminismRuns)
+}
+
+// checkDeterminism renders the expected files again until there are runs
+// renderings in total and fails on the first one that differs from want,
+// showing the difference. Map iteration order and timestamps in new
+// features would otherwise only surface as flaky validate runs downstream.
+func checkDeterminism(want []renderedFile, runs int) error {
+	for run := 2; run <= runs; run++ {
+		stack, err := detect.DetectStack(".")
+		if err != nil {
+			return fmt.Errorf("stack detection failed: %w", err)
+		}
+		if err := applyStackOverrides(stack); err != nil {
+			return err
+		}
+		got, err := buildExpectedFiles(stack)
+		if err != nil {
+			return err
+		}
+		if msg := filesDifference(want, got); msg != "" {
+			return fmt.Errorf("generation is not deterministic: run %d of %d differs from run 1: %s", run, runs, msg)
+		}
+	}
+	fmt.Printf("Determinism: %d runs produced identical output.\n", runs)
+	return nil
+}
+
+// filesDifference describes the first difference between two renderings,
+// or returns "" when they are identical.
+func filesDifference(a, b []renderedFile) string {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		switch {
+		case i >= len(a):
+			return fmt.Sprintf("unexpected extra file '%s'", b[i].Path)
+		case i >= len(b):
+			return fmt.Sprintf("file '%s' is missing", a[i].Path)
+		case a[i].Path != b[i].Path:
+			return fmt.Sprintf("file order differs ('%s' instead of '%s')", b[i].Path, a[i].Path)
+		case a[i].Content != b[i].Content:
+			return fmt.Sprintf("'%s' differs:\n%s", a[i].Path, diff.Unified(a[i].Path, a[i].Path, a[i].Content, b[i].Content, 3))
+		}
+	}
+	return ""
+}
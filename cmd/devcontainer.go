@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/jsonc"
+	"github.com/cego/ai-instructions/internal/warnings"
+)
+
+// devcontainerPath is the dev container configuration the target patches.
+const devcontainerPath = ".devcontainer/devcontainer.json"
+
+// devcontainerImage is used when the project has no dev container yet; it is
+// the image Codespaces falls back to without configuration.
+const devcontainerImage = "mcr.microsoft.com/devcontainers/universal:2"
+
+// copilotExtensions are added to the dev container's VS Code extensions.
+var copilotExtensions = []string{"GitHub.copilot", "GitHub.copilot-chat"}
+
+// copilotInstructionSettings are the VS Code settings that load the files of
+// the category targets (see targets).
+var copilotInstructionSettings = []struct {
+	Category string
+	Target   string
+	Path     string
+	Setting  string
+}{
+	{categoryReview, "copilot-review", ".github/copilot-review-instructions.md", "github.copilot.chat.reviewSelection.instructions"},
+	{categoryCommitMessage, "copilot-commit", ".github/copilot-commit-message-instructions.md", "github.copilot.chat.commitMessageGeneration.instructions"},
+	{categoryPullRequest, "copilot-pr", ".github/copilot-pull-request-description-instructions.md", "github.copilot.chat.pullRequestDescriptionGeneration.instructions"},
+}
+
+// buildDevcontainerFiles patches .devcontainer/devcontainer.json (or creates
+// one on the default Codespaces image) so that Copilot in the dev container
+// loads the generated instruction files of the selected targets. Other
+// members are kept in order. A file with comments is not patched, since they
+// would be lost; the settings to add by hand are printed instead.
+func buildDevcontainerFiles(stack *detect.DetectedStack, selected []target) ([]renderedFile, error) {
+	config := jsonc.NewObject()
+	indent := "\t"
+	commented := false
+	data, err := os.ReadFile(devcontainerPath)
+	switch {
+	case err == nil:
+		if config, err = jsonc.ParseObject(data); err != nil {
+			return nil, &detect.DetectError{Detector: "devcontainer", Path: devcontainerPath, Malformed: true, Err: err}
+		}
+		commented = !bytes.Equal(jsonc.Standardize(data), data)
+		indent = detectIndent(data)
+	case os.IsNotExist(err):
+		config.Set("image", devcontainerImage)
+	default:
+		return nil, err
+	}
+	if commented {
+		// Only the members this target adds
+		config = jsonc.NewObject()
+	}
+
+	vscode := config.Object("customizations").Object("vscode")
+	extensions, _ := vscode.Get("extensions")
+	list, _ := extensions.([]any)
+	for _, ext := range copilotExtensions {
+		if !containsFold(list, ext) {
+			list = append(list, ext)
+		}
+	}
+	vscode.Set("extensions", list)
+
+	settings := vscode.Object("settings")
+	settings.Set("github.copilot.chat.codeGeneration.useInstructionFiles", true)
+	categoryIDs := categoryRuleIDs(stack)
+	for _, s := range copilotInstructionSettings {
+		// Only files this run generates
+		if len(categoryIDs[s.Category]) == 0 || !hasTarget(selected, s.Target) {
+			continue
+		}
+		file := jsonc.NewObject()
+		file.Set("file", s.Path)
+		settings.Set(s.Setting, []any{file})
+	}
+
+	out, err := config.Indent(indent)
+	if err != nil {
+		return nil, err
+	}
+	if commented {
+		warnings.Add("devcontainer", "%s has comments, which patching would drop; it is left unchanged. Merge these members into it:\n%s", devcontainerPath, out)
+		return nil, nil
+	}
+	return []renderedFile{{Label: "DEVCONTAINER", Path: filepath.ToSlash(devcontainerPath), Content: string(out)}}, nil
+}
+
+// detectIndent returns the indentation of the first indented line of data.
+func detectIndent(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		if trimmed := strings.TrimLeft(line, " \t"); trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "\t"
+}
+
+// containsFold reports whether list holds the string s, ignoring case
+// (extension IDs are case-insensitive).
+func containsFold(list []any, s string) bool {
+	for _, v := range list {
+		if str, ok := v.(string); ok && strings.EqualFold(str, s) {
+			return true
+		}
+	}
+	return false
+}
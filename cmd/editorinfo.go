@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/cego/ai-instructions/internal/config"
+)
+
+// editorInfoVersion is the version of the editor-info descriptor itself.
+// Bump it when a field is removed or changes meaning; adding fields is
+// compatible.
+const editorInfoVersion = 1
+
+// schemaVersions are the versions of the JSON documents the CLI reads or
+// emits (see the schema command), bumped on incompatible changes, so that an
+// extension can tell whether it understands them.
+var schemaVersions = map[string]int{
+	"config":   1,
+	"stack":    1,
+	"manifest": 1,
+	"model":    1,
+	"policy":   1,
+	"daemon":   1,
+}
+
+// editorInfo is the descriptor a companion editor extension reads to discover
+// what this CLI supports and where it writes, instead of parsing --help.
+type editorInfo struct {
+	DescriptorVersion int              `json:"descriptor_version"`
+	Version           string           `json:"version"`
+	Config            string           `json:"config"`
+	Schemas           map[string]int   `json:"schemas"`
+	Commands          []editorCommand  `json:"commands"`
+	Outputs           []editorOutput   `json:"outputs"`
+	Daemon            editorDaemonInfo `json:"daemon"`
+}
+
+// editorCommand lists a command and its flags, so that an extension can check
+// for a capability (e.g. generate --paths) before using it.
+type editorCommand struct {
+	Name  string   `json:"name"`
+	Flags []string `json:"flags,omitempty"`
+}
+
+type editorOutput struct {
+	Target string `json:"target"`
+	Path   string `json:"path"`
+	// Selected reports whether generate writes the target for this project
+	// (from the config or the defaults).
+	Selected bool `json:"selected"`
+	// Directory is set for targets that write several files below Path.
+	Directory bool `json:"directory,omitempty"`
+}
+
+type editorDaemonInfo struct {
+	Socket   string   `json:"socket"`
+	Protocol string   `json:"protocol"`
+	Methods  []string `json:"methods"`
+}
+
+var editorInfoCmd = &cobra.Command{
+	Use:     "editor-info",
+	Short:   "Print a JSON descriptor of capabilities, output paths and the daemon socket for editor extensions",
+	GroupID: groupIntrospection,
+	Example: "  ai-instructions editor-info",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := collectEditorInfo()
+		if err != nil {
+			return err
+		}
+		out, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editorInfoCmd)
+}
+
+func collectEditorInfo() (editorInfo, error) {
+	info := editorInfo{
+		DescriptorVersion: editorInfoVersion,
+		Version:           cliVersion(),
+		Config:            flagConfig,
+		Schemas:           schemaVersions,
+		Daemon: editorDaemonInfo{
+			Socket:   daemonSocketPath(),
+			Protocol: "jsonrpc-2.0",
+			Methods:  slices.Sorted(maps.Keys((&daemon{}).methods())),
+		},
+	}
+	if flagConfig == config.Stdin {
+		info.Config = ""
+	}
+
+	info.Commands = editorCommands(rootCmd, nil)
+
+	selected, err := selectedTargets(nil)
+	if err != nil {
+		return info, err
+	}
+	for _, t := range targets {
+		info.Outputs = append(info.Outputs, editorOutput{
+			Target:    t.Name,
+			Path:      t.Path,
+			Selected:  hasTarget(selected, t.Name),
+			Directory: t.Files != nil && path.Ext(t.Path) == "",
+		})
+	}
+	return info, nil
+}
+
+// editorCommands lists the available subcommands of parent (recursively, as
+// "rules lint") and their own flags.
+func editorCommands(parent *cobra.Command, list []editorCommand) []editorCommand {
+	for _, c := range parent.Commands() {
+		if !c.IsAvailableCommand() {
+			continue
+		}
+		command := editorCommand{Name: strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" ")}
+		c.LocalFlags().VisitAll(func(f *pflag.Flag) {
+			if !f.Hidden && f.Name != "help" {
+				command.Flags = append(command.Flags, f.Name)
+			}
+		})
+		list = editorCommands(c, append(list, command))
+	}
+	return list
+}
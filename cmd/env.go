@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/envvars"
+)
+
+var flagIncludeEnv bool
+
+// buildEnvSection lists the env variable names used in dir (never their
+// values). It is empty unless --include-env is set.
+func buildEnvSection(dir string) string {
+	if !flagIncludeEnv {
+		return ""
+	}
+
+	sources, err := envvars.Scan(dir)
+	if err != nil || len(sources) == 0 {
+		return ""
+	}
+
+	// Files listed in .aiignore are not mentioned
+	ignore := loadAIIgnore(dir)
+	var listed []envvars.Source
+	for _, src := range sources {
+		if !ignore.Match(src.Path, false) {
+			listed = append(listed, src)
+		}
+	}
+	if len(listed) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Configuration & environment variables\n\n")
+	b.WriteString("Use these existing variables instead of inventing new ones. " +
+		"When a new variable is really needed, add it to the example env file and the config.\n")
+	for _, src := range listed {
+		fmt.Fprintf(&b, "\n- `%s`: %s", src.Path, codeList(src.Names))
+	}
+	return b.String()
+}
+
+// addIncludeEnvFlag registers --include-env on a command that renders instructions.
+func addIncludeEnvFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flagIncludeEnv,
+		"include-env",
+		false,
+		"Add a section listing env variable names from .env.example and config files (values are never included)",
+	)
+}
+
+func init() {
+	addIncludeEnvFlag(generateCmd)
+	addIncludeEnvFlag(validateCmd)
+	addIncludeEnvFlag(exportCmd)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/rules"
+)
+
+// flagExperiments selects rule variants, overriding the config's experiments.
+var flagExperiments []string
+
+// addExperimentFlag registers --experiment on a command that renders instructions.
+func addExperimentFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(
+		&flagExperiments,
+		"experiment",
+		nil,
+		"Use rule variants for the experiment(s) (rules/<id>@<experiment>.md) where they exist, overriding the config",
+	)
+}
+
+func init() {
+	addExperimentFlag(generateCmd)
+	addExperimentFlag(validateCmd)
+	addExperimentFlag(exportCmd)
+	addExperimentFlag(renderCmd)
+}
+
+// activeExperiments returns --experiment, or the config's experiments when unset.
+func activeExperiments() []string {
+	if len(flagExperiments) > 0 {
+		return flagExperiments
+	}
+	return cfg.Experiments
+}
+
+// variantMarker identifies an experiment variant in the output so results can
+// be attributed to it; base rules get no marker.
+func variantMarker(r *rules.Rule) string {
+	if r.Variant == "" {
+		return ""
+	}
+	return fmt.Sprintf("<!-- ai-instructions: rules/%s%s%s.md (experiment %s) -->\n\n", r.ID, rules.VariantSeparator, r.Variant, r.Variant)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cego/ai-instructions/rules"
+)
+
+// now is the date expiry is evaluated against.
+var now = time.Now
+
+// expiryMarkerRe matches the marker written before temporary content.
+var expiryMarkerRe = regexp.MustCompile(`<!-- ai-instructions: temporary (.+?), expires (\d{4}-\d{2}-\d{2}) -->`)
+
+// filterExpired drops rules whose `expires:` date has passed.
+func filterExpired(ids []string) []string {
+	var out []string
+	for _, id := range ids {
+		if r, err := rules.Load(id); err == nil && r.Meta.Expired(now()) {
+			continue
+		}
+		out = append(out, id)
+	}
+	return out
+}
+
+// expiryMarker labels temporary content in the output, so validate can
+// report it once it expired.
+func expiryMarker(id string) string {
+	r, err := rules.Load(id)
+	if err != nil || r.Meta.Expires == "" {
+		return ""
+	}
+	return temporaryMarker("rules/"+id+".md", r.Meta.Expires)
+}
+
+func temporaryMarker(source, expires string) string {
+	return fmt.Sprintf("<!-- ai-instructions: temporary %s, expires %s -->\n\n", source, expires)
+}
+
+// configSections renders the config sections that have not expired.
+func configSections() string {
+	var sections []string
+	for _, s := range cfg.Sections {
+		if rules.Expired(s.Expires, now()) {
+			continue
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "## %s\n\n", strings.TrimSpace(s.Title))
+		if s.Expires != "" {
+			b.WriteString(temporaryMarker("config section '"+strings.TrimSpace(s.Title)+"'", s.Expires))
+		}
+		b.WriteString(strings.TrimSpace(s.Body))
+		sections = append(sections, b.String())
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// expiredContent describes the temporary content in content whose expiry
+// date has passed.
+func expiredContent(content string) []string {
+	var out []string
+	for _, m := range expiryMarkerRe.FindAllStringSubmatch(content, -1) {
+		if rules.Expired(m[2], now()) {
+			out = append(out, fmt.Sprintf("%s (expired %s)", m[1], m[2]))
+		}
+	}
+	return out
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/rules"
+)
+
+var explainCmd = &cobra.Command{
+	Use:     "explain <name>[/<version>]",
+	Short:   "Show how the rules of a technology version are resolved: the files tried, which exist and the final order",
+	GroupID: groupIntrospection,
+	Example: "  ai-instructions explain php/8.2\n" +
+		"  ai-instructions explain laravel/^11.0\n" +
+		"  ai-instructions explain django/5.0",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, version := splitExplainTarget(args[0])
+		dir, label := name, deriveRuleLabel(name)
+		for _, k := range detect.Known {
+			if strings.EqualFold(k, name) {
+				dir, label = ruleDir(k), stackEntryFor(k).Label
+			}
+		}
+		all, err := rules.ByPrefix(dir + "/")
+		if err != nil || len(all) == 0 {
+			return fmt.Errorf("no rules below '%s'", dir)
+		}
+		if version == "" {
+			if hasVersionRules(dir) {
+				return fmt.Errorf("%s has version-specific rules; give a version, e.g. %s/8.2", dir, name)
+			}
+			// Like an unversioned technology, marked by the file it was found in
+			version = "unversioned"
+		}
+		major, minor := versionParts(version)
+
+		fmt.Printf("Rules of %s %s (rules/%s", label, version, dir)
+		if major != "" {
+			fmt.Printf(", major %s", major)
+			if minor != "" {
+				fmt.Printf(", minor %s", minor)
+			}
+		}
+		fmt.Println(")")
+
+		// The candidates mirror addRuleFilesFor and addAgentFor, which pick the final rules
+		var general, agent []string
+		general = append(general, dir+"/general")
+		if major != "" && minor != "" {
+			general = append(general, dir+"/"+major+"."+minor+"/general")
+			agent = append(agent, dir+"/"+major+"."+minor+"/agent")
+		}
+		if major != "" {
+			general = append(general, dir+"/"+major+"/general")
+			agent = append(agent, dir+"/"+major+"/agent")
+		}
+		agent = append(agent, dir+"/agent")
+
+		var generalIDs []string
+		addRulesFor(&generalIDs, dir, version)
+		generalIDs = filterExpired(filterAudience(generalIDs, false))
+		var agentFiles []agentFile
+		addAgentFor(&agentFiles, label, dir, version)
+
+		fmt.Println("\nGeneral rules (general.md): every existing file is included, in this order")
+		if err := printCandidates(general, generalIDs); err != nil {
+			return err
+		}
+		fmt.Println("\nAgent rules (agent.md): the most specific existing file is used")
+		var agentIDs []string
+		for _, af := range agentFiles {
+			agentIDs = append(agentIDs, af.ID)
+		}
+		if err := printCandidates(agent, agentIDs); err != nil {
+			return err
+		}
+
+		fmt.Println("\nFinal order:")
+		fmt.Printf("- instructions: %s\n", orNone(generalIDs))
+		if len(agentFiles) > 0 {
+			fmt.Printf("- AGENTS.md section %q: %s\n", agentFiles[0].Label, agentFiles[0].ID)
+		} else {
+			fmt.Println("- AGENTS.md section: none")
+		}
+		chained := append(append([]string{}, general...), agent...)
+		for _, c := range categories {
+			files := []string{c}
+			if c == categoryReview {
+				files = []string{"general", c}
+			}
+			var ids []string
+			for _, file := range files {
+				addRuleFilesFor(&ids, dir, version, file)
+			}
+			ids = filterExpired(filterAudience(ids, c == categoryReview))
+			if c == categoryReview {
+				// general.md files count as review rules only with audience: reviewer
+				ids = removeIDs(ids, generalIDs)
+			}
+			fmt.Printf("- %s: %s\n", c, orNone(ids))
+			chained = append(chained, ids...)
+		}
+
+		// Cross-cutting rules (e.g. php/plain/general) and upgrade guides are
+		// added by their conditions or by other commands, not by the chain
+		var other []string
+		for _, id := range all {
+			if !stringIn(id, chained) {
+				other = append(other, id)
+			}
+		}
+		if len(other) > 0 {
+			fmt.Printf("\nOther rules below rules/%s, outside this chain:\n", dir)
+			if err := printCandidates(other, nil); err != nil {
+				return err
+			}
+		}
+		fmt.Println("\nRules with a `when:` condition are included only when it holds for the detected stack.")
+		return nil
+	},
+}
+
+// splitExplainTarget splits php/8.2 into the name and the version; the
+// version is the last path element when it parses as one.
+func splitExplainTarget(arg string) (name, version string) {
+	arg = strings.Trim(arg, "/ ")
+	if i := strings.LastIndex(arg, "/"); i > 0 && normalizeVersion(arg[i+1:]) != "" {
+		return arg[:i], arg[i+1:]
+	}
+	return arg, ""
+}
+
+// printCandidates lists the rule IDs tried, whether each exists, whether it is
+// used and what else decides about it.
+func printCandidates(candidates, used []string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, id := range candidates {
+		status := "not found"
+		var notes []string
+		if ruleExists(id) {
+			status = "found"
+			if stringIn(id, used) {
+				status = "used"
+			}
+			if rules.IsLocal(id) {
+				notes = append(notes, "local")
+			}
+			if r, err := rules.Load(id); err == nil {
+				if r.Meta.When != "" {
+					notes = append(notes, "when: "+r.Meta.When)
+				}
+				if r.Meta.Optional {
+					notes = append(notes, "optional")
+				}
+				if r.Meta.IsReviewer() {
+					notes = append(notes, "audience: reviewer")
+				}
+				if r.Meta.Expires != "" {
+					notes = append(notes, "expires: "+r.Meta.Expires)
+				}
+			}
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", id, status, strings.Join(notes, ", "))
+	}
+	return w.Flush()
+}
+
+func stringIn(s string, list []string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// removeIDs returns ids without those in remove.
+func removeIDs(ids, remove []string) []string {
+	var out []string
+	for _, id := range ids {
+		if !stringIn(id, remove) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func orNone(ids []string) string {
+	if len(ids) == 0 {
+		return "none"
+	}
+	return strings.Join(ids, " → ")
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+}
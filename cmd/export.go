@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/markdown"
+)
+
+var (
+	flagExportFormat   string
+	flagExportMaxChars int
+)
+
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	Short:   "Export the instructions in alternative formats (e.g. a system prompt for chat workflows)",
+	GroupID: groupGeneration,
+	Example: "  ai-instructions export --format prompt --max-chars 8000",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagExportFormat != "prompt" {
+			return fmt.Errorf("unsupported export format '%s' (available: prompt)", flagExportFormat)
+		}
+
+		var ids []string
+		var stack *detect.DetectedStack
+		if anyRuleFlagsSet() {
+			ids = buildGeneralRulesFromFlags()
+		} else {
+			var err error
+			stack, err = detect.DetectStack(".")
+			if err != nil {
+				return err
+			}
+			ids = buildGeneralRulesFromDetection(stack)
+		}
+		if len(ids) == 0 {
+			return fmt.Errorf("no rule files selected – nothing to export")
+		}
+
+		content, err := loadAndMergeRules(ids)
+		if err != nil {
+			return err
+		}
+		if detected := buildDetectedSections(".", stack); detected != "" {
+			content = detected + "\n\n" + content
+		}
+
+		prompt, err := guardSecrets("prompt", buildPrompt(content, flagExportMaxChars))
+		if err != nil {
+			return err
+		}
+		fmt.Println(prompt)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(
+		&flagExportFormat,
+		"format",
+		"prompt",
+		"Export format (prompt: plain-text block ready to paste as a system prompt)",
+	)
+
+	exportCmd.Flags().IntVar(
+		&flagExportMaxChars,
+		"max-chars",
+		12000,
+		"Maximum size of the exported prompt in characters (0 for no limit)",
+	)
+
+	exportCmd.Flags().StringSliceVar(
+		&flagRules,
+		"rule",
+		nil,
+		"Rule set(s) to include instead of detection, e.g. 'laravel', 'nuxt'",
+	)
+}
+
+const promptTruncatedNote = "\n\n[Instructions truncated to fit the size limit.]"
+
+// buildPrompt flattens the instructions into a plain-text system prompt,
+// cutting at a line boundary when it exceeds maxChars.
+func buildPrompt(content string, maxChars int) string {
+	text := "Follow these project instructions when writing or reviewing code.\n\n" + markdown.ToPlainText(content)
+
+	if maxChars <= 0 || len([]rune(text)) <= maxChars {
+		return text
+	}
+
+	limit := maxChars - len([]rune(promptTruncatedNote))
+	if limit < 0 {
+		limit = 0
+	}
+	cut := string([]rune(text)[:limit])
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, "\n ") + promptTruncatedNote
+}
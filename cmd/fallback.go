@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+)
+
+// languagePacks are the generic rules offered when detection finds no
+// technology with rules, with the source language (see detect.LanguageShare)
+// that suggests each.
+var languagePacks = []struct{ Rule, Language string }{
+	{"go", "Go"},
+	{"php/plain", "PHP"},
+	{"python", "Python"},
+	{"ruby", "Ruby"},
+	{"typescript", "TypeScript"},
+	{"javascript/plain", "JavaScript"},
+}
+
+// fallbackPacks returns the language packs available in the rules, and those
+// of them the languages of the source files suggest (largest share first).
+func fallbackPacks(stack *detect.DetectedStack) (packs, suggested []string) {
+	languages := map[string]string{}
+	for _, p := range languagePacks {
+		if ruleExists(p.Rule + "/general") {
+			packs = append(packs, p.Rule)
+			languages[p.Language] = p.Rule
+		}
+	}
+	if stack != nil {
+		for _, l := range stack.Languages {
+			if rule, ok := languages[l.Name]; ok {
+				suggested = append(suggested, rule)
+			}
+		}
+	}
+	return packs, suggested
+}
+
+// ruleFlags formats rules as --rule flags.
+func ruleFlags(rules []string) string {
+	flags := make([]string, len(rules))
+	for i, r := range rules {
+		flags[i] = "--rule " + r
+	}
+	return strings.Join(flags, " ")
+}
+
+// chooseFallbackRules keeps a first run in a repository without detected
+// technologies from being a dead end. Interactive runs choose generic
+// language packs, defaulting to those the source files suggest; other runs
+// print the --rule flags to use. It returns the chosen rules (none when the
+// run is not interactive or the user declines).
+func chooseFallbackRules(stack *detect.DetectedStack) ([]string, error) {
+	packs, suggested := fallbackPacks(stack)
+	if len(packs) == 0 {
+		return nil, nil
+	}
+
+	fmt.Println("No supported technology detected.")
+	if !isInteractive() || flagOut == "-" {
+		if len(suggested) > 0 {
+			fmt.Printf("The source files suggest generic language packs; run '%s generate %s'.\n", rootCmd.Name(), ruleFlags(suggested))
+		} else {
+			fmt.Printf("Generic language packs: %s; run '%s generate --rule <pack>'.\n", strings.Join(packs, ", "), rootCmd.Name())
+		}
+		return nil, nil
+	}
+
+	fmt.Println("Choose generic language packs to generate instructions from:")
+	isSuggested := map[string]bool{}
+	for _, s := range suggested {
+		isSuggested[s] = true
+	}
+	for i, p := range packs {
+		line := fmt.Sprintf("  [%d] %s", i+1, p)
+		if isSuggested[p] {
+			line += " (suggested by the source files)"
+		}
+		fmt.Println(line)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		if len(suggested) > 0 {
+			fmt.Printf("Packs (numbers or names, comma-separated; Enter for %s, n for none): ", strings.Join(suggested, ", "))
+		} else {
+			fmt.Print("Packs (numbers or names, comma-separated; n for none): ")
+		}
+		answer, err := reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		switch {
+		case answer == "" && err == nil && len(suggested) > 0:
+			return suggested, nil
+		case answer == "n" || answer == "none":
+			return nil, nil
+		case answer != "":
+			if chosen, ok := parsePackChoice(answer, packs); ok {
+				return chosen, nil
+			}
+			fmt.Println("Unknown pack; enter numbers or names from the list.")
+		}
+		if err != nil {
+			// No answer (e.g. stdin is /dev/null)
+			return nil, nil
+		}
+	}
+}
+
+// parsePackChoice resolves a comma-separated list of pack numbers or names.
+func parsePackChoice(answer string, packs []string) ([]string, bool) {
+	known := map[string]bool{}
+	for _, p := range packs {
+		known[p] = true
+	}
+	var chosen []string
+	for _, item := range strings.Split(answer, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(item); err == nil && n >= 1 && n <= len(packs) {
+			item = packs[n-1]
+		}
+		if !known[item] {
+			return nil, false
+		}
+		chosen = append(chosen, item)
+	}
+	chosen = uniqueTrimmed(chosen)
+	return chosen, len(chosen) > 0
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/format"
+)
+
+// flagFormat is the document format of the generated instruction files.
+var flagFormat string
+
+// addFormatFlag registers --format on a command that writes instruction files.
+func addFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&flagFormat,
+		"format",
+		format.Markdown,
+		"Document format of the instruction files: "+strings.Join(format.Names, ", ")+" (other formats replace the .md extension)",
+	)
+}
+
+func init() {
+	addFormatFlag(generateCmd)
+	addFormatFlag(validateCmd)
+	addFormatFlag(renderCmd)
+}
+
+// formatPath returns the path a markdown output is written to in the
+// selected format.
+func formatPath(path string) string {
+	r, err := format.Lookup(flagFormat)
+	if err != nil || !strings.HasSuffix(path, ".md") {
+		return path
+	}
+	return format.Path(r, path)
+}
+
+// convertFiles renders the markdown outputs in the selected format. Files
+// with front matter (prompt files, chat modes, agents) are read by tools as
+// markdown and keep it; references to renamed files in the others (such as
+// the aider or dev container config) follow the new names.
+func convertFiles(files []renderedFile) ([]renderedFile, error) {
+	r, err := format.Lookup(flagFormat)
+	if err != nil {
+		return nil, err
+	}
+	if r.Ext() == ".md" {
+		return files, nil
+	}
+
+	out := make([]renderedFile, len(files))
+	converted := make([]bool, len(files))
+	renamed := map[string]string{}
+	for i, f := range files {
+		if strings.HasSuffix(f.Path, ".md") && !strings.HasPrefix(f.Content, "---\n") {
+			content, marked := strings.CutSuffix(f.Content, generatedMarker+"\n")
+			content = format.Render(r, content)
+			if marked {
+				content = strings.TrimRight(content, "\n") + "\n\n" + r.Comment(markerText()) + "\n"
+			}
+			renamed[f.Path] = format.Path(r, f.Path)
+			f.Path, f.Content = renamed[f.Path], content
+			converted[i] = true
+		}
+		out[i] = f
+	}
+	if len(renamed) == 0 {
+		return out, nil
+	}
+
+	// Longest paths first, so that apps/web/AGENTS.md is not renamed as AGENTS.md
+	olds := make([]string, 0, len(renamed))
+	for old := range renamed {
+		olds = append(olds, old)
+	}
+	sort.Slice(olds, func(i, j int) bool { return len(olds[i]) > len(olds[j]) })
+	var pairs []string
+	for _, old := range olds {
+		pairs = append(pairs, old, renamed[old])
+	}
+	replacer := strings.NewReplacer(pairs...)
+	for i := range out {
+		if !converted[i] {
+			out[i].Content = replacer.Replace(out[i].Content)
+		}
+	}
+	return out, nil
+}
+
+// markerText is the text of generatedMarker without the comment delimiters.
+func markerText() string {
+	return strings.TrimSuffix(strings.TrimPrefix(generatedMarker, "<!-- "), " -->")
+}
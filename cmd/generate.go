@@ -9,102 +9,281 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/markdown"
+	"github.com/cego/ai-instructions/internal/warnings"
 	"github.com/cego/ai-instructions/rules"
 )
 
 var (
-	flagRules []string
-	flagOut   string
+	flagRules      []string
+	flagOut        string
+	flagCheckLinks bool
+
+	flagAnnotateSources bool
 )
 
 var generateCmd = &cobra.Command{
-	Use:   "generate",
-	Short: "Generate copilot-instructions.md and AGENTS.md based on detected stack or explicit flags",
+	Use:     "generate",
+	Short:   "Generate copilot-instructions.md and AGENTS.md based on detected stack or explicit flags",
+	GroupID: groupGeneration,
+	Example: "  # Detect the stack and write the default targets\n" +
+		"  ai-instructions generate\n" +
+		"\n" +
+		"  # Print every file to stdout instead of writing it\n" +
+		"  ai-instructions generate -o -\n" +
+		"\n" +
+		"  # Explicit rule sets, AGENTS.md only\n" +
+		"  ai-instructions generate --rule laravel/11 --target agents",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		projectRoot := "." // kept for future use (detection only)
-
-		var (
-			generalRuleIDs []string
-			agentRuleIDs   []agentFile
-			stack          *detect.DetectedStack
-			err            error
-		)
-
-		if anyRuleFlagsSet() {
-			// Manual mode
-			generalRuleIDs = buildGeneralRulesFromFlags()
-			agentRuleIDs = buildAgentRulesFromFlags()
-		} else {
-			// Auto mode
-			stack, err = detect.DetectStack(projectRoot)
+		if err := runGenerate(); err != nil {
+			return err
+		}
+		if flagWatch {
+			return watchAndRegenerate(cmd.Context())
+		}
+		return nil
+	},
+}
+
+// runGenerate writes all outputs once, based on detection or explicit --rule flags.
+func runGenerate() error {
+	projectRoot := "." // kept for future use (detection only)
+
+	var (
+		generalRuleIDs []string
+		categoryIDs    map[string][]string
+		agentRuleIDs   []agentFile
+		stack          *detect.DetectedStack
+		err            error
+	)
+
+	if flagPerProject && anyRuleFlagsSet() {
+		return fmt.Errorf("--per-project cannot be combined with --rule")
+	}
+	if len(flagSet) > 0 && anyRuleFlagsSet() {
+		return fmt.Errorf("--set cannot be combined with --rule")
+	}
+	scope, err := resolvePathScope()
+	if err != nil {
+		return err
+	}
+
+	if anyRuleFlagsSet() {
+		// Manual mode
+		generalRuleIDs = buildGeneralRulesFromFlags()
+		categoryIDs = categoryRuleIDs(nil)
+		agentRuleIDs = buildAgentRulesFromFlags()
+	} else {
+		// Auto mode
+		stack, err = detect.DetectStack(projectRoot)
+		if err != nil {
+			return fmt.Errorf("stack detection failed: %w", err)
+		}
+		if err := applyStackOverrides(stack); err != nil {
+			return err
+		}
+		generalRuleIDs = buildGeneralRulesFromDetection(stack)
+		categoryIDs = categoryRuleIDs(stack)
+		agentRuleIDs = buildAgentRulesFromDetection(stack)
+
+		// Nothing detected: fall back to the language packs the user picks
+		if len(generalRuleIDs) == 0 && !flagPerProject {
+			chosen, err := chooseFallbackRules(stack)
 			if err != nil {
 				return err
 			}
-			generalRuleIDs = buildGeneralRulesFromDetection(stack)
-			agentRuleIDs = buildAgentRulesFromDetection(stack)
+			if len(chosen) > 0 {
+				flagRules = chosen
+				generalRuleIDs = buildGeneralRulesFromFlags()
+				categoryIDs = categoryRuleIDs(nil)
+				agentRuleIDs = buildAgentRulesFromFlags()
+				fmt.Printf("Using the language packs; regenerate with '%s generate %s'.\n", rootCmd.Name(), ruleFlags(chosen))
+			}
+		}
+	}
+
+	if err := checkStrict(stack, generalRuleIDs); err != nil {
+		return err
+	}
+
+	// A topic-focused file leaves out untagged rules and the category files
+	if len(activeTags()) > 0 {
+		generalRuleIDs = filterTagged(generalRuleIDs, activeTags())
+		if len(generalRuleIDs) == 0 {
+			return fmt.Errorf("no rules tagged %s apply", strings.Join(activeTags(), ", "))
+		}
+		categoryIDs = map[string][]string{}
+	}
+
+	// Refuse to ship dead references
+	var linkIDs []string
+	linkIDs = append(linkIDs, generalRuleIDs...)
+	for _, c := range categories {
+		linkIDs = append(linkIDs, categoryIDs[c]...)
+	}
+	for _, af := range agentRuleIDs {
+		linkIDs = append(linkIDs, af.ID)
+	}
+	if problems := checkRuleLinks(linkIDs, flagCheckLinks); len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+		return fmt.Errorf("link check failed: %d problem(s)", len(problems))
+	}
+
+	// Generate instruction files (general rules) for every selected target
+	if len(generalRuleIDs) > 0 {
+		content, err := loadAndMergeRules(generalRuleIDs)
+		if err != nil {
+			return err
+		}
+
+		// Prepend stack section in auto-mode
+		var detected string
+		if !anyRuleFlagsSet() {
+			if detected = buildDetectedSections(".", stack); detected != "" {
+				content = detected + "\n\n---\n\n" + content
+			}
+		}
+		if flagEmitModel != "" {
+			if err := writeModel(flagEmitModel, generalRuleIDs, categoryIDs, detected); err != nil {
+				return err
+			}
+			if flagOut != "-" {
+				fmt.Printf("Document model written to %s\n", flagEmitModel)
+			}
+		}
+
+		selected, err := selectedTargets(flagTargets)
+		if err != nil {
+			return err
+		}
+		if len(selected) == 0 {
+			fmt.Println("All targets disabled – nothing to generate.")
+			return nil
+		}
+
+		copilotPath := flagOut
+		if copilotPath == "" || copilotPath == "-" {
+			copilotPath = ".github/copilot-instructions.md"
+		}
+
+		// Assets referenced by rules live next to the copilot output
+		assetsDir := assetsDirFor(copilotPath)
+
+		agentsContent, err := addAgentLevel(content)
+		if err != nil {
+			return err
 		}
 
-		// Generate copilot-instructions.md (general rules)
-		if len(generalRuleIDs) > 0 {
-			content, err := loadAndMergeRules(generalRuleIDs)
+		// Per-project mode: scoped AGENTS.md per subproject, linked from the root
+		var subprojects []subprojectFile
+		if flagPerProject && hasTarget(selected, "agents") {
+			subprojects, err = buildSubprojectFiles(projectRoot, scope)
 			if err != nil {
 				return err
 			}
+			if section := buildSubprojectsSection(subprojects); section != "" {
+				agentsContent += "\n\n---\n\n" + section
+			}
+		}
+
+		categoryContents, err := buildCategoryContents(categoryIDs, stack)
+		if err != nil {
+			return err
+		}
+
+		summary, details, err := buildSummaryContent(selected, generalRuleIDs, detected, assetsDir)
+		if err != nil {
+			return err
+		}
 
-			// Prepend stack section in auto-mode
-			if !anyRuleFlagsSet() {
-				stackSection := buildStackSection(stack)
-				if stackSection != "" {
-					content = stackSection + "\n\n---\n\n" + content
+		files := renderTargets(selected, content, agentsContent, summary, categoryContents, copilotPath, assetsDir)
+		files = append(files, details...)
+		fileTargets, err := renderFileTargets(selected, stack)
+		if err != nil {
+			return err
+		}
+		files = append(files, fileTargets...)
+		files = append(files, renderSubprojects(subprojects, assetsDir)...)
+		outputs, err := buildOutputFiles(stack, generalRuleIDs, agentRuleIDs, assetsDir)
+		if err != nil {
+			return err
+		}
+		files = append(files, outputs...)
+		if files, err = finalizeFiles(files); err != nil {
+			return err
+		}
+
+		// Checked against the render, so output of earlier releases is recognised
+		if flagOut != "-" && hasTarget(selected, "copilot") {
+			path := formatPath(filepath.ToSlash(copilotPath))
+			for _, f := range files {
+				if f.Path != path {
+					continue
+				}
+				merged, err := resolveOverwriteConflict(path, f.Content)
+				if err != nil {
+					return err
+				}
+				if merged {
+					// Render again with the section the config gained
+					flagForce = true
+					return runGenerate()
 				}
 			}
+		}
 
-			outPath := flagOut
-			if outPath == "" {
-				outPath = ".github/copilot-instructions.md"
+		if flagOut == "-" {
+			for i, f := range files {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("=== %s ===\n", f.Path)
+				fmt.Println(f.Content)
 			}
-
-			if outPath == "-" {
-				fmt.Println("=== copilot-instructions.md ===")
-				fmt.Println(content)
-			} else {
-				if err := writeFileWithDirs(outPath, []byte(content)); err != nil {
+		} else {
+			fmt.Println("Generated instructions")
+			if err := writeRenderedFiles(files); err != nil {
+				return err
+			}
+			for _, f := range files {
+				fmt.Printf("%s documentation written to %s\n", f.Label, f.Path)
+			}
+			if flagManifest != "" {
+				if err := writeManifest(flagManifest, files); err != nil {
 					return err
 				}
-				fmt.Printf("Generated instructions\nCOPILOT documentation written to %s\n", outPath)
+				fmt.Printf("Manifest written to %s\n", flagManifest)
 			}
 
-			// Write same content to AGENTS.md (per original behavior)
-			agentsPath := "AGENTS.md"
-			if flagOut == "-" {
-				fmt.Println("\n=== AGENTS.md ===")
-				fmt.Println(content)
-			} else {
-				if err := writeFileWithDirs(agentsPath, []byte(content)); err != nil {
+			all := joinCategoryContents(content, categoryContents)
+			if assets := referencedAssets(all); len(assets) > 0 {
+				if err := writeAssets(all, assetsDir); err != nil {
 					return err
 				}
-				fmt.Printf("AGENTS documentation written to %s\n", agentsPath)
+				fmt.Printf("%d asset(s) written to %s\n", len(assets), assetsDir)
 			}
 		}
+	}
 
-		// Agents content (separate aggregation)
-		if len(agentRuleIDs) > 0 {
-			agentContent := buildAgentContent(agentRuleIDs)
-			if flagOut == "-" {
-				fmt.Println("\n=== (Agents Section) ===")
-				fmt.Println(agentContent)
-			} else {
-				// Append or create AGENTS.md with agent details separated
-				// (Optional enhancement: integrate directly above; kept simple)
-			}
+	// Agents content (separate aggregation)
+	if len(agentRuleIDs) > 0 {
+		agentContent := buildAgentContent(agentRuleIDs)
+		if flagOut == "-" {
+			fmt.Println("\n=== (Agents Section) ===")
+			fmt.Println(agentContent)
+		} else {
+			// Append or create AGENTS.md with agent details separated
+			// (Optional enhancement: integrate directly above; kept simple)
 		}
+	}
 
-		if len(generalRuleIDs) == 0 && len(agentRuleIDs) == 0 {
-			fmt.Println("No rule files selected – nothing to generate.")
-		}
+	if len(generalRuleIDs) == 0 && len(agentRuleIDs) == 0 {
+		fmt.Println("No rule files selected – nothing to generate.")
+	}
 
-		return nil
-	},
+	return nil
 }
 
 func init() {
@@ -124,38 +303,123 @@ func init() {
 		"",
 		"Output path for copilot-instructions.md (default .github/copilot-instructions.md, use '-' for stdout)",
 	)
+
+	addTargetFlags(generateCmd, "generate")
+
+	generateCmd.Flags().BoolVar(
+		&flagForce,
+		"force",
+		false,
+		"Overwrite a hand-written copilot-instructions.md (one without the generation marker) without asking",
+	)
+
+	generateCmd.Flags().BoolVar(
+		&flagWatch,
+		"watch",
+		false,
+		"Keep running and regenerate when local rules, the config or manifests change",
+	)
+
+	generateCmd.Flags().BoolVar(
+		&flagStrict,
+		"strict",
+		false,
+		"Fail listing every missing expected rule (e.g. a detected technology without rules/<name>/general.md)",
+	)
+
+	generateCmd.Flags().BoolVar(
+		&flagCheckLinks,
+		"check-links",
+		false,
+		"Verify external links in the selected rules with HTTP HEAD requests",
+	)
+
+	generateCmd.Flags().BoolVar(
+		&flagInlineAssets,
+		"inline-assets",
+		false,
+		"Inline images referenced by rules as data URIs instead of copying them next to the output",
+	)
+
+	generateCmd.Flags().BoolVar(
+		&flagPerProject,
+		"per-project",
+		false,
+		"Also write an AGENTS.md into each detected subproject, linked from the root AGENTS.md",
+	)
+
+	generateCmd.Flags().BoolVar(
+		&flagAnnotateSources,
+		"annotate-sources",
+		false,
+		"Append an HTML comment after each section naming the rule file(s) it came from",
+	)
 }
 
+// Rule in the local rules directory holding project-specific additions.
+const localGeneralRule = "local/general"
+
 type agentFile struct {
 	Label string
 	ID    string // rule identifier without prefix & extension (e.g. php/8/agent)
 }
 
+// buildDetectedSections returns the sections derived from detection in dir
+// (project identity, code ownership, restricted paths, stack, package
+// management, git hooks, commit conventions, TypeScript, upgrades, env vars, vulnerable
+// dependencies) that precede the merged rules.
+func buildDetectedSections(dir string, stack *detect.DetectedStack) string {
+	var sections []string
+	for _, section := range []string{
+		buildIdentitySection(dir),
+		buildGlossarySection(),
+		buildOwnersSection(dir),
+		buildRestrictedSection(dir),
+		buildGuardrailsSection(),
+		buildStackSection(stack),
+		buildPackageManagementSection(stack),
+		buildGitHooksSection(stack),
+		buildCommitConventionsSection(stack),
+		buildTypeScriptSection(stack),
+		buildI18nSection(stack),
+		buildPerformanceSection(stack),
+		buildUpgradeSection(stack),
+		buildEnvSection(dir),
+		buildAuditSection(dir),
+	} {
+		if section != "" {
+			sections = append(sections, section)
+		}
+	}
+	return strings.Join(sections, "\n\n")
+}
+
 func buildStackSection(stack *detect.DetectedStack) string {
 	if stack == nil {
 		return ""
 	}
 
 	var lines []string
-	if stack.PHP != "" {
-		lines = append(lines, fmt.Sprintf("- PHP: %s", stack.PHP))
-	}
-	if stack.Laravel != "" {
-		lines = append(lines, fmt.Sprintf("- Laravel: %s", stack.Laravel))
-	}
-	if stack.Nuxt != "" {
-		lines = append(lines, fmt.Sprintf("- Nuxt: %s", stack.Nuxt))
-	}
-	if stack.Vue != "" {
-		lines = append(lines, fmt.Sprintf("- Vue: %s", stack.Vue))
-	}
-	if stack.NuxtUI != "" {
-		lines = append(lines, fmt.Sprintf("- Nuxt UI: %s", stack.NuxtUI))
+	for _, line := range stackLines(stack, true) {
+		lines = append(lines, "- "+line)
 	}
 	if len(lines) == 0 {
 		return ""
 	}
-	return "## Stack\n\n" + strings.Join(lines, "\n")
+	if frontends := stack.Frontends(); len(frontends) > 1 {
+		var names []string
+		for _, f := range frontends {
+			names = append(names, stackEntryFor(f.Name).Label)
+		}
+		last := len(names) - 1
+		lines = append(lines, fmt.Sprintf("- Frontend frameworks: %s and %s are used side by side; write new code in the framework of the surrounding module and never mix them in one component",
+			strings.Join(names[:last], ", "), names[last]))
+	}
+	section := "## Stack\n\n" + strings.Join(lines, "\n")
+	if languages := buildLanguagesSubsection(stack); languages != "" {
+		section += "\n\n" + languages
+	}
+	return section
 }
 
 func anyRuleFlagsSet() bool {
@@ -165,45 +429,74 @@ func anyRuleFlagsSet() bool {
 // General rules (general.md)
 func buildGeneralRulesFromDetection(stack *detect.DetectedStack) []string {
 	var ids []string
-	addRulesFor(&ids, "php", stack.PHP)
-	addRulesFor(&ids, "laravel", stack.Laravel)
-	addRulesFor(&ids, "nuxt", stack.Nuxt)
-	addRulesFor(&ids, "vue", stack.Vue)
-	addRulesFor(&ids, "nuxt_ui", stack.NuxtUI)
+	for _, t := range ruleTechnologies(stack) {
+		addRulesFor(&ids, ruleDir(t.Name), t.Version)
+	}
+
+	ids = filterApplicable(ids, stack)
+	ids = append(ids, conditionalRules(stack, "/general", ids)...)
+	ids = filterOptional(ids)
+	ids = filterAudience(ids, false)
+	ids = append(ids, branchRules(ids)...)
+	ids = filterExpired(ids)
+
+	// Project-local additions always come last
+	addIfExists(&ids, localGeneralRule)
 	return ids
 }
 
 func addRulesFor(ids *[]string, name, version string) {
+	addRuleFilesFor(ids, name, version, "general")
+}
+
+// addRuleFilesFor adds name/<file>, name/<major>.<minor>/<file> and name/<major>/<file> when they exist.
+func addRuleFilesFor(ids *[]string, name, version, file string) {
 	if version == "" {
 		return
 	}
 
-	// Base general
-	addIfExists(ids, name+"/general")
+	// Base
+	addIfExists(ids, name+"/"+file)
 
-	norm := normalizeVersion(version)
-	if norm == "" {
+	if normalizeVersion(version) == "" {
+		if hasVersionRules(name) {
+			warnings.Add("rules", "could not normalize %s version '%s'; using base rules only", name, version)
+		}
 		return
 	}
+	major, minor := versionParts(version)
 
-	parts := strings.Split(norm, ".")
-	major := parts[0]
-	minor := ""
-	if len(parts) > 1 {
-		minor = parts[1]
-	}
-
-	// major.minor/general
+	// major.minor/<file>
 	if major != "" && minor != "" {
-		addIfExists(ids, name+"/"+major+"."+minor+"/general")
+		id := name + "/" + major + "." + minor + "/" + file
+		if ruleExists(id) {
+			*ids = append(*ids, id)
+		} else if siblings, _ := rules.Glob(name + "/" + major + ".*/" + file); len(siblings) > 0 {
+			warnings.Add("rules", "no rules/%s.md for %s %s (other %s.x versions have one)", id, name, version, major)
+		}
 	}
 
-	// major/general
+	// major/<file>
 	if major != "" {
-		addIfExists(ids, name+"/"+major+"/general")
+		addIfExists(ids, name+"/"+major+"/"+file)
 	}
 }
 
+// hasVersionRules reports whether a framework has version-specific rule
+// directories (e.g. vuex/4, not the framework directories of python/django).
+func hasVersionRules(name string) bool {
+	children, err := rules.Children(name)
+	if err != nil {
+		return false
+	}
+	for _, c := range children {
+		if c[0] >= '0' && c[0] <= '9' && !ruleExists(name+"/"+c) {
+			return true
+		}
+	}
+	return false
+}
+
 func addIfExists(ids *[]string, id string) {
 	if ruleExists(id) {
 		*ids = append(*ids, id)
@@ -237,18 +530,34 @@ func buildGeneralRulesFromFlags() []string {
 			ids = append(ids, r)
 		}
 	}
+	ids = filterExpired(filterAudience(ids, false))
+
+	// Project-local additions always come last
+	if len(ids) > 0 {
+		addIfExists(&ids, localGeneralRule)
+	}
 	return ids
 }
 
 // Agent rules (agent.md)
 func buildAgentRulesFromDetection(stack *detect.DetectedStack) []agentFile {
 	var files []agentFile
-	addAgentFor(&files, "PHP", "php", stack.PHP)
-	addAgentFor(&files, "Laravel", "laravel", stack.Laravel)
-	addAgentFor(&files, "Nuxt", "nuxt", stack.Nuxt)
-	addAgentFor(&files, "Vue", "vue", stack.Vue)
-	addAgentFor(&files, "Nuxt UI", "nuxt_ui", stack.NuxtUI)
-	return files
+	for _, t := range ruleTechnologies(stack) {
+		addAgentFor(&files, stackEntryFor(t.Name).Label, ruleDir(t.Name), t.Version)
+	}
+
+	var applicable []agentFile
+	var ids []string
+	for _, af := range files {
+		if ruleApplies(af.ID, stack) {
+			applicable = append(applicable, af)
+			ids = append(ids, af.ID)
+		}
+	}
+	for _, id := range conditionalRules(stack, "/agent", ids) {
+		applicable = append(applicable, agentFile{Label: deriveRuleLabel(id), ID: id})
+	}
+	return applicable
 }
 
 func addAgentFor(files *[]agentFile, label, name, version string) {
@@ -256,13 +565,7 @@ func addAgentFor(files *[]agentFile, label, name, version string) {
 		return
 	}
 
-	norm := normalizeVersion(version)
-	parts := strings.Split(norm, ".")
-	major := parts[0]
-	minor := ""
-	if len(parts) > 1 {
-		minor = parts[1]
-	}
+	major, minor := versionParts(version)
 
 	// major.minor/agent
 	if major != "" && minor != "" {
@@ -305,12 +608,27 @@ func buildAgentRulesFromFlags() []agentFile {
 	return files
 }
 
-// Merge general rule contents
+// Paragraphs shorter than this (e.g. "**Example:**") are never deduplicated.
+const minDedupeChars = 40
+
+// Merge general rule contents (limited to --tags)
 func loadAndMergeRules(ids []string) (string, error) {
+	return mergeRules(ids, activeTags())
+}
+
+// mergeRules merges the parts of the rules matching tags (all when empty).
+func mergeRules(ids, tags []string) (string, error) {
+	loaded, bodies, contributors := resolveRules(ids, tags)
+	if flagAnnotateSources {
+		for id, body := range bodies {
+			bodies[id] = annotateSources(id, body, contributors)
+		}
+	}
+
 	var b strings.Builder
 	for _, id := range ids {
-		data, err := rules.Get(id)
-		if err != nil {
+		data, ok := bodies[id]
+		if !ok {
 			if b.Len() > 0 {
 				b.WriteString("\n\n---\n\n")
 			}
@@ -324,9 +642,32 @@ func loadAndMergeRules(ids []string) (string, error) {
 		if b.Len() > 0 {
 			b.WriteString("\n\n---\n\n")
 		}
-		b.WriteString(data)
+		b.WriteString(variantMarker(loaded[id]))
+		b.WriteString(expiryMarker(id))
+		b.WriteString(rewriteRuleAssets(id, data))
 	}
-	return b.String(), nil
+
+	// General and version-specific rules may share boilerplate paragraphs
+	merged, _ := markdown.DedupeParagraphs(b.String(), minDedupeChars)
+	if appendix := attributionAppendix(ids); appendix != "" {
+		merged += "\n\n---\n\n" + appendix
+	}
+	return merged, nil
+}
+
+// resolveRules loads the rules, keeps the parts matching tags (all when
+// empty) and applies the overrides between them. Rules that fail to load
+// have no body.
+func resolveRules(ids, tags []string) (map[string]*rules.Rule, map[string]string, sectionContributors) {
+	loaded := make(map[string]*rules.Rule, len(ids))
+	bodies := make(map[string]string, len(ids))
+	for _, id := range ids {
+		if r, err := rules.Load(id); err == nil {
+			loaded[id] = r
+			bodies[id] = taggedBody(r, tags)
+		}
+	}
+	return loaded, bodies, applyOverrides(ids, bodies)
 }
 
 // Agent content aggregation
@@ -343,7 +684,7 @@ func buildAgentContent(files []agentFile) string {
 		b.WriteString(af.Label)
 		b.WriteString("\n\n")
 
-		data, err := rules.Get(af.ID)
+		r, err := rules.Load(af.ID)
 		if err != nil {
 			b.WriteString("<!-- Missing agent instructions for ")
 			b.WriteString(af.Label)
@@ -352,15 +693,24 @@ func buildAgentContent(files []agentFile) string {
 			b.WriteString(".md) -->")
 			continue
 		}
-		b.WriteString(data)
+		b.WriteString(variantMarker(r))
+		b.WriteString(rewriteRuleAssets(af.ID, r.Body))
+	}
+
+	ids := make([]string, len(files))
+	for i, af := range files {
+		ids[i] = af.ID
+	}
+	if appendix := attributionAppendix(ids); appendix != "" {
+		b.WriteString("\n\n---\n\n")
+		b.WriteString(appendix)
 	}
 	return b.String()
 }
 
 // Existence probe via embedded rules
 func ruleExists(id string) bool {
-	_, err := rules.Get(id)
-	return err == nil
+	return rules.Exists(id)
 }
 
 func normalizeVersion(v string) string {
@@ -382,6 +732,17 @@ func normalizeVersion(v string) string {
 	return b.String()
 }
 
+// versionParts returns the major and minor version of a version or
+// constraint, e.g. 8 and 2 for ^8.2.1 ("" when missing).
+func versionParts(version string) (major, minor string) {
+	parts := strings.Split(normalizeVersion(version), ".")
+	major = parts[0]
+	if len(parts) > 1 {
+		minor = parts[1]
+	}
+	return major, minor
+}
+
 func deriveRuleLabel(id string) string {
 	id = strings.TrimSuffix(id, "/general")
 	id = strings.TrimSuffix(id, "/agent")
@@ -399,6 +760,76 @@ func writeFileWithDirs(path string, data []byte) error {
 	return writeFile(path, data)
 }
 
+// writeRenderedFiles writes all files or none: every file is staged next to
+// its target first and only renamed into place once all are staged. The
+// targets it replaces are kept aside until every rename succeeded, so a
+// failed rename puts them back and never leaves a mix of old and new outputs.
+func writeRenderedFiles(files []renderedFile) error {
+	var staged []string
+	cleanup := func() {
+		for _, tmp := range staged {
+			_ = os.Remove(tmp)
+		}
+	}
+	for _, f := range files {
+		tmp := stagingPath(f.Path)
+		if err := writeFileWithDirs(tmp, []byte(f.Content)); err != nil {
+			cleanup()
+			return err
+		}
+		staged = append(staged, tmp)
+	}
+
+	// backups[i] holds the previous content of file i ("" when it is new)
+	backups := make([]string, len(files))
+	rollback := func(replaced int) {
+		for i := replaced - 1; i >= 0; i-- {
+			if backups[i] != "" {
+				_ = rename(backups[i], files[i].Path)
+			} else {
+				_ = os.Remove(files[i].Path)
+			}
+		}
+	}
+	for i, f := range files {
+		if _, err := os.Lstat(f.Path); err == nil {
+			backup := rollbackPath(f.Path)
+			if err := rename(f.Path, backup); err != nil {
+				rollback(i)
+				cleanup()
+				return err
+			}
+			backups[i] = backup
+		}
+		if err := rename(staged[i], f.Path); err != nil {
+			if backups[i] != "" {
+				_ = rename(backups[i], f.Path)
+			}
+			rollback(i)
+			cleanup()
+			return err
+		}
+	}
+	for _, backup := range backups {
+		if backup != "" {
+			_ = os.Remove(backup)
+		}
+	}
+	return nil
+}
+
+// stagingPath is the hidden file a rendered file is written to before it is
+// renamed into place.
+func stagingPath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".ai-instructions-tmp")
+}
+
+// rollbackPath is the hidden file a replaced file is kept in until every
+// rendered file is in place.
+func rollbackPath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".ai-instructions-bak")
+}
+
 // This ensures the directory exists, even if it's empty.'
 func ensureDir(dir string) error {
 	return mkdirAll(dir, 0o755)
@@ -409,7 +840,7 @@ func writeFile(path string, data []byte) error {
 	return write(path, data, 0o644)
 }
 
-// this is a wrapper around os.MkdirAll and os.WriteFile to allow future abstraction.
+// this is a wrapper around os.MkdirAll, os.WriteFile and os.Rename to allow future abstraction.
 var (
 	mkdirAll = func(path string, perm uint32) error {
 		return osMkdirAll(path, perm)
@@ -417,8 +848,23 @@ var (
 	write = func(name string, data []byte, perm uint32) error {
 		return osWriteFile(name, data, perm)
 	}
+	rename = os.Rename
 )
 
+// enableReadOnly makes every write through the helpers above fail, so commands
+// run with --read-only cannot touch the working tree.
+func enableReadOnly() {
+	mkdirAll = func(path string, perm uint32) error {
+		return fmt.Errorf("read-only mode: refusing to create directory '%s'", path)
+	}
+	write = func(name string, data []byte, perm uint32) error {
+		return fmt.Errorf("read-only mode: refusing to write '%s'", name)
+	}
+	rename = func(oldpath, newpath string) error {
+		return fmt.Errorf("read-only mode: refusing to write '%s'", newpath)
+	}
+}
+
 // This is a wrapper around os.MkdirAll to allow future abstraction.
 func osMkdirAll(path string, perm uint32) error { return os.MkdirAll(path, os.FileMode(perm)) }
 
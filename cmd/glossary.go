@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/config"
+	"github.com/cego/ai-instructions/internal/warnings"
+	"github.com/cego/ai-instructions/rules"
+)
+
+// glossaryPrefix is the rules directory of the shared glossaries.
+const glossaryPrefix = "glossary/"
+
+// glossaryTermPattern matches a glossary rule entry: "- **Term:** definition"
+// (or "- **Term**: definition").
+var glossaryTermPattern = regexp.MustCompile(`^[-*]\s+\*\*([^*]+?):?\*\*:?\s+(.+)$`)
+
+// glossaryTerms returns the terms of the config's glossary rules, replaced
+// by the config terms of the same name (case-insensitive).
+func glossaryTerms() []config.Term {
+	var terms []config.Term
+	index := map[string]int{}
+	add := func(t config.Term) {
+		key := strings.ToLower(strings.TrimSpace(t.Term))
+		if key == "" || strings.TrimSpace(t.Definition) == "" {
+			return
+		}
+		if i, ok := index[key]; ok {
+			terms[i] = t
+			return
+		}
+		index[key] = len(terms)
+		terms = append(terms, t)
+	}
+
+	for _, name := range cfg.Glossary.Rules {
+		id := glossaryPrefix + strings.TrimPrefix(strings.TrimSpace(name), glossaryPrefix)
+		body, err := rules.Get(id)
+		if err != nil {
+			warnings.Add("config", "glossary: unknown rule '%s'", id)
+			continue
+		}
+		for _, line := range strings.Split(body, "\n") {
+			if m := glossaryTermPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+				add(config.Term{Term: m[1], Definition: m[2]})
+			}
+		}
+	}
+	for _, t := range cfg.Glossary.Terms {
+		add(t)
+	}
+	return terms
+}
+
+// buildGlossarySection lists the domain terms alphabetically, so that
+// assistants use the project's words in code, comments and answers. It is
+// empty when the config defines no glossary.
+func buildGlossarySection() string {
+	terms := glossaryTerms()
+	if len(terms) == 0 {
+		return ""
+	}
+	sort.SliceStable(terms, func(i, j int) bool {
+		return strings.ToLower(terms[i].Term) < strings.ToLower(terms[j].Term)
+	})
+
+	var b strings.Builder
+	b.WriteString("## Glossary\n\n")
+	b.WriteString("Use these domain terms in names, comments, commit messages and answers, with the meaning given here; do not introduce synonyms.\n")
+	for _, t := range terms {
+		if len(t.Aliases) > 0 {
+			fmt.Fprintf(&b, "\n- **%s** (also called %s): %s", strings.TrimSpace(t.Term), strings.Join(t.Aliases, ", "), strings.TrimSpace(t.Definition))
+			continue
+		}
+		fmt.Fprintf(&b, "\n- **%s:** %s", strings.TrimSpace(t.Term), strings.TrimSpace(t.Definition))
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Command groups of the root help, in display order.
+const (
+	groupGeneration    = "generation"
+	groupRules         = "rules"
+	groupIntrospection = "introspection"
+	groupIntegration   = "integration"
+)
+
+// usageTemplate is cobra's default usage template with the commands listed by
+// group and a pointer to the config and examples at the end of the root help.
+const usageTemplate = `Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
+  {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
+
+Aliases:
+  {{.NameAndAliases}}{{end}}{{if .HasExample}}
+
+Examples:
+{{.Example}}{{end}}{{if .HasAvailableSubCommands}}{{$cmds := .Commands}}{{if eq (len .Groups) 0}}
+
+Available Commands:{{range $cmds}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{else}}{{range $group := .Groups}}
+
+{{.Title}}{{range $cmds}}{{if (and (eq .GroupID $group.ID) (or .IsAvailableCommand (eq .Name "help")))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if not .AllChildCommandsHaveGroup}}
+
+Other Commands:{{range $cmds}}{{if (and (eq .GroupID "") (or .IsAvailableCommand (eq .Name "help")))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+
+Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+
+Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+  {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
+
+Use "{{.CommandPath}} [command] --help" for more information and examples.{{end}}{{if not .HasParent}}
+Projects are configured in .ai-instructions.yaml; "{{.CommandPath}} schema" prints its JSON Schema.{{end}}
+`
+
+func init() {
+	rootCmd.AddGroup(
+		&cobra.Group{ID: groupGeneration, Title: "Generation:"},
+		&cobra.Group{ID: groupRules, Title: "Rules:"},
+		&cobra.Group{ID: groupIntrospection, Title: "Introspection:"},
+		&cobra.Group{ID: groupIntegration, Title: "Integration:"},
+	)
+	rootCmd.SetUsageTemplate(usageTemplate)
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+)
+
+// hookCommand is the check our installer adds to the pre-commit hook.
+const hookCommand = "ai-instructions validate"
+
+var flagHooksForce bool
+
+// buildGitHooksSection lists the checks that run on commit, so generated code is written to pass them.
+func buildGitHooksSection(stack *detect.DetectedStack) string {
+	if stack == nil || stack.Hooks.Empty() {
+		return ""
+	}
+	h := stack.Hooks
+
+	var lines []string
+	for _, hook := range sortedKeys(h.Husky) {
+		lines = append(lines, fmt.Sprintf("- `%s` (husky): %s", hook, codeList(h.Husky[hook])))
+	}
+	if len(h.LintStaged) > 0 {
+		lines = append(lines, "- lint-staged runs on staged files:")
+		for _, glob := range sortedKeys(h.LintStaged) {
+			lines = append(lines, fmt.Sprintf("  - `%s`: %s", glob, codeList(h.LintStaged[glob])))
+		}
+	} else if h.LintStagedConfig != "" {
+		lines = append(lines, fmt.Sprintf("- lint-staged runs on staged files (see `%s`)", h.LintStagedConfig))
+	}
+	if h.Lefthook != "" {
+		lines = append(lines, fmt.Sprintf("- lefthook hooks are configured in `%s`", h.Lefthook))
+	}
+	if h.PreCommit != "" {
+		lines = append(lines, fmt.Sprintf("- pre-commit hooks are configured in `%s`", h.PreCommit))
+	}
+
+	return "## Git hooks\n\n" +
+		"These checks run on commit. Code you produce must pass them; do not bypass them with `--no-verify`.\n\n" +
+		strings.Join(lines, "\n")
+}
+
+func codeList(cmds []string) string {
+	quoted := make([]string, len(cmds))
+	for i, c := range cmds {
+		quoted[i] = "`" + c + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var hooksCmd = &cobra.Command{
+	Use:     "hooks",
+	Short:   "Manage the git hook that checks generated files are up to date",
+	GroupID: groupIntegration,
+	Example: "  ai-instructions hooks install",
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Add '" + hookCommand + "' to the pre-commit hook",
+	Long: "Adds '" + hookCommand + "' to the pre-commit hook. When husky manages the hooks, the command\n" +
+		"is appended to .husky/pre-commit; otherwise .git/hooks/pre-commit is used. Existing hooks are\n" +
+		"never replaced: the command is appended, and only once.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := preCommitHookPath(".")
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		existing := string(data)
+
+		if strings.Contains(existing, hookCommand) {
+			fmt.Printf("'%s' already runs in %s\n", hookCommand, path)
+			return nil
+		}
+
+		// lefthook and pre-commit regenerate .git/hooks, so our line would be lost or clash
+		if hooks, err := detect.DetectGitHooks("."); err == nil && !flagHooksForce &&
+			filepath.Base(filepath.Dir(path)) != ".husky" && (hooks.Lefthook != "" || hooks.PreCommit != "") {
+			return fmt.Errorf("git hooks are managed by another tool; add '%s' to its config (or use --force)", hookCommand)
+		}
+
+		var b strings.Builder
+		if existing == "" {
+			b.WriteString("#!/bin/sh\n")
+		} else {
+			b.WriteString(existing)
+			if !strings.HasSuffix(existing, "\n") {
+				b.WriteString("\n")
+			}
+		}
+		b.WriteString(hookCommand + "\n")
+
+		if err := ensureDir(filepath.Dir(path)); err != nil {
+			return err
+		}
+		if err := write(path, []byte(b.String()), 0o755); err != nil {
+			return err
+		}
+
+		fmt.Printf("Added '%s' to %s\n", hookCommand, path)
+		return nil
+	},
+}
+
+// preCommitHookPath returns the pre-commit hook file to edit: husky's when it
+// manages the hooks, else the one in the git directory.
+func preCommitHookPath(root string) (string, error) {
+	if info, err := os.Stat(filepath.Join(root, ".husky")); err == nil && info.IsDir() {
+		return filepath.Join(root, ".husky", "pre-commit"), nil
+	}
+
+	gitDir := filepath.Join(root, ".git")
+	info, err := os.Stat(gitDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("not a git repository (no .git in '%s')", root)
+		}
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("'%s' is not a directory (worktrees and submodules are not supported)", gitDir)
+	}
+	return filepath.Join(gitDir, "hooks", "pre-commit"), nil
+}
+
+func init() {
+	hooksInstallCmd.Flags().BoolVar(
+		&flagHooksForce,
+		"force",
+		false,
+		"Install into .git/hooks even when lefthook or pre-commit manage the hooks",
+	)
+
+	hooksCmd.AddCommand(hooksInstallCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
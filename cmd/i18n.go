@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+)
+
+// buildI18nSection lists the i18n libraries, translation directories and
+// supported locales of a localized project, which the localization rules
+// (rules/i18n) refer to.
+func buildI18nSection(stack *detect.DetectedStack) string {
+	if stack == nil || stack.I18n == nil {
+		return ""
+	}
+	i18n := stack.I18n
+
+	lines := []string{fmt.Sprintf("- Libraries: %s", strings.Join(i18n.Libraries, ", "))}
+	if len(i18n.Dirs) > 0 {
+		dirs := make([]string, len(i18n.Dirs))
+		for i, dir := range i18n.Dirs {
+			dirs[i] = dir + "/"
+		}
+		lines = append(lines, fmt.Sprintf("- Translation files: %s", codeList(dirs)))
+	}
+	if len(i18n.Locales) > 0 {
+		lines = append(lines, fmt.Sprintf("- Supported locales: %s; every new translation key must exist in each of them", codeList(i18n.Locales)))
+	}
+	return "## Localization\n\n" + strings.Join(lines, "\n")
+}
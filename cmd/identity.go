@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+)
+
+var flagIncludeIdentity bool
+
+// buildIdentitySection lists the project name, repository, primary branch and
+// license of dir. It is empty unless --include-identity is set, as the git
+// metadata differs between clones (e.g. CI checkouts without origin/HEAD).
+func buildIdentitySection(dir string) string {
+	if !flagIncludeIdentity {
+		return ""
+	}
+
+	id, err := detect.DetectIdentity(dir)
+	if err != nil || id.Empty() {
+		return ""
+	}
+
+	var lines []string
+	for _, field := range []struct{ label, value string }{
+		{"Name", id.Name},
+		{"Description", id.Description},
+		{"Repository", id.Repository},
+		{"Primary branch", id.Branch},
+		{"License", id.License},
+	} {
+		if v := normalizeStackValue(field.value); v != "" {
+			lines = append(lines, fmt.Sprintf("- %s: %s", field.label, v))
+		}
+	}
+	return "## Project identity\n\n" + strings.Join(lines, "\n")
+}
+
+// addIncludeIdentityFlag registers --include-identity on a command that renders instructions.
+func addIncludeIdentityFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flagIncludeIdentity,
+		"include-identity",
+		false,
+		"Add a project identity section (name, description, repository, primary branch, license) from git and the manifests",
+	)
+}
+
+func init() {
+	addIncludeIdentityFlag(generateCmd)
+	addIncludeIdentityFlag(validateCmd)
+	addIncludeIdentityFlag(exportCmd)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/rulepack"
+	"github.com/cego/ai-instructions/rules"
+)
+
+var (
+	flagImportFrom  string
+	flagImportAs    string
+	flagImportForce bool
+)
+
+// importAliases map words of community instruction names to our rule
+// directories, e.g. awesome-copilot:vuejs3 to vue/3/general.
+var importAliases = map[string]string{
+	"php":        "php",
+	"laravel":    "laravel",
+	"vue":        "vue",
+	"vuejs":      "vue",
+	"nuxt":       "nuxt",
+	"nuxtjs":     "nuxt",
+	"go":         "go",
+	"golang":     "go",
+	"typescript": "typescript",
+	"ts":         "typescript",
+	"pinia":      "pinia",
+	"vuex":       "vuex",
+	"bazel":      "bazel",
+	"nix":        "nix",
+}
+
+var importWordRe = regexp.MustCompile(`^([a-z]+?)(\d+)?$`)
+
+var rulesImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Convert a community instruction file (awesome-copilot, awesome-cursorrules) into a local rule",
+	Long: "Fetches a community instruction file and writes it as a local rule (--rules-dir) with its\n" +
+		"source and license in the front matter, e.g.:\n\n" +
+		"  ai-instructions rules import --from awesome-copilot:vuejs3        # rules/vue/3/general.md\n" +
+		"  ai-instructions rules import --from awesome-cursorrules:htmx-basic-cursorrules-prompt-file --as htmx/general\n\n" +
+		"The rule ID is derived from the name when it names a supported framework; use --as otherwise.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagImportFrom == "" {
+			return fmt.Errorf("--from is required")
+		}
+		pack, name, err := rulepack.Parse(flagImportFrom)
+		if err != nil {
+			return err
+		}
+
+		id, err := resolveImportID(flagImportAs, name)
+		if err != nil {
+			return err
+		}
+		if ruleExists(id) && !flagImportForce {
+			where := "an embedded rule"
+			if rules.IsLocal(id) {
+				where = "a local rule"
+			}
+			return fmt.Errorf("rules/%s.md already exists as %s; the import would replace it (use --force)", id, where)
+		}
+
+		data, url, err := rulepack.Fetch(cmd.Context(), nil, pack, name)
+		if err != nil {
+			return err
+		}
+		label := deriveRuleLabel(id)
+		title := strings.ToUpper(label[:1]) + label[1:] + " Guidelines"
+		content, err := rulepack.Convert(data, title, rulepack.Provenance{
+			Source:  url,
+			License: pack.License,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", flagImportFrom, err)
+		}
+
+		path := filepath.Join(flagRulesDir, filepath.FromSlash(id)+".md")
+		if err := writeFileWithDirs(path, []byte(content)); err != nil {
+			return err
+		}
+		fmt.Printf("Imported %s as rules/%s.md (%s)\n", flagImportFrom, id, path)
+		fmt.Println("Review the rule, then run 'ai-instructions rules lint' and 'ai-instructions generate'.")
+		return nil
+	},
+}
+
+// resolveImportID returns the rule ID an import is written to: as (--as) or
+// the ID derived from the instruction name. IDs that would write outside the
+// rules directory or name a variant are rejected.
+func resolveImportID(as, name string) (string, error) {
+	id := strings.Trim(filepath.ToSlash(as), "/")
+	if id == "" {
+		if id = importRuleID(name); id == "" {
+			return "", fmt.Errorf("cannot map '%s' onto a framework; choose the rule ID with --as (e.g. --as laravel/general)", name)
+		}
+	}
+	if strings.Contains(id, "..") || strings.Contains(id, rules.VariantSeparator) || !filepath.IsLocal(filepath.FromSlash(id)) {
+		return "", fmt.Errorf("invalid rule ID '%s'", id)
+	}
+	return id, nil
+}
+
+// importRuleID derives <framework>[/<major>]/general from an instruction
+// name such as "vuejs3" or "go-best-practices" ("" when no word matches).
+func importRuleID(name string) string {
+	for _, word := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}) {
+		m := importWordRe.FindStringSubmatch(word)
+		if m == nil {
+			continue
+		}
+		dir, ok := importAliases[m[1]]
+		if !ok {
+			continue
+		}
+		if m[2] != "" {
+			return dir + "/" + m[2] + "/general"
+		}
+		return dir + "/general"
+	}
+	return ""
+}
+
+func init() {
+	rulesCmd.AddCommand(rulesImportCmd)
+
+	rulesImportCmd.Flags().StringVar(
+		&flagImportFrom,
+		"from",
+		"",
+		"Instruction file to import as <pack>:<name> (packs: "+strings.Join(rulepack.Names(), ", ")+")",
+	)
+	rulesImportCmd.Flags().StringVar(
+		&flagImportAs,
+		"as",
+		"",
+		"Rule ID to write, e.g. laravel/11/general (default: derived from the name)",
+	)
+	rulesImportCmd.Flags().BoolVar(
+		&flagImportForce,
+		"force",
+		false,
+		"Replace an existing embedded or local rule with the same ID",
+	)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import "testing"
+
+func TestResolveImportID(t *testing.T) {
+	tests := []struct {
+		as, name string
+		want     string
+		wantErr  bool
+	}{
+		{"", "vuejs3", "vue/3/general", false},
+		{"", "go-best-practices", "go/general", false},
+		{"", "htmx-basic", "", true},
+		{"htmx/general", "htmx-basic", "htmx/general", false},
+		{"/htmx/general/", "htmx-basic", "htmx/general", false},
+		{"../outside", "vuejs3", "", true},
+		{"vue/../../outside", "vuejs3", "", true},
+		{"vue/general@terse", "vuejs3", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.as+"|"+tt.name, func(t *testing.T) {
+			got, err := resolveImportID(tt.as, tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveImportID() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveImportID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+)
+
+var flagLanguageStats bool
+
+// minLanguagePercent hides languages below this share in the stack section.
+const minLanguagePercent = 1.0
+
+// languageSummary formats the languages with at least minPercent as
+// "Go 80.1%, PHP 19.9%".
+func languageSummary(languages []detect.LanguageShare, minPercent float64) string {
+	var parts []string
+	for _, l := range languages {
+		if l.Percent >= minPercent {
+			parts = append(parts, fmt.Sprintf("%s %.1f%%", l.Name, l.Percent))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildLanguagesSubsection lists the repository's languages by size. It is
+// empty unless --language-stats is set.
+func buildLanguagesSubsection(stack *detect.DetectedStack) string {
+	if !flagLanguageStats || len(stack.Languages) == 0 {
+		return ""
+	}
+	var lines []string
+	for _, l := range stack.Languages {
+		if l.Percent >= minLanguagePercent {
+			lines = append(lines, fmt.Sprintf("- %s: %.1f%%", l.Name, l.Percent))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "### Languages (by source size)\n\n" + strings.Join(lines, "\n")
+}
+
+// addLanguageStatsFlag registers --language-stats on a command that renders instructions.
+func addLanguageStatsFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flagLanguageStats,
+		"language-stats",
+		false,
+		"Add the share of each language (by bytes of source files) to the stack section",
+	)
+}
+
+func init() {
+	addLanguageStatsFlag(generateCmd)
+	addLanguageStatsFlag(validateCmd)
+	addLanguageStatsFlag(exportCmd)
+	addLanguageStatsFlag(renderCmd)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/warnings"
+)
+
+// legacyRuleFlags are the boolean flags of the legacy interface
+// ("ai-instructions --php --react"), mapped to the rule sets they select.
+// They are deprecated and kept for one major version, so that CI scripts
+// written for the legacy binary keep working.
+var legacyRuleFlags = []struct {
+	Flag string
+	Rule string
+}{
+	{"php", "php/plain"},
+	{"laravel", "laravel"},
+	{"horizon", "horizon"},
+	{"octane", "octane"},
+	{"scheduler", "scheduler"},
+	{"inertia", "inertia"},
+	{"javascript", "javascript/plain"},
+	{"typescript", "typescript"},
+	{"react", "react"},
+	{"nuxt", "nuxt"},
+	{"nuxt-ui", "nuxt_ui"},
+	{"pinia", "pinia"},
+	{"vuex", "vuex"},
+	{"vue-router", "vue_router"},
+	{"go", "go"},
+	{"bazel", "bazel"},
+	{"nix", "nix"},
+	{"git", "git/commit-message"},
+}
+
+var flagLegacyRules = map[string]*bool{}
+
+func init() {
+	for _, l := range legacyRuleFlags {
+		flagLegacyRules[l.Flag] = rootCmd.Flags().Bool(
+			l.Flag,
+			false,
+			"Include the "+l.Rule+" rules (legacy interface)",
+		)
+		_ = rootCmd.Flags().MarkDeprecated(l.Flag, "use 'ai-instructions generate --rule "+l.Rule+"' instead; the legacy flags are removed in the next major version")
+	}
+
+	rootCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		rules := legacyRules(cmd)
+		if len(rules) == 0 {
+			return cmd.Help()
+		}
+		// A CI script must not pass while generating nothing
+		for _, r := range rules {
+			if !ruleExists(r+"/general") && !ruleExists(r) {
+				return fmt.Errorf("legacy flag selects rules/%s, which does not exist in this release; use 'ai-instructions generate --rule <rule>' (see 'ai-instructions list')", r)
+			}
+		}
+		warnings.Add("legacy", "replace the legacy flags with: ai-instructions generate --rule %s", strings.Join(rules, " --rule "))
+		flagRules = rules
+		return runGenerate()
+	}
+}
+
+// legacyRules returns the rule sets selected by the legacy flags given.
+func legacyRules(cmd *cobra.Command) []string {
+	var ids []string
+	for _, l := range legacyRuleFlags {
+		if cmd.Flags().Changed(l.Flag) && *flagLegacyRules[l.Flag] {
+			ids = append(ids, l.Rule)
+		}
+	}
+	return ids
+}
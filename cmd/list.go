@@ -8,10 +8,19 @@ import (
 )
 
 var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all available embedded rule files",
+	Use:     "list [pattern]",
+	Short:   "List all available embedded rule files, optionally filtered by a glob (e.g. \"php/8/*\")",
+	GroupID: groupRules,
+	Example: "  ai-instructions list \"php/8/*\"",
+	Args:    cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		names, err := rules.List()
+		var names []string
+		var err error
+		if len(args) == 1 {
+			names, err = rules.Glob(args[0])
+		} else {
+			names, err = rules.List()
+		}
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/cego/ai-instructions/rules"
+)
+
+// flagManifest is where generate records the files it wrote ("" disables).
+var flagManifest string
+
+// outputManifest lists the files written by generate, for tooling that
+// verifies or collects them without re-running detection.
+type outputManifest struct {
+	Version   string         `json:"version"`
+	RulesHash string         `json:"rules_hash"`
+	Files     []manifestFile `json:"files"`
+}
+
+type manifestFile struct {
+	Path   string `json:"path"`
+	Label  string `json:"label"`
+	SHA256 string `json:"sha256"`
+}
+
+func init() {
+	generateCmd.Flags().StringVar(
+		&flagManifest,
+		"manifest",
+		"",
+		"Also write a JSON manifest of the generated files (path, label, sha256) to this file",
+	)
+}
+
+// writeManifest writes the output manifest for files to path.
+func writeManifest(path string, files []renderedFile) error {
+	hash, err := rules.Hash()
+	if err != nil {
+		return err
+	}
+	m := outputManifest{Version: version, RulesHash: hash, Files: []manifestFile{}}
+	for _, f := range files {
+		sum := sha256.Sum256([]byte(f.Content))
+		m.Files = append(m.Files, manifestFile{Path: f.Path, Label: f.Label, SHA256: hex.EncodeToString(sum[:])})
+	}
+	out, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileWithDirs(path, append(out, '\n'))
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/mcp"
+	"github.com/cego/ai-instructions/rules"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:     "mcp",
+	Short:   "Run a Model Context Protocol (stdio) server exposing rules and detection",
+	GroupID: groupIntegration,
+	Example: "  # Register as a stdio MCP server in the editor, e.g. command \"ai-instructions\", args [\"mcp\"]\n" +
+		"  ai-instructions mcp",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server := &mcp.Server{
+			Name:    "ai-instructions",
+			Version: version,
+			Tools:   mcpTools(),
+		}
+		return server.Serve(os.Stdin, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+type mcpPathArgs struct {
+	Path string `json:"path"`
+}
+
+func (a mcpPathArgs) root() string {
+	if a.Path == "" {
+		return "."
+	}
+	return a.Path
+}
+
+var mcpPathSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"path": map[string]any{"type": "string", "description": "Project root (defaults to the server's working directory)"},
+	},
+}
+
+func mcpTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "detect_stack",
+			Description: "Detect the technology stack (PHP, Laravel, Nuxt, Vue, Nuxt UI versions) of a project",
+			InputSchema: mcpPathSchema,
+			Handler: func(raw json.RawMessage) (string, error) {
+				var args mcpPathArgs
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return "", err
+				}
+				stack, err := detect.DetectStack(args.root())
+				if err != nil {
+					return "", err
+				}
+				data, err := json.MarshalIndent(stack, "", "  ")
+				return string(data), err
+			},
+		},
+		{
+			Name:        "list_rules",
+			Description: "List all embedded rule identifiers",
+			InputSchema: map[string]any{"type": "object"},
+			Handler: func(json.RawMessage) (string, error) {
+				names, err := rules.List()
+				return strings.Join(names, "\n"), err
+			},
+		},
+		{
+			Name:        "get_rule",
+			Description: "Get the markdown content of an embedded rule, e.g. 'laravel/general'",
+			InputSchema: map[string]any{
+				"type":     "object",
+				"required": []string{"id"},
+				"properties": map[string]any{
+					"id": map[string]any{"type": "string", "description": "Rule identifier without .md"},
+				},
+			},
+			Handler: func(raw json.RawMessage) (string, error) {
+				var args struct {
+					ID string `json:"id"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return "", err
+				}
+				data, err := rules.Get(args.ID)
+				if err != nil {
+					return "", fmt.Errorf("unknown rule '%s'", args.ID)
+				}
+				return data, nil
+			},
+		},
+		{
+			Name:        "generate_instructions",
+			Description: "Generate the instructions (stack section plus merged rules) for a project",
+			InputSchema: mcpPathSchema,
+			Handler: func(raw json.RawMessage) (string, error) {
+				var args mcpPathArgs
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return "", err
+				}
+				stack, err := detect.DetectStack(args.root())
+				if err != nil {
+					return "", err
+				}
+				content, err := buildStackContent(args.root(), stack)
+				if err != nil {
+					return "", err
+				}
+				if content == "" {
+					return "", fmt.Errorf("no rules apply to the detected stack")
+				}
+				return content, nil
+			},
+		},
+	}
+}
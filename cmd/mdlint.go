@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/mdlint"
+)
+
+// flagMarkdownlint enables markdownlint fixes even without a config section.
+var flagMarkdownlint bool
+
+// addMarkdownlintFlag registers --markdownlint on a command that writes instruction files.
+func addMarkdownlintFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flagMarkdownlint,
+		"markdownlint",
+		false,
+		"Fix generated markdown for markdownlint ("+strings.Join(mdlint.All, ", ")+"; select rules with markdownlint.rules in the config)",
+	)
+}
+
+func init() {
+	addMarkdownlintFlag(generateCmd)
+	addMarkdownlintFlag(validateCmd)
+	addMarkdownlintFlag(renderCmd)
+}
+
+// markdownlintOptions returns the configured rules; ok is false when disabled.
+func markdownlintOptions() (mdlint.Options, bool) {
+	opts := mdlint.Options{Rules: cfg.Markdownlint.Rules, Language: cfg.Markdownlint.CodeLanguage}
+	if len(opts.Rules) == 0 {
+		if !flagMarkdownlint {
+			return opts, false
+		}
+		opts.Rules = mdlint.All
+	}
+	return opts, true
+}
+
+// lintFiles applies the markdownlint fixes to the markdown outputs.
+func lintFiles(files []renderedFile) ([]renderedFile, error) {
+	opts, ok := markdownlintOptions()
+	if !ok {
+		return files, nil
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	out := make([]renderedFile, len(files))
+	for i, f := range files {
+		if strings.HasSuffix(f.Path, ".md") {
+			f.Content = mdlint.Fix(f.Content, opts)
+		}
+		out[i] = f
+	}
+	return out, nil
+}
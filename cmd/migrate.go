@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/config"
+	"github.com/cego/ai-instructions/internal/migrate"
+)
+
+var flagMigrateDryRun bool
+
+var migrateConfigCmd = &cobra.Command{
+	Use:   "migrate-config",
+	Short: "Upgrade the config file and local rule front matter to the current schema in place",
+	// The config may not load before it is migrated, so skip the root hook
+	GroupID: groupRules,
+	Example: "  ai-instructions migrate-config --dry-run",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagConfig == config.Stdin {
+			return fmt.Errorf("migrate-config cannot migrate a config read from stdin; run it on the file")
+		}
+
+		var changed, failed int
+		migrateFile := func(path string, fn func([]byte) ([]byte, []string, error)) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				fmt.Printf("%s: %v\n", path, err)
+				failed++
+				return
+			}
+			out, changes, err := fn(data)
+			if err != nil {
+				fmt.Printf("%s: %v\n", path, err)
+				failed++
+				return
+			}
+			if len(changes) == 0 {
+				return
+			}
+			changed++
+			fmt.Printf("%s:\n", path)
+			for _, c := range changes {
+				fmt.Printf("  - %s\n", c)
+			}
+			if flagMigrateDryRun {
+				return
+			}
+			if err := writeFile(path, out); err != nil {
+				fmt.Printf("%s: %v\n", path, err)
+				failed++
+			}
+		}
+
+		if _, err := os.Stat(flagConfig); err == nil {
+			migrateFile(flagConfig, func(data []byte) ([]byte, []string, error) {
+				return migrate.YAML(data, migrate.Config)
+			})
+		}
+
+		if info, err := os.Stat(flagRulesDir); err == nil && info.IsDir() {
+			err := filepath.WalkDir(flagRulesDir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() && strings.HasSuffix(path, ".md") {
+					migrateFile(path, migrate.Rule)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("migration failed for %d file(s)", failed)
+		}
+		switch {
+		case changed == 0:
+			fmt.Println("Config and local rules already use the current schema.")
+		case flagMigrateDryRun:
+			fmt.Printf("%d file(s) would be migrated (dry run, nothing written).\n", changed)
+		default:
+			fmt.Printf("%d file(s) migrated.\n", changed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateConfigCmd)
+
+	migrateConfigCmd.Flags().BoolVar(
+		&flagMigrateDryRun,
+		"dry-run",
+		false,
+		"Only report the changes, do not write any file",
+	)
+}
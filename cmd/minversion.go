@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// cliVersion returns the running version: the -ldflags version, or the
+// module version for `go install ...@vX.Y.Z` builds ("dev" otherwise).
+func cliVersion() string {
+	if version != "dev" {
+		return version
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		return bi.Main.Version
+	}
+	return version
+}
+
+// checkMinVersion fails when the config requires a newer CLI, since older
+// binaries render different output and validate would report confusing diffs.
+// Development builds are never rejected.
+func checkMinVersion() error {
+	if cfg.MinVersion == "" {
+		return nil
+	}
+	want := releaseVersion(cfg.MinVersion)
+	if want == nil {
+		return fmt.Errorf("invalid minVersion '%s' in config (use e.g. 1.4.0)", cfg.MinVersion)
+	}
+	current := cliVersion()
+	have := releaseVersion(current)
+	if have == nil || strings.HasPrefix(current, "v0.0.0-") {
+		return nil
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			if have[i] > want[i] {
+				return nil
+			}
+			return fmt.Errorf("this project requires ai-instructions %s or newer (running %s); upgrade with: go install %s@latest",
+				cfg.MinVersion, current, modulePath)
+		}
+	}
+	return nil
+}
+
+// releaseVersion parses "1.4.0", "v1.4" or "v1.4.0-rc.1" into major, minor
+// and patch (nil when v is not a version).
+func releaseVersion(v string) []int {
+	v, _, _ = strings.Cut(strings.TrimPrefix(strings.TrimSpace(v), "v"), "-")
+	v, _, _ = strings.Cut(v, "+")
+	parts := strings.Split(v, ".")
+	if len(parts) > 3 {
+		return nil
+	}
+	out := make([]int, 3)
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil
+		}
+		out[i] = n
+	}
+	return out
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/cego/ai-instructions/internal/markdown"
+	"github.com/cego/ai-instructions/rules"
+)
+
+// flagEmitModel is where generate writes the document model ("" disables).
+var flagEmitModel string
+
+func init() {
+	generateCmd.Flags().StringVar(
+		&flagEmitModel,
+		"emit-model",
+		"",
+		"Also write the resolved document model (rules, sections, bullets, metadata, provenance) as JSON to this file, for external renderers",
+	)
+}
+
+// documentModel is the content of the generated instructions after rule
+// selection, tag filtering and overrides, structured for systems that render
+// it their own way (docs portals, static site generators).
+type documentModel struct {
+	Version   string `json:"version"`
+	RulesHash string `json:"rules_hash"`
+	// Stack holds the sections built from the detected stack (auto mode).
+	Stack []modelSection `json:"stack,omitempty"`
+	Rules []modelRule    `json:"rules"`
+	// Categories are the rules of the commit message, pull request and
+	// review instructions, by category.
+	Categories map[string][]modelRule `json:"categories,omitempty"`
+}
+
+type modelRule struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	// Title is the text of the rule's level 1 heading.
+	Title      string          `json:"title,omitempty"`
+	Metadata   modelMetadata   `json:"metadata"`
+	Provenance modelProvenance `json:"provenance"`
+	Sections   []modelSection  `json:"sections"`
+}
+
+type modelMetadata struct {
+	Tags     []string `json:"tags,omitempty"`
+	Audience string   `json:"audience,omitempty"`
+	Expires  string   `json:"expires,omitempty"`
+	Variant  string   `json:"variant,omitempty"`
+}
+
+type modelProvenance struct {
+	File string `json:"file"`
+	// Origin is "embedded" or "local" (the project rules directory).
+	Origin  string `json:"origin"`
+	Author  string `json:"author,omitempty"`
+	URL     string `json:"url,omitempty"`
+	License string `json:"license,omitempty"`
+}
+
+// modelSection is a heading with the blocks up to the next heading; the
+// blocks before the first heading form a section without heading.
+type modelSection struct {
+	Heading string `json:"heading,omitempty"`
+	Level   int    `json:"level,omitempty"`
+	// Sources are the rule files the section comes from, the rules that
+	// changed it through overrides included.
+	Sources []string     `json:"sources"`
+	Blocks  []modelBlock `json:"blocks"`
+}
+
+type modelBlock struct {
+	// Type is paragraph, bullet, code, quote, table or rule.
+	Type    string     `json:"type"`
+	Text    string     `json:"text,omitempty"`
+	Level   int        `json:"level,omitempty"`
+	Ordered bool       `json:"ordered,omitempty"`
+	Lang    string     `json:"lang,omitempty"`
+	Lines   []string   `json:"lines,omitempty"`
+	Rows    [][]string `json:"rows,omitempty"`
+}
+
+var modelBlockTypes = map[markdown.BlockKind]string{
+	markdown.BlockParagraph: "paragraph",
+	markdown.BlockListItem:  "bullet",
+	markdown.BlockCode:      "code",
+	markdown.BlockQuote:     "quote",
+	markdown.BlockTable:     "table",
+	markdown.BlockRule:      "rule",
+}
+
+// writeModel writes the document model of the general rules ids, the
+// category rules and the detected stack sections to path.
+func writeModel(path string, ids []string, categoryIDs map[string][]string, detected string) error {
+	hash, err := rules.Hash()
+	if err != nil {
+		return err
+	}
+	m := documentModel{Version: version, RulesHash: hash, Rules: buildModelRules(ids)}
+	if detected != "" {
+		_, m.Stack = modelSections(detected, func(string) []string { return []string{"detection"} })
+	}
+	for _, c := range categories {
+		if len(categoryIDs[c]) > 0 {
+			if m.Categories == nil {
+				m.Categories = map[string][]modelRule{}
+			}
+			m.Categories[c] = buildModelRules(categoryIDs[c])
+		}
+	}
+	out, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileWithDirs(path, append(out, '\n'))
+}
+
+// buildModelRules resolves the rules like mergeRules and structures them.
+// Missing rules are left out.
+func buildModelRules(ids []string) []modelRule {
+	loaded, bodies, contributors := resolveRules(ids, activeTags())
+	out := []modelRule{}
+	for _, id := range ids {
+		r, ok := loaded[id]
+		if !ok {
+			continue
+		}
+		origin := "embedded"
+		if rules.IsLocal(id) {
+			origin = "local"
+		}
+		rule := modelRule{
+			ID:    id,
+			Label: deriveRuleLabel(id),
+			Metadata: modelMetadata{
+				Tags:     r.Meta.Tags,
+				Audience: r.Meta.Audience,
+				Expires:  r.Meta.Expires,
+				Variant:  r.Variant,
+			},
+			Provenance: modelProvenance{
+				File:    "rules/" + id + ".md",
+				Origin:  origin,
+				Author:  r.Meta.Author,
+				URL:     r.Meta.Source,
+				License: r.Meta.License,
+			},
+		}
+		rule.Title, rule.Sections = modelSections(rewriteRuleAssets(id, bodies[id]), func(heading string) []string {
+			return contributors.sources(id, heading)
+		})
+		out = append(out, rule)
+	}
+	return out
+}
+
+// modelSections splits markdown into sections. The first level 1 heading is
+// returned as the title instead of a section; comments are left out.
+func modelSections(md string, sources func(heading string) []string) (string, []modelSection) {
+	var title string
+	sections := []modelSection{}
+	current := -1
+	for _, b := range markdown.Parse(md).Blocks {
+		if b.Kind == markdown.BlockHeading {
+			if b.Level == 1 && title == "" && len(sections) == 0 {
+				title = b.Text
+				continue
+			}
+			sections = append(sections, modelSection{Heading: b.Text, Level: b.Level, Sources: sources(b.Text), Blocks: []modelBlock{}})
+			current = len(sections) - 1
+			continue
+		}
+		typ, ok := modelBlockTypes[b.Kind]
+		if !ok {
+			continue
+		}
+		if current < 0 {
+			sections = append(sections, modelSection{Sources: sources(""), Blocks: []modelBlock{}})
+			current = 0
+		}
+		sections[current].Blocks = append(sections[current].Blocks, modelBlock{
+			Type:    typ,
+			Text:    b.Text,
+			Level:   b.Level,
+			Ordered: b.Ordered,
+			Lang:    b.Lang,
+			Lines:   b.Lines,
+			Rows:    b.Rows,
+		})
+	}
+	return title, sections
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/config"
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/warnings"
+)
+
+// buildOutputFiles renders the named outputs of the config from the resolved
+// general and agent rules (stack is nil in manual mode). Outputs without
+// applicable rules are skipped with a warning, so one config can serve
+// several repositories.
+func buildOutputFiles(stack *detect.DetectedStack, generalIDs []string, agentFiles []agentFile, assetsDir string) ([]renderedFile, error) {
+	var files []renderedFile
+	seen := map[string]bool{}
+	for _, o := range cfg.Outputs {
+		switch {
+		case o.Name == "" || o.Path == "":
+			return nil, fmt.Errorf("config outputs: every output needs a name and a path")
+		case seen[o.Name]:
+			return nil, fmt.Errorf("config outputs: duplicate output '%s'", o.Name)
+		}
+		seen[o.Name] = true
+
+		var body string
+		switch o.Rules {
+		case "", config.OutputGeneral:
+			tags := normalizeTags(o.Tags)
+			ids := filterTagged(generalIDs, tags)
+			if len(ids) == 0 {
+				warnings.Add("config", "output '%s': no rules apply, skipped", o.Name)
+				continue
+			}
+			merged, err := mergeRules(ids, tags)
+			if err != nil {
+				return nil, err
+			}
+			body = merged
+			if stack != nil {
+				if detected := buildDetectedSections(".", stack); detected != "" {
+					body = detected + "\n\n---\n\n" + body
+				}
+			}
+		case config.OutputAgents:
+			if len(agentFiles) == 0 {
+				warnings.Add("config", "output '%s': no agent rules apply, skipped", o.Name)
+				continue
+			}
+			body = buildAgentContent(agentFiles)
+		default:
+			return nil, fmt.Errorf("output '%s': unknown rules '%s' (use %s or %s)", o.Name, o.Rules, config.OutputGeneral, config.OutputAgents)
+		}
+
+		path := filepath.ToSlash(o.Path)
+		files = append(files, renderedFile{
+			Label:   "OUTPUT " + strings.ToUpper(o.Name),
+			Path:    path,
+			Content: wrapBoilerplate(resolveAssetLinks(body, path, assetsDir)),
+		})
+	}
+	return files, nil
+}
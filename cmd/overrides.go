@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"path"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/markdown"
+	"github.com/cego/ai-instructions/rules"
+)
+
+// sectionContributors records, per rule and lower-cased section heading, which
+// other rules changed that section through overrides.
+type sectionContributors map[string]map[string][]string
+
+func (c sectionContributors) add(target, heading, by string) {
+	if c[target] == nil {
+		c[target] = map[string][]string{}
+	}
+	key := strings.ToLower(heading)
+	for _, existing := range c[target][key] {
+		if existing == by {
+			return
+		}
+	}
+	c[target][key] = append(c[target][key], by)
+}
+
+// applyOverrides runs the merge pass where rules suppress or replace sections
+// and bullets of the other rules selected for the same output (e.g. laravel/11
+// overriding a bullet from laravel/general), so contradictory instructions
+// never appear together.
+func applyOverrides(ids []string, bodies map[string]string) sectionContributors {
+	contributors := sectionContributors{}
+	for _, id := range ids {
+		r, err := rules.Load(id)
+		if err != nil {
+			continue
+		}
+		for _, o := range r.Meta.Overrides {
+			for _, target := range ids {
+				if target == id || (o.Rule != "" && o.Rule != target) || (o.Rule == "" && !lessSpecific(target, id)) {
+					continue
+				}
+				body, ok := bodies[target]
+				if !ok {
+					continue
+				}
+				if o.Section != "" && o.Replace != "" {
+					contributors.add(target, o.Section, id)
+				}
+				if o.Bullet != "" {
+					for _, heading := range markdown.SectionOf(body, o.Bullet) {
+						contributors.add(target, heading, id)
+					}
+				}
+				bodies[target] = applyOverride(body, o)
+			}
+		}
+	}
+	return contributors
+}
+
+// lessSpecific reports whether target is a rule of the same tree as id that
+// applies more broadly: it lives in a parent directory (laravel/general for
+// laravel/11/general) or in a version directory id's refines (laravel/11 for
+// laravel/11.2).
+func lessSpecific(target, id string) bool {
+	targetDir, idDir := path.Dir(target), path.Dir(id)
+	if targetDir == idDir {
+		return false
+	}
+	return strings.HasPrefix(idDir, targetDir+"/") || strings.HasPrefix(idDir, targetDir+".")
+}
+
+func applyOverride(body string, o rules.Override) string {
+	switch {
+	case o.Section != "" && o.Replace != "":
+		body, _ = markdown.ReplaceSection(body, o.Section, o.Replace)
+	case o.Section != "":
+		body, _ = markdown.RemoveSection(body, o.Section)
+	case o.Bullet != "":
+		body, _ = markdown.ReplaceBullets(body, o.Bullet, o.Replace)
+	}
+	return body
+}
+
+// annotateSources appends a provenance comment after every section of a rule body.
+func annotateSources(id, body string, contributors sectionContributors) string {
+	return markdown.AnnotateSections(body, func(heading string) string {
+		return "<!-- source: " + strings.Join(contributors.sources(id, heading), ", ") + " -->"
+	})
+}
+
+// sources returns the rule files a section of rule id comes from: the rule
+// itself, then the rules that changed the section.
+func (c sectionContributors) sources(id, heading string) []string {
+	sources := []string{"rules/" + id + ".md"}
+	for _, by := range c[id][strings.ToLower(heading)] {
+		sources = append(sources, "rules/"+by+".md")
+	}
+	return sources
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/config"
+)
+
+// flagForce overwrites a hand-written copilot-instructions.md without asking.
+var flagForce bool
+
+// generatedMarker ends the generated copilot-instructions.md, so that generate
+// can tell it from a hand-written file it must not clobber.
+const generatedMarker = "<!-- Generated by ai-instructions. Edit the rules or .ai-instructions.yaml instead of this file. -->"
+
+// mergedSectionTitle is the title of the config section a hand-written file
+// is merged into, unless it starts with a heading of its own.
+const mergedSectionTitle = "Project instructions"
+
+// markGenerated appends the generation marker to content.
+func markGenerated(content string) string {
+	return strings.TrimRight(content, "\n") + "\n\n" + generatedMarker + "\n"
+}
+
+// isGenerated reports whether an existing file was written by generate: it
+// has the marker or the --stamp comment, or (written before the marker
+// existed) the stack section, or it is the rendered content without the
+// marker, as written by earlier releases with --rule.
+func isGenerated(content, rendered string) bool {
+	return strings.Contains(content, "Generated by ai-instructions") ||
+		strings.HasPrefix(content, "## Stack\n") || strings.Contains(content, "\n## Stack\n") ||
+		withoutGeneratedComments(content) == withoutGeneratedComments(rendered)
+}
+
+// withoutGeneratedComments returns content without the lines of the marker
+// and --stamp comment, trimmed.
+func withoutGeneratedComments(content string) string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.Contains(line, "Generated by ai-instructions") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// resolveOverwriteConflict stops generate from silently replacing a
+// hand-written copilot-instructions.md at path with rendered. Interactive
+// runs choose to back it up, merge it into the config as a custom section
+// (merged is then set, since the outputs must be rendered again), or abort;
+// other runs fail unless --force is set.
+func resolveOverwriteConflict(path, rendered string) (merged bool, err error) {
+	if flagForce {
+		return false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if isGenerated(string(data), rendered) || strings.TrimSpace(string(data)) == "" {
+		return false, nil
+	}
+
+	conflict := fmt.Errorf("'%s' was not generated by ai-instructions and would be overwritten; run generate in a terminal to back it up or merge it, or use --force", path)
+	if !isInteractive() {
+		return false, conflict
+	}
+
+	fmt.Printf("'%s' was not generated by ai-instructions.\n", path)
+	fmt.Printf("  [b] back it up to %s and overwrite it\n", backupPath(path))
+	fmt.Printf("  [m] merge it into %s as a custom section included in the generated files\n", flagConfig)
+	fmt.Println("  [a] abort")
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("Choice [b/m/a]: ")
+		answer, err := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "b", "backup":
+			backup := backupPath(path)
+			if err := writeFile(backup, data); err != nil {
+				return false, err
+			}
+			fmt.Printf("Backed up '%s' to '%s'\n", path, backup)
+			return false, nil
+		case "m", "merge":
+			return true, mergeIntoConfig(string(data))
+		case "a", "abort":
+			return false, fmt.Errorf("aborted: '%s' left unchanged", path)
+		}
+		if err != nil {
+			// No answer (e.g. stdin is /dev/null)
+			return false, conflict
+		}
+	}
+}
+
+// isInteractive reports whether the user can answer a prompt on stdin.
+func isInteractive() bool {
+	if flagConfig == config.Stdin {
+		return false
+	}
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// backupPath returns the first free backup name for path (path.bak, path.bak.2, ...).
+func backupPath(path string) string {
+	backup := path + ".bak"
+	for i := 2; ; i++ {
+		if _, err := os.Stat(backup); os.IsNotExist(err) {
+			return backup
+		}
+		backup = fmt.Sprintf("%s.bak.%d", path, i)
+	}
+}
+
+// mergeIntoConfig adds hand-written instructions to the config as a section,
+// so they are kept in every generated file, and to the loaded config.
+func mergeIntoConfig(content string) error {
+	if flagConfig == config.Stdin {
+		return fmt.Errorf("cannot merge into a config read from stdin")
+	}
+	section := config.Section{Title: mergedSectionTitle, Body: strings.TrimSpace(content)}
+	if first, rest, _ := strings.Cut(section.Body, "\n"); strings.HasPrefix(first, "# ") {
+		section.Title = strings.TrimSpace(strings.TrimPrefix(first, "# "))
+		section.Body = strings.TrimSpace(rest)
+	}
+
+	data, err := config.ReadFile(flagConfig)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	out, err := config.AppendSection(data, section)
+	if err != nil {
+		return fmt.Errorf("cannot add a section to %s: %w", flagConfig, err)
+	}
+	if err := writeFile(flagConfig, out); err != nil {
+		return err
+	}
+	cfg.Sections = append(cfg.Sections, section)
+	fmt.Printf("Merged the hand-written instructions into %s as section '%s'\n", flagConfig, section.Title)
+	return nil
+}
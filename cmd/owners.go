@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/gitmeta"
+	"github.com/cego/ai-instructions/internal/warnings"
+)
+
+var flagIncludeOwners bool
+
+// maxOwnerRules limits the CODEOWNERS rules listed; the rest are referred to
+// the file itself.
+const maxOwnerRules = 50
+
+// buildOwnersSection lists the owners of the paths in the CODEOWNERS file of
+// dir, so agents keep a change within one team's paths or flag it. It is
+// empty unless --include-owners is set.
+func buildOwnersSection(dir string) string {
+	if !flagIncludeOwners {
+		return ""
+	}
+
+	path, rules, err := gitmeta.CodeOwners(dir)
+	if err != nil {
+		warnings.Add("codeowners", "%v", err)
+		return ""
+	}
+	if len(rules) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Code ownership\n\n")
+	fmt.Fprintf(&b, "Paths are owned by the teams below (from `%s`; the last matching pattern wins). ", path)
+	b.WriteString("Keep a change within the paths of one owner where possible; when it has to cross an ownership boundary, say so and name the owners that need to review it.\n")
+	for i, rule := range rules {
+		if i == maxOwnerRules {
+			fmt.Fprintf(&b, "\n- …%d more (see `%s`)", len(rules)-maxOwnerRules, path)
+			break
+		}
+		owners := "no owner"
+		if len(rule.Owners) > 0 {
+			owners = strings.Join(rule.Owners, ", ")
+		}
+		fmt.Fprintf(&b, "\n- `%s`: %s", rule.Pattern, owners)
+	}
+	return b.String()
+}
+
+// addIncludeOwnersFlag registers --include-owners on a command that renders instructions.
+func addIncludeOwnersFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flagIncludeOwners,
+		"include-owners",
+		false,
+		"Add a code ownership section from CODEOWNERS, asking agents to flag changes that cross team boundaries",
+	)
+}
+
+func init() {
+	addIncludeOwnersFlag(generateCmd)
+	addIncludeOwnersFlag(validateCmd)
+	addIncludeOwnersFlag(exportCmd)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+)
+
+// Commands the AI should use per JavaScript package manager.
+var nodePackageManagerCommands = map[string]string{
+	"npm":  "`npm install`, `npm install <pkg>`, `npm run <script>`",
+	"yarn": "`yarn install`, `yarn add <pkg>`, `yarn <script>`",
+	"pnpm": "`pnpm install`, `pnpm add <pkg>`, `pnpm <script>`",
+	"bun":  "`bun install`, `bun add <pkg>`, `bun run <script>`",
+}
+
+// buildPackageManagementSection tells the AI which package manager commands to use.
+func buildPackageManagementSection(stack *detect.DetectedStack) string {
+	if stack == nil {
+		return ""
+	}
+
+	var lines []string
+	if stack.Has(detect.Nix) {
+		shell := "`nix develop`"
+		if stack.Version(detect.Nix) != "flake.nix" {
+			shell = "`nix-shell`"
+		}
+		lines = append(lines, fmt.Sprintf("- Tools come from **Nix** (`%s`): run commands inside %s and add missing tools there instead of installing them globally.", stack.Version(detect.Nix), shell))
+	}
+
+	// Bazel owns dependencies and builds; language package managers are not run directly
+	if stack.Has(detect.Bazel) {
+		lines = append(lines, "- Dependencies and builds are managed by **Bazel**: declare dependencies in `MODULE.bazel`/`BUILD.bazel` "+
+			"(language manifests and lockfiles are consumed by Bazel rules), build with `bazel build //...` and test with `bazel test //...`. "+
+			"Do not suggest `npm install`, `composer install` or `go build` as the way to build or test.")
+		return "## Package management\n\n" + strings.Join(lines, "\n")
+	}
+
+	if stack.Has(detect.PackageManager) {
+		name, version, _ := strings.Cut(stack.Version(detect.PackageManager), "@")
+		label := "**" + name + "**"
+		if version != "" {
+			label += " " + version
+		}
+
+		var others []string
+		for _, pm := range []string{"npm", "yarn", "pnpm", "bun"} {
+			if pm != name {
+				others = append(others, pm)
+			}
+		}
+
+		line := fmt.Sprintf("- JavaScript: use %s", label)
+		if cmds, ok := nodePackageManagerCommands[name]; ok {
+			line += " (" + cmds + ")"
+		}
+		line += ". Do not use " + strings.Join(others, ", ") + " or commit their lockfiles."
+		lines = append(lines, line)
+	}
+
+	// PHP from .tool-versions or a Dockerfile alone does not make a Composer project
+	php, _ := stack.Get(detect.PHP)
+	if stack.Has(detect.Composer) || php.Manifest() == "composer.json" || stack.Has(detect.Laravel) {
+		label := "**Composer**"
+		if stack.Has(detect.Composer) {
+			label += " (plugin API " + stack.Version(detect.Composer) + ")"
+		}
+		lines = append(lines, fmt.Sprintf("- PHP: use %s (`composer install`, `composer require <pkg>`, `composer require --dev <pkg>`); never edit `vendor/` or `composer.lock` by hand.", label))
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return "## Package management\n\n" + strings.Join(lines, "\n")
+}
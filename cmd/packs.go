@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/rules"
+)
+
+// flagPacks enables optional rule packs, overriding the config's packs.
+var flagPacks []string
+
+// addPackFlag registers --pack on a command that selects rules from detection.
+func addPackFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(
+		&flagPacks,
+		"pack",
+		nil,
+		"Enable optional rule packs by tag, e.g. a11y (rules with optional: true), overriding the config",
+	)
+}
+
+func init() {
+	addPackFlag(generateCmd)
+	addPackFlag(validateCmd)
+	addPackFlag(exportCmd)
+}
+
+// activePacks returns --pack, or the config's packs when unset.
+func activePacks() []string {
+	if len(flagPacks) > 0 {
+		return normalizeTags(flagPacks)
+	}
+	return normalizeTags(cfg.Packs)
+}
+
+// filterOptional drops optional rules unless an active pack or --tags names
+// one of their tags.
+func filterOptional(ids []string) []string {
+	enabled := append(activePacks(), activeTags()...)
+	var out []string
+	for _, id := range ids {
+		if r, err := rules.Load(id); err == nil && r.Meta.Optional && !matchesTags(r.Meta.Tags, enabled) {
+			continue
+		}
+		out = append(out, id)
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+)
+
+// lighthouseLimits describe the assertion options of Lighthouse CI.
+var lighthouseLimits = map[string]string{
+	"minScore":        "score at least %s",
+	"maxNumericValue": "at most %s",
+	"maxLength":       "at most %s items",
+	"minLength":       "at least %s items",
+}
+
+// lighthouseBudgetUnits are the units of the Lighthouse budgets file.
+var lighthouseBudgetUnits = map[string]string{"timing": "ms", "size": "KB", "count": ""}
+
+// buildPerformanceSection quotes the budgets of the Lighthouse CI and
+// bundlesize configs, so that generated frontend code stays within the
+// limits CI enforces.
+func buildPerformanceSection(stack *detect.DetectedStack) string {
+	if stack == nil || stack.Performance.Empty() {
+		return ""
+	}
+	p := stack.Performance
+
+	var b strings.Builder
+	b.WriteString("## Performance budgets\n\n")
+	b.WriteString("CI enforces these limits; keep new frontend code (dependencies, images, scripts, rendering work) within them and mention in your summary when a change gets close to one.")
+
+	if p.LighthouseConfig != "" {
+		fmt.Fprintf(&b, "\n\n### Lighthouse CI (`%s`)\n", p.LighthouseConfig)
+		if len(p.Assertions) == 0 && len(p.Budgets) == 0 {
+			fmt.Fprintf(&b, "\n- See `%s` for the asserted audits.", p.LighthouseConfig)
+		}
+		for _, a := range p.Assertions {
+			fmt.Fprintf(&b, "\n- `%s`: ", a.Audit)
+			if format, ok := lighthouseLimits[a.Limit]; ok {
+				fmt.Fprintf(&b, format, a.Value)
+			} else {
+				b.WriteString("must pass")
+			}
+			fmt.Fprintf(&b, " (%s)", a.Level)
+		}
+		for _, budget := range p.Budgets {
+			fmt.Fprintf(&b, "\n- %s `%s`: at most %s", budget.Kind, budget.Name, strconv.FormatFloat(budget.Budget, 'f', -1, 64))
+			if unit := lighthouseBudgetUnits[budget.Kind]; unit != "" {
+				b.WriteString(" " + unit)
+			}
+			if budget.Path != "" {
+				fmt.Fprintf(&b, " on `%s`", budget.Path)
+			}
+			fmt.Fprintf(&b, " (`%s`)", p.BudgetsFile)
+		}
+	}
+
+	if p.BundlesizeConfig != "" {
+		fmt.Fprintf(&b, "\n\n### Bundle sizes (bundlesize, `%s`)\n", p.BundlesizeConfig)
+		for _, bundle := range p.Bundles {
+			fmt.Fprintf(&b, "\n- `%s`: at most %s", bundle.Path, bundle.MaxSize)
+			if bundle.Compression != "" {
+				fmt.Fprintf(&b, " (%s)", bundle.Compression)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/jsonc"
+	"github.com/cego/ai-instructions/internal/warnings"
+)
+
+const (
+	// claudeSettingsPath is the Claude Code project settings file the
+	// claude-settings target patches.
+	claudeSettingsPath = ".claude/settings.json"
+	// copilotFirewallPath is the host list for the Copilot coding agent
+	// firewall, which is configured in the repository settings.
+	copilotFirewallPath = ".github/copilot-firewall-allowlist.txt"
+)
+
+// claudeToolRule matches entries that already are Claude Code permission
+// rules, e.g. Read(./secrets/**) or WebFetch(domain:example.com).
+var claudeToolRule = regexp.MustCompile(`^[A-Z][A-Za-z]*\(.*\)$`)
+
+// deniedCommands returns the commands of the config's deny list, then the
+// forbidden commands of the policy, without duplicates.
+func deniedCommands() []string {
+	commands := append([]string(nil), cfg.Permissions.Deny...)
+	if orgPolicy != nil {
+		for _, c := range orgPolicy.ForbiddenCommands {
+			commands = append(commands, c.Command)
+		}
+	}
+	return uniqueTrimmed(commands)
+}
+
+// uniqueTrimmed returns the non-empty trimmed values, first occurrence first.
+func uniqueTrimmed(values []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v != "" && !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// claudeCommandRule turns a command into a Bash rule matching it and its
+// arguments; permission rules are kept as they are.
+func claudeCommandRule(command string) string {
+	if claudeToolRule.MatchString(command) {
+		return command
+	}
+	return "Bash(" + command + ":*)"
+}
+
+// claudePathRules denies reading and editing the files of a secrets pattern
+// of the policy (.gitignore syntax: patterns without a slash match anywhere).
+func claudePathRules(pattern string) []string {
+	path := "./" + strings.TrimPrefix(pattern, "/")
+	if !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		path = "**/" + pattern
+	}
+	// A directory pattern covers everything below it
+	if strings.HasSuffix(path, "/") {
+		path += "**"
+	}
+	return []string{"Read(" + path + ")", "Edit(" + path + ")"}
+}
+
+// buildClaudeSettingsFiles patches .claude/settings.json (or creates it) so
+// that Claude Code enforces the config's permissions: allowed commands and
+// domains are allowed without asking, denied commands and the policy's
+// secret files are denied. Entries already in the file are kept, as are the
+// other members; comments are not.
+func buildClaudeSettingsFiles(*detect.DetectedStack, []target) ([]renderedFile, error) {
+	var allow, deny []string
+	for _, c := range uniqueTrimmed(cfg.Permissions.Allow) {
+		allow = append(allow, claudeCommandRule(c))
+	}
+	for _, d := range uniqueTrimmed(cfg.Permissions.Domains) {
+		allow = append(allow, "WebFetch(domain:"+d+")")
+	}
+	for _, c := range deniedCommands() {
+		deny = append(deny, claudeCommandRule(c))
+	}
+	if orgPolicy != nil {
+		for _, f := range uniqueTrimmed(orgPolicy.Secrets.Files) {
+			deny = append(deny, claudePathRules(f)...)
+		}
+	}
+	if len(allow) == 0 && len(deny) == 0 {
+		warnings.Add("permissions", "no permissions in the config or policy; %s is not generated", claudeSettingsPath)
+		return nil, nil
+	}
+
+	settings := jsonc.NewObject()
+	indent := "  "
+	data, err := os.ReadFile(claudeSettingsPath)
+	switch {
+	case err == nil:
+		if settings, err = jsonc.ParseObject(data); err != nil {
+			return nil, &detect.DetectError{Detector: "claude-settings", Path: claudeSettingsPath, Malformed: true, Err: err}
+		}
+		if !bytes.Equal(jsonc.Standardize(data), data) {
+			warnings.Add("claude-settings", "comments in %s are not kept when it is patched", claudeSettingsPath)
+		}
+		indent = detectIndent(data)
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	permissions := settings.Object("permissions")
+	for _, list := range []struct {
+		key   string
+		rules []string
+	}{{"allow", allow}, {"deny", deny}} {
+		if len(list.rules) == 0 {
+			continue
+		}
+		existing, _ := permissions.Get(list.key)
+		values, _ := existing.([]any)
+		for _, rule := range list.rules {
+			if !containsString(values, rule) {
+				values = append(values, rule)
+			}
+		}
+		permissions.Set(list.key, values)
+	}
+
+	out, err := settings.Indent(indent)
+	if err != nil {
+		return nil, err
+	}
+	return []renderedFile{{Label: "CLAUDE SETTINGS", Path: claudeSettingsPath, Content: string(out)}}, nil
+}
+
+// containsString reports whether list holds the string s.
+func containsString(list []any, s string) bool {
+	for _, v := range list {
+		if str, ok := v.(string); ok && str == s {
+			return true
+		}
+	}
+	return false
+}
+
+// buildCopilotFirewallFiles writes the hosts of the config's permissions as
+// the custom allowlist of the Copilot coding agent firewall. The firewall is
+// configured in the repository settings, so the file is a hint to copy from
+// (or to apply with the GitHub API), one host per line.
+func buildCopilotFirewallFiles(*detect.DetectedStack, []target) ([]renderedFile, error) {
+	domains := uniqueTrimmed(cfg.Permissions.Domains)
+	if len(domains) == 0 {
+		warnings.Add("permissions", "no permissions.domains in the config; %s is not generated", copilotFirewallPath)
+		return nil, nil
+	}
+
+	var b strings.Builder
+	b.WriteString("# Generated by ai-instructions. Do not edit manually.\n")
+	b.WriteString("# Custom allowlist of the Copilot coding agent firewall: add these hosts under\n")
+	b.WriteString("# Settings > Copilot > Coding agent > Custom allowlist.\n")
+	for _, d := range domains {
+		b.WriteString(d + "\n")
+	}
+	return []renderedFile{{Label: "COPILOT FIREWALL", Path: copilotFirewallPath, Content: b.String()}}, nil
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cego/ai-instructions/internal/policy"
+)
+
+// orgPolicy is the loaded organization policy (nil when there is none).
+var orgPolicy *policy.Policy
+
+// policyPath returns the policy file of the config, or defaultPath when the
+// config names none.
+func policyPath(defaultPath string) string {
+	if cfg.Policy != "" {
+		return cfg.Policy
+	}
+	return defaultPath
+}
+
+// loadPolicy loads and validates the policy file (see policyPath); a missing
+// default file means no policy, one named by the config must exist.
+func loadPolicy(defaultPath string) error {
+	orgPolicy = nil
+	path := policyPath(defaultPath)
+	if path == "" {
+		return nil
+	}
+	loaded, err := policy.Load(path)
+	if err != nil {
+		return err
+	}
+	if loaded == nil && cfg.Policy != "" {
+		return fmt.Errorf("policy file '%s' (from the config) does not exist", cfg.Policy)
+	}
+	orgPolicy = loaded
+	return nil
+}
+
+// buildGuardrailsSection renders the Guardrails section of the policy.
+func buildGuardrailsSection() string {
+	return orgPolicy.Section()
+}
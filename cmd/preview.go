@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/markdown"
+	"github.com/cego/ai-instructions/internal/watch"
+)
+
+var flagPreviewAddr string
+
+var previewCmd = &cobra.Command{
+	Use:     "preview",
+	Short:   "Serve the generated instructions as HTML on localhost with live reload",
+	GroupID: groupGeneration,
+	Example: "  ai-instructions preview --addr 127.0.0.1:8787",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", handlePreview)
+		mux.HandleFunc("/__version", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, previewFingerprint())
+		})
+
+		server := &http.Server{Addr: flagPreviewAddr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		fmt.Printf("Previewing instructions on http://%s (Ctrl+C to stop)\n", flagPreviewAddr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(previewCmd)
+
+	previewCmd.Flags().StringVar(
+		&flagPreviewAddr,
+		"addr",
+		"127.0.0.1:8787",
+		"Address to listen on",
+	)
+}
+
+// previewFingerprint changes whenever local rules, the config or manifests change.
+func previewFingerprint() string {
+	sum := sha256.Sum256([]byte(watch.Fingerprint(append([]string{flagRulesDir, flagConfig}, watchedManifests...))))
+	return hex.EncodeToString(sum[:8])
+}
+
+func handlePreview(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := previewContent()
+	if err != nil {
+		body = "<p><strong>Error:</strong> " + html.EscapeString(err.Error()) + "</p>"
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, previewPage, body, previewFingerprint())
+}
+
+// Serializes reloads of the shared config and rules between requests.
+var previewMu sync.Mutex
+
+// previewContent renders the current instructions, re-reading all inputs.
+func previewContent() (string, error) {
+	previewMu.Lock()
+	defer previewMu.Unlock()
+
+	if err := reloadInputs(); err != nil {
+		return "", err
+	}
+	stack, err := detect.DetectStack(".")
+	if err != nil {
+		return "", err
+	}
+	content, err := buildStackContent(".", stack)
+	if err != nil {
+		return "", err
+	}
+	if content == "" {
+		return "", fmt.Errorf("no rules apply to the detected stack")
+	}
+	return markdown.ToHTML(wrapBoilerplate(content)), nil
+}
+
+const previewPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>ai-instructions preview</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 860px; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #1f2328; }
+pre { background: #f6f8fa; padding: 1rem; overflow: auto; border-radius: 6px; }
+code { background: #f6f8fa; padding: .1em .3em; border-radius: 4px; }
+pre code { padding: 0; }
+hr { border: 0; border-top: 1px solid #d0d7de; margin: 2rem 0; }
+</style>
+</head>
+<body>
+%s
+<script>
+(function () {
+  var version = %q;
+  setInterval(function () {
+    fetch("/__version").then(function (r) { return r.text(); }).then(function (v) {
+      if (v !== version) { location.reload(); }
+    }).catch(function () {});
+  }, 1000);
+})();
+</script>
+</body>
+</html>
+`
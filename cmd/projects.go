@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+)
+
+var flagPerProject bool
+
+// subprojectFile is the AGENTS.md generated for one subproject in per-project mode.
+type subprojectFile struct {
+	Project detect.Project
+	Path    string
+	Content string
+	// Unchanged is set for subprojects outside the --paths scope: they are
+	// still linked from the root AGENTS.md, but their file is not rendered.
+	Unchanged bool
+}
+
+// buildStackContent returns the instructions for the stack detected in dir: the
+// stack section followed by the merged general rules (empty when no rules apply).
+func buildStackContent(dir string, stack *detect.DetectedStack) (string, error) {
+	ids := buildGeneralRulesFromDetection(stack)
+	if len(ids) == 0 {
+		return "", nil
+	}
+
+	content, err := loadAndMergeRules(ids)
+	if err != nil {
+		return "", err
+	}
+
+	if detected := buildDetectedSections(dir, stack); detected != "" {
+		content = detected + "\n\n---\n\n" + content
+	}
+	return content, nil
+}
+
+// buildSubprojectFiles computes an AGENTS.md scoped to the stack of every
+// subproject below projectRoot that has applicable rules. Subprojects the
+// scope does not include are returned unchanged, without merging their rules.
+func buildSubprojectFiles(projectRoot string, scope pathScope) ([]subprojectFile, error) {
+	projects, err := detect.DetectProjects(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []subprojectFile
+	for _, p := range projects {
+		if !scope.Includes(p.Path) {
+			if len(buildGeneralRulesFromDetection(p.Stack)) > 0 {
+				files = append(files, subprojectFile{Project: p, Path: path.Join(p.Path, "AGENTS.md"), Unchanged: true})
+			}
+			continue
+		}
+		content, err := buildStackContent(filepath.Join(projectRoot, p.Path), p.Stack)
+		if err != nil {
+			return nil, err
+		}
+		if content == "" {
+			continue
+		}
+		files = append(files, subprojectFile{
+			Project: p,
+			Path:    path.Join(p.Path, "AGENTS.md"),
+			Content: content,
+		})
+	}
+	return files, nil
+}
+
+// buildSubprojectsSection summarizes and links the subproject AGENTS.md files
+// from the root AGENTS.md.
+func buildSubprojectsSection(files []subprojectFile) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Subprojects\n\n")
+	b.WriteString("Each subproject has its own AGENTS.md scoped to its stack. Follow the nearest one when working inside it.\n")
+	for _, f := range files {
+		fmt.Fprintf(&b, "\n- [%s](%s)", f.Project.Path, f.Path)
+		if summary := stackSummary(f.Project.Stack); summary != "" {
+			b.WriteString(" — ")
+			b.WriteString(summary)
+		}
+		if deps := f.Project.Dependencies; len(deps) > 0 {
+			b.WriteString(" (depends on ")
+			b.WriteString(strings.Join(deps, ", "))
+			b.WriteString(")")
+		}
+	}
+	return b.String()
+}
+
+// stackSummary returns a one-line description like "PHP ^8.3, Laravel ^11.0".
+func stackSummary(stack *detect.DetectedStack) string {
+	section := buildStackSection(stack)
+	var parts []string
+	for _, line := range strings.Split(section, "\n") {
+		if strings.HasPrefix(line, "- ") {
+			parts = append(parts, strings.Replace(strings.TrimPrefix(line, "- "), ": ", " ", 1))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderSubprojects returns the subproject AGENTS.md files as output files.
+func renderSubprojects(subprojects []subprojectFile, assetsDir string) []renderedFile {
+	var files []renderedFile
+	for _, sub := range subprojects {
+		if sub.Unchanged {
+			continue
+		}
+		files = append(files, renderedFile{
+			Label:   "AGENTS",
+			Path:    sub.Path,
+			Content: wrapBoilerplate(resolveAssetLinks(sub.Content, sub.Path, assetsDir)),
+		})
+	}
+	return files
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/rules"
+)
+
+// Rules below promptsPrefix are rendered to Copilot prompt files in promptsDir.
+const (
+	promptsPrefix = "prompts/"
+	promptsDir    = ".github/prompts"
+)
+
+// buildPromptFiles renders the prompts whose `when:` condition holds for the stack.
+func buildPromptFiles(stack *detect.DetectedStack, _ []target) ([]renderedFile, error) {
+	ids, err := rules.Glob(promptsPrefix + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []renderedFile
+	for _, id := range filterApplicable(ids, stack) {
+		f, err := renderPromptFile(id)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// renderPromptFile renders a prompt rule as .github/prompts/<name>.prompt.md.
+func renderPromptFile(id string) (renderedFile, error) {
+	r, err := rules.Load(id)
+	if err != nil {
+		return renderedFile{}, err
+	}
+
+	mode := r.Meta.Mode
+	if mode == "" {
+		mode = "agent"
+	}
+
+	content := frontMatter([][2]string{
+		{"mode", mode},
+		{"description", yamlQuote(r.Meta.Description)},
+		{"tools", yamlList(r.Meta.Tools)},
+		{"model", yamlQuote(r.Meta.Model)},
+	}) + rewriteRuleAssets(id, r.Body)
+
+	return renderedFile{
+		Label:   "COPILOT PROMPT",
+		Path:    path.Join(promptsDir, path.Base(id)+".prompt.md"),
+		Content: content,
+	}, nil
+}
+
+// frontMatter renders "key: value" pairs as a YAML front matter block,
+// skipping empty values.
+func frontMatter(fields [][2]string) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	for _, f := range fields {
+		if f[1] != "" {
+			b.WriteString(f[0] + ": " + f[1] + "\n")
+		}
+	}
+	b.WriteString("---\n\n")
+	return b.String()
+}
+
+// yamlQuote returns s as a double-quoted YAML string (empty stays empty).
+func yamlQuote(s string) string {
+	if s == "" {
+		return ""
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// yamlList returns items as a YAML flow sequence of quoted strings (empty stays empty).
+func yamlList(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = yamlQuote(item)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+var promptsCmd = &cobra.Command{
+	Use:     "prompts",
+	Short:   "List and add Copilot prompt files (.github/prompts/*.prompt.md)",
+	GroupID: groupRules,
+	Example: "  ai-instructions prompts list\n" +
+		"  ai-instructions prompts add write-go-test",
+}
+
+var promptsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available prompts and whether they apply to the detected stack",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := rules.Glob(promptsPrefix + "*")
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			fmt.Println("No prompts found.")
+			return nil
+		}
+
+		stack, err := detect.DetectStack(".")
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PROMPT\tAPPLIES\tDESCRIPTION")
+		for _, id := range ids {
+			desc := ""
+			if r, err := rules.Load(id); err == nil {
+				desc = r.Meta.Description
+			}
+			applies := "no"
+			if ruleApplies(id, stack) {
+				applies = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", strings.TrimPrefix(id, promptsPrefix), applies, desc)
+		}
+		return w.Flush()
+	},
+}
+
+var promptsAddCmd = &cobra.Command{
+	Use:   "add <prompt>...",
+	Short: "Write prompts to " + promptsDir + ", whether or not they apply to the detected stack",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, name := range args {
+			id := promptsPrefix + strings.TrimSuffix(strings.TrimPrefix(name, promptsPrefix), ".prompt.md")
+			if !ruleExists(id) {
+				return fmt.Errorf("unknown prompt '%s' (see 'prompts list')", name)
+			}
+
+			f, err := renderPromptFile(id)
+			if err != nil {
+				return err
+			}
+			content, err := guardSecrets(f.Path, f.Content)
+			if err != nil {
+				return err
+			}
+			if err := writeFileWithDirs(f.Path, []byte(content)); err != nil {
+				return err
+			}
+			fmt.Printf("Prompt written to %s\n", f.Path)
+		}
+		return nil
+	},
+}
+
+func init() {
+	promptsCmd.AddCommand(promptsListCmd)
+	promptsCmd.AddCommand(promptsAddCmd)
+	rootCmd.AddCommand(promptsCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/markdown"
+	"github.com/cego/ai-instructions/internal/warnings"
+)
+
+// flagAllowRawHTML keeps raw HTML in rule content as written.
+var flagAllowRawHTML bool
+
+// addAllowRawHTMLFlag registers --allow-raw-html on a command that renders instructions.
+func addAllowRawHTMLFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flagAllowRawHTML,
+		"allow-raw-html",
+		false,
+		"Keep raw HTML from rules as is (by default script-like elements are removed, unsafe attributes stripped and unknown tags escaped)",
+	)
+}
+
+func init() {
+	addAllowRawHTMLFlag(generateCmd)
+	addAllowRawHTMLFlag(validateCmd)
+	addAllowRawHTMLFlag(renderCmd)
+}
+
+// sanitizeFiles sanitizes the raw HTML of the markdown outputs, since
+// imported rule packs may carry HTML that misbehaves when the instructions
+// are displayed in web UIs.
+func sanitizeFiles(files []renderedFile) []renderedFile {
+	if flagAllowRawHTML {
+		return files
+	}
+	for i, f := range files {
+		if !strings.HasSuffix(f.Path, ".md") {
+			continue
+		}
+		content, changed := markdown.SanitizeHTML(f.Content)
+		if len(changed) > 0 {
+			warnings.Add("html", "sanitized raw HTML in %s (%s); use --allow-raw-html to keep it", f.Path, strings.Join(changed, ", "))
+			files[i].Content = content
+		}
+	}
+	return files
+}
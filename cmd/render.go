@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/archive"
+	"github.com/cego/ai-instructions/internal/config"
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/rules"
+)
+
+var (
+	flagRenderStack        string
+	flagRenderRulesArchive string
+	flagRenderOutDir       string
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Single-shot generation from explicit inputs, for hermetic builds and sidecars",
+	Long: "Generates the instruction files from explicit inputs only: the stack as JSON (as printed by\n" +
+		"'detect --json'), an optional config (--config) and an optional rules archive\n" +
+		"(zip, tar or tar.gz layered over the embedded rules). Use '-' to read one input from stdin.\n" +
+		"Everything is written below --out-dir; the working directory is never read.",
+	// Replaces the root hook: no implicit config or local rules from the working directory
+	GroupID: groupGeneration,
+	Example: "  ai-instructions detect --json > stack.json\n" +
+		"  ai-instructions render --stack stack.json --out-dir out",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cfg = &config.Config{}
+		if cmd.Flags().Changed("config") {
+			loaded, err := config.Load(flagConfig)
+			if err != nil {
+				return err
+			}
+			cfg = loaded
+		}
+		if err := checkMinVersion(); err != nil {
+			return err
+		}
+		rules.SetExperiments(activeExperiments())
+		// Only a policy named by the config is read
+		if err := loadPolicy(""); err != nil {
+			return err
+		}
+
+		rules.SetLocalDir("")
+		if cmd.Flags().Changed("rules-dir") {
+			return useLocalRules(flagRulesDir)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagRenderStack == "" || flagRenderOutDir == "" {
+			return fmt.Errorf("--stack and --out-dir are required")
+		}
+		stdinInputs := 0
+		for _, input := range []string{flagRenderStack, flagRenderRulesArchive, flagConfig} {
+			if input == "-" {
+				stdinInputs++
+			}
+		}
+		if stdinInputs > 1 {
+			return fmt.Errorf("only one of --stack, --rules-archive and --config can be read from stdin")
+		}
+
+		data, err := readInput(flagRenderStack)
+		if err != nil {
+			return err
+		}
+		var stack detect.DetectedStack
+		if err := json.Unmarshal(data, &stack); err != nil {
+			return fmt.Errorf("invalid stack JSON: %w", err)
+		}
+
+		if flagRenderRulesArchive != "" {
+			dir, err := os.MkdirTemp("", "ai-instructions-rules-")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(dir)
+
+			data, err := readInput(flagRenderRulesArchive)
+			if err != nil {
+				return err
+			}
+			if err := archive.Extract(data, dir); err != nil {
+				return fmt.Errorf("rules archive: %w", err)
+			}
+			rules.SetLocalDir(dir)
+		}
+
+		// The env section is never enabled here, so dir is not read
+		content, err := buildStackContent(".", &stack)
+		if err != nil {
+			return err
+		}
+		if content == "" {
+			return fmt.Errorf("no rules apply to the given stack")
+		}
+
+		selected, err := selectedTargets(flagTargets)
+		if err != nil {
+			return err
+		}
+		if len(selected) == 0 {
+			fmt.Println("All targets disabled – nothing to generate.")
+			return nil
+		}
+
+		copilotPath := ".github/copilot-instructions.md"
+		assetsDir := assetsDirFor(copilotPath)
+
+		categoryContents, err := buildCategoryContents(categoryRuleIDs(&stack), &stack)
+		if err != nil {
+			return err
+		}
+
+		agentsContent, err := addAgentLevel(content)
+		if err != nil {
+			return err
+		}
+
+		files := renderTargets(selected, content, agentsContent, "", categoryContents, copilotPath, assetsDir)
+		fileTargets, err := renderFileTargets(selected, &stack)
+		if err != nil {
+			return err
+		}
+		files, err = finalizeFiles(append(files, fileTargets...))
+		if err != nil {
+			return err
+		}
+		for i, f := range files {
+			files[i].Path = filepath.Join(flagRenderOutDir, filepath.FromSlash(f.Path))
+		}
+		if err := writeRenderedFiles(files); err != nil {
+			return err
+		}
+		for _, f := range files {
+			fmt.Printf("%s documentation written to %s\n", f.Label, f.Path)
+		}
+
+		all := joinCategoryContents(content, categoryContents)
+		if len(referencedAssets(all)) > 0 {
+			if err := writeAssets(all, filepath.Join(flagRenderOutDir, assetsDir)); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// readInput reads a file, or stdin for "-".
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+
+	renderCmd.Flags().StringVar(
+		&flagRenderStack,
+		"stack",
+		"",
+		"Stack JSON file, e.g. {\"php\":\"^8.3\",\"laravel\":\"^11.0\"} ('-' for stdin)",
+	)
+
+	renderCmd.Flags().StringVar(
+		&flagRenderRulesArchive,
+		"rules-archive",
+		"",
+		"Archive (zip, tar, tar.gz) of rules layered over the embedded rules ('-' for stdin)",
+	)
+
+	renderCmd.Flags().StringVar(
+		&flagRenderOutDir,
+		"out-dir",
+		"",
+		"Directory the output tree is written to",
+	)
+
+	addTargetFlags(renderCmd, "render")
+	addRedactFlag(renderCmd)
+}
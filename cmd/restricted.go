@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/aiignore"
+	"github.com/cego/ai-instructions/internal/warnings"
+)
+
+// loadAIIgnore returns the .aiignore of dir (nil when there is none or it
+// cannot be read).
+func loadAIIgnore(dir string) *aiignore.List {
+	ignore, err := aiignore.Load(dir)
+	if err != nil {
+		warnings.Add("aiignore", "%v", err)
+	}
+	return ignore
+}
+
+// buildRestrictedSection lists the paths of the .aiignore in dir, which
+// agents must neither read nor modify. It is empty without an .aiignore.
+func buildRestrictedSection(dir string) string {
+	ignore := loadAIIgnore(dir)
+	if ignore.Empty() {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Restricted paths\n\n")
+	fmt.Fprintf(&b, "Never read, modify or create files matching these patterns (from `%s`, .gitignore syntax), ", aiignore.File)
+	b.WriteString("and do not copy their contents into code, commits or prompts. When a task requires a change there, stop and ask a maintainer.\n")
+	for _, pattern := range ignore.Patterns {
+		fmt.Fprintf(&b, "\n- `%s`", pattern)
+	}
+	return b.String()
+}
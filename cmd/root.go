@@ -3,19 +3,120 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/config"
+	"github.com/cego/ai-instructions/internal/policy"
+	"github.com/cego/ai-instructions/internal/warnings"
+	"github.com/cego/ai-instructions/rules"
+)
+
+// version is the CLI version, set at build time with -ldflags "-X github.com/cego/ai-instructions/cmd.version=...".
+var version = "dev"
+
+// Default location of project-local rules layered over the embedded rules.
+const defaultRulesDir = ".ai-instructions/rules"
+
+var (
+	flagRulesDir string
+	flagConfig   string
+	flagReadOnly bool
 )
 
+// cfg is the loaded project configuration (empty when there is no config file).
+var cfg = &config.Config{}
+
 var rootCmd = &cobra.Command{
 	Use:   "ai-instructions",
 	Short: "AI Instructions CLI for stack detection and config generation",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		configPath, baseDir := flagConfig, ""
+		if flagRulesSource != "" {
+			dir, err := openRulesSource(flagRulesSource)
+			if err != nil {
+				return err
+			}
+			baseDir = filepath.Join(dir, bundleRulesDir)
+			// The pinned config applies unless another one is given explicitly
+			if !cmd.Flags().Changed("config") {
+				configPath = filepath.Join(dir, bundleConfig)
+			}
+		}
+		rules.SetBaseDir(baseDir)
+
+		loaded, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+		cfg = loaded
+		if err := checkMinVersion(); err != nil {
+			return err
+		}
+		rules.SetExperiments(activeExperiments())
+		if err := loadPolicy(policy.DefaultPath); err != nil {
+			return err
+		}
+
+		return useLocalRules(flagRulesDir)
+	},
+}
+
+func init() {
+	// Runs for every command, including those replacing the root hook
+	cobra.OnInitialize(func() {
+		if flagReadOnly {
+			enableReadOnly()
+		}
+	})
+
+	rootCmd.PersistentFlags().BoolVar(
+		&flagReadOnly,
+		"read-only",
+		false,
+		"Refuse to create or modify any file (for audits on read-only mounts)",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&flagConfig,
+		"config",
+		config.DefaultPath,
+		"Project config file ('-' reads it from stdin)",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&flagRulesDir,
+		"rules-dir",
+		defaultRulesDir,
+		"Directory with project-local rules that extend and override the embedded rules",
+	)
+}
+
+// useLocalRules enables the local rules directory when it exists.
+func useLocalRules(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			rules.SetLocalDir("")
+			return nil
+		}
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("rules directory '%s' is not a directory", dir)
+	}
+	rules.SetLocalDir(dir)
+	return nil
 }
 
 // Execute This is our required entrypoint, for Cobra CLI
 func Execute() {
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	closeRulesSource()
+	warnings.PrintSummary(os.Stderr)
+	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
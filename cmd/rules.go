@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/condition"
+	"github.com/cego/ai-instructions/internal/links"
+	"github.com/cego/ai-instructions/internal/markdown"
+	"github.com/cego/ai-instructions/internal/quality"
+	"github.com/cego/ai-instructions/internal/terms"
+	"github.com/cego/ai-instructions/internal/warnings"
+	"github.com/cego/ai-instructions/rules"
+)
+
+var (
+	flagLintCheckLinks bool
+	flagLintMinScore   int
+)
+
+var rulesCmd = &cobra.Command{
+	Use:     "rules",
+	Short:   "Inspect and maintain the embedded rule files",
+	GroupID: groupRules,
+	Example: "  ai-instructions rules lint\n" +
+		"  ai-instructions rules matrix\n" +
+		"  ai-instructions rules children laravel",
+}
+
+var rulesLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Lint all embedded rule files (links, structure)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Lint every file as written, experiment variants included
+		rules.SetExperiments(nil)
+		ids, err := rules.Files()
+		if err != nil {
+			return err
+		}
+
+		var failures int
+
+		for _, id := range ids {
+			if base, variant := rules.SplitVariant(id); variant != "" && !ruleExists(base) {
+				fmt.Printf("rules/%s.md: variant of unknown rule '%s'\n", id, base)
+				failures++
+			}
+		}
+
+		problems := checkRuleLinks(ids, flagLintCheckLinks)
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+		failures += len(problems)
+
+		for _, id := range ids {
+			r, err := rules.Load(id)
+			if err != nil {
+				fmt.Println(err)
+				failures++
+				continue
+			}
+			if r.Meta.When != "" {
+				if err := condition.Validate(r.Meta.When); err != nil {
+					fmt.Printf("rules/%s.md: invalid when condition: %v\n", id, err)
+					failures++
+				}
+			}
+			if a := r.Meta.Audience; a != "" && a != rules.AudienceAuthor && a != rules.AudienceReviewer {
+				fmt.Printf("rules/%s.md: unknown audience '%s' (use %s or %s)\n", id, a, rules.AudienceAuthor, rules.AudienceReviewer)
+				failures++
+			}
+			for _, req := range r.Meta.Requires {
+				if req != rules.RequireTypeScriptStrict {
+					fmt.Printf("rules/%s.md: unknown requirement '%s'\n", id, req)
+					failures++
+				}
+			}
+			if e := r.Meta.Expires; e != "" {
+				if _, err := time.Parse(rules.ExpiresLayout, e); err != nil {
+					fmt.Printf("rules/%s.md: invalid expires '%s' (use YYYY-MM-DD)\n", id, e)
+					failures++
+				} else if r.Meta.Expired(time.Now()) {
+					warnings.Add("rules", "rules/%s.md expired on %s; remove the rule", id, e)
+				}
+			}
+			headings := map[string]bool{}
+			for _, sec := range markdown.Sections(r.Body) {
+				headings[strings.ToLower(sec.Heading)] = true
+			}
+			for heading := range r.Meta.SectionTags {
+				if !headings[strings.ToLower(strings.TrimSpace(heading))] {
+					fmt.Printf("rules/%s.md: sectionTags names unknown section '%s'\n", id, heading)
+					failures++
+				}
+			}
+			for _, o := range r.Meta.Overrides {
+				if (o.Section == "") == (o.Bullet == "") {
+					fmt.Printf("rules/%s.md: override must set exactly one of section or bullet\n", id)
+					failures++
+				}
+				if o.Rule != "" && !ruleExists(o.Rule) {
+					fmt.Printf("rules/%s.md: override targets unknown rule '%s'\n", id, o.Rule)
+					failures++
+				}
+			}
+		}
+
+		sizeProblems, err := checkRuleSizes(cfg.Lint.MaxRuleBytes, cfg.Lint.MaxTotalBytes)
+		if err != nil {
+			return err
+		}
+		for _, p := range sizeProblems {
+			fmt.Println(p)
+		}
+		failures += len(sizeProblems)
+
+		// Spelling and terminology
+		dict := terms.Default.Merge(terms.Dictionary{
+			Terminology: cfg.Lint.Terminology,
+			Spelling:    cfg.Lint.Spelling,
+		})
+		for _, id := range ids {
+			data, err := rules.Get(id)
+			if err != nil {
+				continue
+			}
+			for _, v := range terms.Check(id, data, dict) {
+				fmt.Println(v)
+				failures++
+			}
+		}
+
+		// Quality metrics (warnings unless below --min-score)
+		bodies := map[string]string{}
+		for _, id := range ids {
+			if data, err := rules.Get(id); err == nil {
+				bodies[id] = data
+			}
+		}
+		report := quality.Analyze(bodies, quality.DefaultOptions)
+		for _, f := range report.Findings {
+			fmt.Printf("warning: %s\n", f)
+		}
+
+		fmt.Println("\nQuality scores:")
+		for _, id := range ids {
+			score, ok := report.Scores[id]
+			if !ok {
+				continue
+			}
+			fmt.Printf("  %3d  %s\n", score, id)
+			if score < flagLintMinScore {
+				fmt.Printf("rules/%s.md: quality score %d is below the minimum of %d\n", id, score, flagLintMinScore)
+				failures++
+			}
+		}
+		fmt.Println()
+
+		if failures > 0 {
+			return fmt.Errorf("rules lint failed: %d problem(s)", failures)
+		}
+
+		fmt.Printf("Linted %d rule file(s): no errors found.\n", len(ids))
+		return nil
+	},
+}
+
+var rulesMatrixCmd = &cobra.Command{
+	Use:   "matrix",
+	Short: "Show which frameworks and versions have general.md and agent.md rules",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := rules.List()
+		if err != nil {
+			return err
+		}
+
+		rows := buildRulesMatrix(ids)
+		if len(rows) == 0 {
+			fmt.Println("No rules found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "FRAMEWORK\tVERSION\tGENERAL\tAGENT")
+		var gaps []string
+		for _, r := range rows {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Framework, r.versionLabel(), presence(r.General), presence(r.Agent))
+			if !r.General {
+				gaps = append(gaps, r.prefix()+"/general.md")
+			}
+			if !r.Agent {
+				gaps = append(gaps, r.prefix()+"/agent.md")
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if len(gaps) > 0 {
+			fmt.Printf("\nGaps (%d):\n", len(gaps))
+			for _, g := range gaps {
+				fmt.Printf("- rules/%s\n", g)
+			}
+		}
+		return nil
+	},
+}
+
+var rulesChildrenCmd = &cobra.Command{
+	Use:   "children [prefix]",
+	Short: "List the rule files and version directories directly below a prefix (e.g. laravel)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix := ""
+		if len(args) == 1 {
+			prefix = args[0]
+		}
+		children, err := rules.Children(prefix)
+		if err != nil {
+			return err
+		}
+		if len(children) == 0 {
+			return fmt.Errorf("no rules below '%s'", prefix)
+		}
+		for _, c := range children {
+			fmt.Println(c)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rulesCmd)
+	rulesCmd.AddCommand(rulesLintCmd)
+	rulesCmd.AddCommand(rulesMatrixCmd)
+	rulesCmd.AddCommand(rulesChildrenCmd)
+
+	rulesLintCmd.Flags().BoolVar(
+		&flagLintCheckLinks,
+		"check-links",
+		false,
+		"Also verify external links with HTTP HEAD requests",
+	)
+
+	rulesLintCmd.Flags().IntVar(
+		&flagLintMinScore,
+		"min-score",
+		0,
+		"Fail when a rule's quality score (0-100) is below this value",
+	)
+}
+
+// checkRuleLinks verifies the links of the given rules, optionally over HTTP.
+func checkRuleLinks(ids []string, checkHTTP bool) []links.Problem {
+	opts := links.Options{
+		ProjectRoot: ".",
+		RuleExists:  ruleExists,
+		AssetExists: assetExists,
+		HTTP:        checkHTTP,
+	}
+
+	var problems []links.Problem
+	for _, id := range ids {
+		data, err := rules.Get(id)
+		if err != nil {
+			continue
+		}
+		problems = append(problems, links.Check(id, data, opts)...)
+	}
+	return problems
+}
+
+// checkRuleSizes reports rule files larger than maxRule and a total size of
+// all rule files and assets above maxTotal (0 selects the defaults).
+func checkRuleSizes(maxRule, maxTotal int) ([]string, error) {
+	if maxRule <= 0 {
+		maxRule = rules.DefaultMaxRuleBytes
+	}
+	if maxTotal <= 0 {
+		maxTotal = rules.DefaultMaxTotalBytes
+	}
+
+	files, err := rules.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	total := 0
+	for _, name := range names {
+		size := len(files[name])
+		total += size
+		if strings.HasSuffix(name, ".md") && size > maxRule {
+			problems = append(problems, fmt.Sprintf("rules/%s: %d bytes exceeds the maximum rule size of %d bytes", name, size, maxRule))
+		}
+	}
+	if total > maxTotal {
+		problems = append(problems, fmt.Sprintf("rules: %d bytes in total exceeds the maximum of %d bytes", total, maxTotal))
+	}
+	return problems, nil
+}
+
+// matrixRow is one framework/version combination in the rules matrix.
+type matrixRow struct {
+	Framework string
+	Version   string // empty for the framework base
+	General   bool
+	Agent     bool
+}
+
+func (r matrixRow) prefix() string {
+	if r.Version == "" {
+		return r.Framework
+	}
+	return r.Framework + "/" + r.Version
+}
+
+func (r matrixRow) versionLabel() string {
+	if r.Version == "" {
+		return "(base)"
+	}
+	return r.Version
+}
+
+// buildRulesMatrix groups general/agent rule IDs by framework and version.
+func buildRulesMatrix(ids []string) []matrixRow {
+	index := map[string]*matrixRow{}
+	var keys []string
+
+	for _, id := range ids {
+		parts := strings.Split(id, "/")
+		if len(parts) < 2 {
+			continue
+		}
+		kind := parts[len(parts)-1]
+		if kind != "general" && kind != "agent" {
+			continue
+		}
+
+		key := strings.Join(parts[:len(parts)-1], "/")
+		row, ok := index[key]
+		if !ok {
+			row = &matrixRow{Framework: parts[0], Version: strings.Join(parts[1:len(parts)-1], "/")}
+			index[key] = row
+			keys = append(keys, key)
+		}
+		if kind == "general" {
+			row.General = true
+		} else {
+			row.Agent = true
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := index[keys[i]], index[keys[j]]
+		if a.Framework != b.Framework {
+			return a.Framework < b.Framework
+		}
+		return compareVersions(a.Version, b.Version) < 0
+	})
+
+	rows := make([]matrixRow, 0, len(keys))
+	for _, k := range keys {
+		rows = append(rows, *index[k])
+	}
+	return rows
+}
+
+// compareVersions orders dotted numeric versions numerically ("" first).
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		ai, aerr := strconv.Atoi(as[i])
+		bi, berr := strconv.Atoi(bs[i])
+		if aerr == nil && berr == nil {
+			if ai != bi {
+				return ai - bi
+			}
+			continue
+		}
+		if c := strings.Compare(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return len(as) - len(bs)
+}
+
+func presence(ok bool) string {
+	if ok {
+		return "yes"
+	}
+	return "MISSING"
+}
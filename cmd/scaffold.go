@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/watch"
+)
+
+var (
+	flagScaffoldTimeout time.Duration
+	flagScaffoldSettle  time.Duration
+)
+
+// Manifests project generators write; detection needs at least one of them.
+var scaffoldManifests = append([]string{"go.mod"}, watchedManifests...)
+
+var postScaffoldCmd = &cobra.Command{
+	Use:   "post-scaffold [dir]",
+	Short: "Generate instructions right after a project generator (laravel new, nuxi init) has run",
+	Long: "Waits until a manifest (composer.json, package.json, go.mod) exists in the new project and\n" +
+		"has stopped changing, then detects the stack, generates the instruction files and prints the\n" +
+		"next steps. Meant to be called from project-bootstrap tooling right after the generator.",
+	GroupID: groupGeneration,
+	Example: "  laravel new blog && ai-instructions post-scaffold blog",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		ctx, cancel := context.WithTimeout(ctx, flagScaffoldTimeout)
+		defer cancel()
+
+		// The generator may still be creating the directory itself
+		for {
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("directory '%s' did not appear within %s", dir, flagScaffoldTimeout)
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+		if err := os.Chdir(dir); err != nil {
+			return err
+		}
+
+		fmt.Printf("Waiting for project manifests in %s...\n", dir)
+		err := watch.WaitStable(ctx, scaffoldManifests, 500*time.Millisecond, flagScaffoldSettle, func() bool {
+			return len(existingFiles(scaffoldManifests)) > 0
+		})
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("no manifest (%s) appeared in '%s' within %s", strings.Join(scaffoldManifests, ", "), dir, flagScaffoldTimeout)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Config and local rules belong to the new project, not the caller's directory
+		if err := reloadInputs(); err != nil {
+			return err
+		}
+		if err := runGenerate(); err != nil {
+			return err
+		}
+
+		stack, err := detect.DetectStack(".")
+		if err != nil {
+			return err
+		}
+		printScaffoldNextSteps(stack)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(postScaffoldCmd)
+
+	postScaffoldCmd.Flags().DurationVar(
+		&flagScaffoldTimeout,
+		"timeout",
+		5*time.Minute,
+		"How long to wait for the generator to write a manifest",
+	)
+
+	postScaffoldCmd.Flags().DurationVar(
+		&flagScaffoldSettle,
+		"settle",
+		2*time.Second,
+		"How long manifests must stay unchanged before generating",
+	)
+
+	addTargetFlags(postScaffoldCmd, "generate")
+}
+
+// existingFiles returns the paths that exist.
+func existingFiles(paths []string) []string {
+	var out []string
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// printScaffoldNextSteps tells the developer how to keep the instructions current.
+func printScaffoldNextSteps(stack *detect.DetectedStack) {
+	fmt.Println()
+	if summary := stackSummary(stack); summary != "" {
+		fmt.Printf("Detected: %s\n", summary)
+	}
+	fmt.Println("Next steps:")
+
+	step := 1
+	next := func(format string, a ...any) {
+		fmt.Printf("  %d. %s\n", step, fmt.Sprintf(format, a...))
+		step++
+	}
+
+	next("Review and commit the generated instruction files.")
+	if _, err := os.Stat(".git"); err == nil {
+		next("Run '%s hooks install' so commits fail when the files get out of date.", rootCmd.Name())
+	} else {
+		next("Initialise git, then run '%s hooks install' so commits fail when the files get out of date.", rootCmd.Name())
+	}
+	next("Add '%s' to CI.", hookCommand)
+	next("Put project-specific rules in %s and re-run '%s generate' after adding dependencies.", defaultRulesDir, rootCmd.Name())
+}
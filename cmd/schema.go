@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/config"
+	"github.com/cego/ai-instructions/internal/mdlint"
+	"github.com/cego/ai-instructions/internal/policy"
+	"github.com/cego/ai-instructions/internal/schema"
+)
+
+var flagSchemaType string
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the config file, the detect --json output or the policy file or the generate --manifest and --emit-model files",
+	Long: "Prints a JSON Schema for editor autocomplete and validation tooling, e.g. for yaml-language-server:\n\n" +
+		"  ai-instructions schema --type config > ai-instructions.schema.json\n" +
+		"  # yaml-language-server: $schema=./ai-instructions.schema.json",
+	GroupID: groupIntrospection,
+	Example: "  ai-instructions schema --type stack > stack.schema.json",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := buildSchema(flagSchemaType)
+		if err != nil {
+			return err
+		}
+		out, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+
+	schemaCmd.Flags().StringVar(
+		&flagSchemaType,
+		"type",
+		"config",
+		"Schema to print: config, stack, manifest, model or policy",
+	)
+}
+
+func buildSchema(kind string) (schema.Schema, error) {
+	switch kind {
+	case "config":
+		s := schema.For(reflect.TypeOf(config.Config{}), "yaml", "ai-instructions config ("+config.DefaultPath+")")
+		if p := s.Property("targets"); p != nil {
+			p["items"] = schema.Schema{"type": "string", "enum": targetNames()}
+		}
+		if p := s.Property("agentLevel"); p != nil {
+			p["enum"] = agentLevels
+		}
+		if p := s.Property("markdownlint.rules"); p != nil {
+			p["items"] = schema.Schema{"type": "string", "enum": mdlint.All}
+		}
+		return s, nil
+	case "stack":
+		return schema.For(reflect.TypeOf(detectOutput{}), "json", "ai-instructions detected stack (detect --json, render --stack)"), nil
+	case "manifest":
+		return schema.For(reflect.TypeOf(outputManifest{}), "json", "ai-instructions output manifest (generate --manifest)"), nil
+	case "policy":
+		return schema.For(reflect.TypeOf(policy.Policy{}), "yaml", "ai-instructions organization policy ("+policy.DefaultPath+")"), nil
+	case "model":
+		return schema.For(reflect.TypeOf(documentModel{}), "json", "ai-instructions document model (generate --emit-model)"), nil
+	}
+	return nil, fmt.Errorf("unknown schema type '%s' (available: config, stack, manifest, model, policy)", kind)
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// --paths and --changed-since limit the subproject AGENTS.md files that
+// --per-project renders to the subprojects a change touches (PR mode).
+var (
+	flagPaths        []string
+	flagChangedSince string
+)
+
+// addScopeFlags registers --paths and --changed-since on generate and validate.
+func addScopeFlags(cmd *cobra.Command, verb string) {
+	cmd.Flags().StringSliceVar(
+		&flagPaths,
+		"paths",
+		nil,
+		"With --per-project, only "+verb+" the AGENTS.md of subprojects these paths or globs touch, e.g. 'app/**,resources/js/**'",
+	)
+
+	cmd.Flags().StringVar(
+		&flagChangedSince,
+		"changed-since",
+		"",
+		"With --per-project, only "+verb+" the AGENTS.md of subprojects with files changed since this git ref (git diff --name-only <ref>...HEAD)",
+	)
+}
+
+func init() {
+	addScopeFlags(generateCmd, "generate")
+	addScopeFlags(validateCmd, "validate")
+}
+
+// pathScope is the set of paths (files, directories or globs, relative to
+// the project root) a scoped run considers; nil means everything.
+type pathScope []string
+
+// resolvePathScope returns the scope given by --paths and --changed-since.
+func resolvePathScope() (pathScope, error) {
+	if len(flagPaths) == 0 && flagChangedSince == "" {
+		return nil, nil
+	}
+	if !flagPerProject {
+		return nil, fmt.Errorf("--paths and --changed-since require --per-project")
+	}
+	scope := pathScope{}
+	for _, p := range flagPaths {
+		if p = strings.TrimSpace(p); p != "" {
+			scope = append(scope, p)
+		}
+	}
+	if flagChangedSince != "" {
+		changed, err := gitChangedFiles(flagChangedSince)
+		if err != nil {
+			return nil, err
+		}
+		scope = append(scope, changed...)
+	}
+	return scope, nil
+}
+
+// gitChangedFiles lists the files changed on HEAD since it forked from ref.
+func gitChangedFiles(ref string) ([]string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "diff", "--name-only", "--relative", ref+"...HEAD")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n")
+		return nil, fmt.Errorf("cannot list the files changed since '%s': %v %s", ref, err, msg)
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// Includes reports whether the scope touches the directory dir (slash
+// separated, relative to the root): a path or the literal part of a glob
+// lies in it, or it lies below one.
+func (s pathScope) Includes(dir string) bool {
+	if s == nil {
+		return true
+	}
+	for _, p := range s {
+		prefix := globPrefix(path.Clean(strings.TrimPrefix(p, "./")))
+		if prefix == "." || prefix == "" || within(prefix, dir) || within(dir, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// globPrefix returns the leading path segments of pattern without glob
+// characters, e.g. app/Http for app/Http/**/*.php.
+func globPrefix(pattern string) string {
+	var literal []string
+	for _, seg := range strings.Split(pattern, "/") {
+		if strings.ContainsAny(seg, "*?[{") {
+			break
+		}
+		literal = append(literal, seg)
+	}
+	return strings.Join(literal, "/")
+}
+
+// within reports whether p is dir or lies below it.
+func within(p, dir string) bool {
+	return p == dir || strings.HasPrefix(p, dir+"/")
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/secrets"
+)
+
+var flagRedact bool
+
+// guardSecrets refuses content containing secrets, since generated files are
+// committed and fed to AI services. With --redact the secrets are masked instead.
+func guardSecrets(path, content string) (string, error) {
+	findings := secrets.Scan(content)
+	if len(findings) == 0 {
+		return content, nil
+	}
+	if flagRedact {
+		return secrets.Redact(content), nil
+	}
+
+	lines := make([]string, len(findings))
+	for i, f := range findings {
+		lines[i] = "  " + f.String()
+	}
+	return "", fmt.Errorf("possible secrets in '%s' (remove them from the rules/included files or use --redact):\n%s",
+		path, strings.Join(lines, "\n"))
+}
+
+// guardFileSecrets applies guardSecrets to every rendered file.
+func guardFileSecrets(files []renderedFile) ([]renderedFile, error) {
+	for i, f := range files {
+		content, err := guardSecrets(f.Path, f.Content)
+		if err != nil {
+			return nil, err
+		}
+		files[i].Content = content
+	}
+	return files, nil
+}
+
+// addRedactFlag registers --redact on a command that renders instructions.
+func addRedactFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flagRedact,
+		"redact",
+		false,
+		"Mask secrets (API keys, tokens, private keys) found in the output instead of refusing to write it",
+	)
+}
+
+func init() {
+	addRedactFlag(generateCmd)
+	addRedactFlag(validateCmd)
+	addRedactFlag(exportCmd)
+}
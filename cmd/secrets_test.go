@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// token is assembled at run time, so that secret scanners do not flag this file.
+var token = "ghp_" + strings.Repeat("a1B2c3", 6)
+
+func TestGuardSecrets(t *testing.T) {
+	defer func(redact bool) { flagRedact = redact }(flagRedact)
+	content := "# Rules\n\nUse the token " + token + " for the API.\n"
+
+	tests := []struct {
+		name    string
+		redact  bool
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"refused by default", false, content, "", true},
+		{"redacted with --redact", true, content, "# Rules\n\nUse the token [REDACTED GitHub token] for the API.\n", false},
+		{"clean content passes", false, "# Rules\n\nNever commit tokens.\n", "# Rules\n\nNever commit tokens.\n", false},
+		{"placeholder passes", false, "api_key: \"your-api-key-goes-here\"\n", "api_key: \"your-api-key-goes-here\"\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flagRedact = tt.redact
+			got, err := guardSecrets("AGENTS.md", tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("guardSecrets() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), "AGENTS.md") || !strings.Contains(err.Error(), "line 3: GitHub token") {
+					t.Errorf("error does not name the file and finding: %v", err)
+				}
+				if strings.Contains(err.Error(), token) {
+					t.Errorf("error shows the secret: %v", err)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("guardSecrets() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuardFileSecretsStopsAtFirstSecret(t *testing.T) {
+	defer func(redact bool) { flagRedact = redact }(flagRedact)
+	flagRedact = false
+	files := []renderedFile{
+		{Path: "AGENTS.md", Content: "clean\n"},
+		{Path: ".github/copilot-instructions.md", Content: "token " + token + "\n"},
+	}
+	if _, err := guardFileSecrets(files); err == nil || !strings.Contains(err.Error(), ".github/copilot-instructions.md") {
+		t.Errorf("guardFileSecrets() error = %v, want the copilot file refused", err)
+	}
+}
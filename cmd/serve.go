@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/archive"
+	"github.com/cego/ai-instructions/internal/github"
+	"github.com/cego/ai-instructions/internal/warnings"
+)
+
+var (
+	flagServeAddr          string
+	flagServeAppID         int64
+	flagServePrivateKey    string
+	flagServeWebhookSecret string
+	flagServeAPIURL        string
+	flagServeCheckName     string
+)
+
+// Largest webhook payload GitHub sends is 25 MB.
+const maxWebhookBody = 25 << 20
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a GitHub App webhook that checks generated files on every push and pull request",
+	Long: "Receives push and pull_request webhooks for a GitHub App, downloads the commit, computes the\n" +
+		"expected instructions with the repository's own config and local rules, and reports the result\n" +
+		"(with a diff of outdated files) as a check run – no CLI install needed in each repository's CI.",
+	GroupID: groupIntegration,
+	Example: "  GITHUB_WEBHOOK_SECRET=... ai-instructions serve --app-id 12345 --private-key app.pem",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagServeAppID == 0 {
+			return fmt.Errorf("--app-id is required")
+		}
+		if flagServeWebhookSecret == "" {
+			flagServeWebhookSecret = os.Getenv("GITHUB_WEBHOOK_SECRET")
+		}
+		if flagServeWebhookSecret == "" {
+			return fmt.Errorf("--webhook-secret (or GITHUB_WEBHOOK_SECRET) is required")
+		}
+		pemData, err := os.ReadFile(flagServePrivateKey)
+		if err != nil {
+			return fmt.Errorf("read private key: %w", err)
+		}
+		key, err := github.ParsePrivateKey(pemData)
+		if err != nil {
+			return err
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		s := &webhookServer{
+			app: &github.App{ID: flagServeAppID, Key: key, BaseURL: flagServeAPIURL},
+			cwd: cwd,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/webhook", s.handleWebhook)
+		mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, "ok")
+		})
+
+		server := &http.Server{Addr: flagServeAddr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		fmt.Printf("Listening for GitHub webhooks on http://%s/webhook (Ctrl+C to stop)\n", flagServeAddr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(
+		&flagServeAddr,
+		"addr",
+		":8080",
+		"Address to listen on",
+	)
+
+	serveCmd.Flags().Int64Var(
+		&flagServeAppID,
+		"app-id",
+		0,
+		"GitHub App ID",
+	)
+
+	serveCmd.Flags().StringVar(
+		&flagServePrivateKey,
+		"private-key",
+		"private-key.pem",
+		"Path to the GitHub App private key (PEM)",
+	)
+
+	serveCmd.Flags().StringVar(
+		&flagServeWebhookSecret,
+		"webhook-secret",
+		"",
+		"Secret used to verify webhook deliveries (defaults to $GITHUB_WEBHOOK_SECRET)",
+	)
+
+	serveCmd.Flags().StringVar(
+		&flagServeAPIURL,
+		"api-url",
+		github.DefaultBaseURL,
+		"GitHub REST API base URL (for GitHub Enterprise Server)",
+	)
+
+	serveCmd.Flags().StringVar(
+		&flagServeCheckName,
+		"check-name",
+		"ai-instructions",
+		"Name of the check run posted on each commit",
+	)
+}
+
+// webhookServer handles deliveries one at a time: validation changes the
+// working directory and the loaded config/rules, which are process-wide.
+type webhookServer struct {
+	app *github.App
+	cwd string
+	mu  sync.Mutex
+}
+
+// webhookEvent is the subset of push and pull_request payloads we need.
+type webhookEvent struct {
+	Action     string `json:"action"`
+	After      string `json:"after"`
+	Deleted    bool   `json:"deleted"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Installation struct {
+		ID int64 `json:"id"`
+	} `json:"installation"`
+	PullRequest struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+}
+
+// commitToCheck returns the commit a delivery should be checked at, or "" to ignore it.
+func (e webhookEvent) commitToCheck(kind string) string {
+	switch kind {
+	case "push":
+		if e.Deleted || strings.Trim(e.After, "0") == "" {
+			return ""
+		}
+		return e.After
+	case "pull_request":
+		switch e.Action {
+		case "opened", "synchronize", "reopened":
+			return e.PullRequest.Head.SHA
+		}
+	}
+	return ""
+}
+
+func (s *webhookServer) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !github.VerifySignature(flagServeWebhookSecret, body, r.Header.Get("X-Hub-Signature-256")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	kind := r.Header.Get("X-GitHub-Event")
+	var event webhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	sha := event.commitToCheck(kind)
+	if sha == "" || event.Repository.FullName == "" || event.Installation.ID == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// Acknowledge right away; GitHub times deliveries out after 10 seconds
+	w.WriteHeader(http.StatusAccepted)
+	go func() {
+		if err := s.check(event.Repository.FullName, sha, event.Installation.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "check %s@%s failed: %v\n", event.Repository.FullName, sha, err)
+		}
+	}()
+}
+
+// check validates repo at sha and posts the result as a check run.
+func (s *webhookServer) check(repo, sha string, installationID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	client, err := s.app.Installation(ctx, installationID)
+	if err != nil {
+		return err
+	}
+	data, err := client.Tarball(ctx, repo, sha)
+	if err != nil {
+		return err
+	}
+
+	report, err := s.validateArchive(data)
+	if err != nil {
+		return err
+	}
+	report.Path = repo
+
+	if err := client.CreateCheckRun(ctx, repo, buildCheckRun(report, sha)); err != nil {
+		return err
+	}
+	fmt.Printf("Checked %s@%s: %s\n", repo, sha, checkConclusion(report))
+	return nil
+}
+
+// validateArchive extracts a repository tarball and validates it like batch does.
+func (s *webhookServer) validateArchive(data []byte) (repoReport, error) {
+	tmp, err := os.MkdirTemp("", "ai-instructions-check-")
+	if err != nil {
+		return repoReport{}, err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := archive.Extract(data, tmp); err != nil {
+		return repoReport{}, fmt.Errorf("extract tarball: %w", err)
+	}
+	// GitHub wraps the tree in a single "<owner>-<repo>-<sha>" directory
+	root := tmp
+	if entries, err := os.ReadDir(tmp); err == nil && len(entries) == 1 && entries[0].IsDir() {
+		root = filepath.Join(tmp, entries[0].Name())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer func() {
+		_ = os.Chdir(s.cwd)
+		_ = reloadInputs()
+	}()
+
+	warnings.Reset()
+	report := validateRepo(root)
+	for _, w := range warnings.List() {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%s: %s", w.Source, w.Message))
+	}
+	warnings.Reset()
+	return report, nil
+}
+
+func checkConclusion(r repoReport) string {
+	if r.UpToDate() {
+		return "success"
+	}
+	return "failure"
+}
+
+// buildCheckRun turns a validation report into a completed check run.
+func buildCheckRun(r repoReport, sha string) github.CheckRun {
+	run := github.CheckRun{
+		Name:       flagServeCheckName,
+		HeadSHA:    sha,
+		Status:     "completed",
+		Conclusion: checkConclusion(r),
+	}
+
+	var summary strings.Builder
+	switch {
+	case r.Err != "":
+		run.Output.Title = "Validation failed"
+		fmt.Fprintf(&summary, "Could not compute the expected instructions: %s\n", r.Err)
+	case r.UpToDate():
+		run.Output.Title = "Instructions are up to date"
+	default:
+		stale := 0
+		for _, f := range r.Files {
+			if !f.OK() {
+				stale++
+			}
+		}
+		run.Output.Title = fmt.Sprintf("%d generated file(s) out of date", stale)
+		summary.WriteString("Run `ai-instructions generate` and commit the result.\n")
+	}
+
+	if r.Stack != "" {
+		fmt.Fprintf(&summary, "\n**Stack:** %s\n", r.Stack)
+	}
+	if len(r.Files) > 0 {
+		summary.WriteString("\n| File | Status |\n| --- | --- |\n")
+		for _, f := range r.Files {
+			fmt.Fprintf(&summary, "| `%s` | %s |\n", f.Path, f.Status)
+		}
+	}
+	if len(r.Warnings) > 0 {
+		summary.WriteString("\n**Warnings:**\n\n")
+		for _, w := range r.Warnings {
+			fmt.Fprintf(&summary, "- %s\n", w)
+		}
+	}
+	run.Output.Summary = summary.String()
+
+	var text strings.Builder
+	for _, f := range r.Files {
+		if f.Diff == "" {
+			continue
+		}
+		block := fmt.Sprintf("```diff\n%s\n```\n\n", strings.TrimRight(f.Diff, "\n"))
+		if text.Len()+len(block) > github.MaxCheckRunText-100 {
+			text.WriteString("_Diff truncated._\n")
+			break
+		}
+		text.WriteString(block)
+	}
+	run.Output.Text = text.String()
+	return run
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+)
+
+// flagSet overrides individual detected versions, e.g. --set php=8.3.
+var flagSet []string
+
+// addSetFlag registers --set on a command that detects the stack.
+func addSetFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(
+		&flagSet,
+		"set",
+		nil,
+		"Override a detected version while keeping the rest of detection, e.g. --set php=8.3 --set laravel=11 (empty value drops it)",
+	)
+}
+
+func init() {
+	addSetFlag(generateCmd)
+	addSetFlag(validateCmd)
+	addSetFlag(syncCmd)
+}
+
+// applyStackOverrides applies --set to the detected stack.
+func applyStackOverrides(stack *detect.DetectedStack) error {
+	for _, kv := range flagSet {
+		name, version, ok := strings.Cut(kv, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid --set '%s' (expected name=version)", kv)
+		}
+		if err := stack.Set(strings.TrimSpace(name), strings.TrimSpace(version)); err != nil {
+			return fmt.Errorf("invalid --set '%s': %w", kv, err)
+		}
+	}
+	return nil
+}
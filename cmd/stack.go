@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+)
+
+// stackEntry describes how a detected technology is listed. Entries are
+// ordered by Priority, so a new technology takes an unused priority and never
+// moves the lines of existing ones (which would break validate downstream).
+type stackEntry struct {
+	Name     string // detect technology name
+	Label    string // label in the detect output
+	Priority int
+	// Section is the line format in the generated stack section ("" to leave
+	// the technology out, e.g. package managers which have their own section).
+	Section string
+	// NoRules marks technologies without a rules directory of their own.
+	NoRules bool
+	// RuleDir is the rules directory when it is not Name, e.g. python/django
+	// for frameworks grouped under their language.
+	RuleDir string
+}
+
+// ruleDir returns the rules directory of a technology.
+func ruleDir(name string) string {
+	if e := stackEntryFor(name); e.RuleDir != "" {
+		return e.RuleDir
+	}
+	return name
+}
+
+var stackEntries = []stackEntry{
+	{Name: detect.PHP, Label: "PHP", Priority: 100, Section: "PHP: %s"},
+	{Name: detect.Laravel, Label: "Laravel", Priority: 110, Section: "Laravel: %s"},
+	{Name: detect.Nuxt, Label: "Nuxt", Priority: 200, Section: "Nuxt: %s"},
+	{Name: detect.Vue, Label: "Vue", Priority: 210, Section: "Vue: %s"},
+	{Name: detect.NuxtUI, Label: "Nuxt UI", Priority: 220, Section: "Nuxt UI: %s"},
+	{Name: detect.React, Label: "React", Priority: 230, Section: "React: %s"},
+	{Name: detect.Svelte, Label: "Svelte", Priority: 240, Section: "Svelte: %s"},
+	{Name: detect.Angular, Label: "Angular", Priority: 250, Section: "Angular: %s"},
+	{Name: detect.Go, Label: "Go", Priority: 300, Section: "Go: %s"},
+	{Name: detect.Python, Label: "Python", Priority: 320, Section: "Python: %s"},
+	{Name: detect.Django, Label: "Django", Priority: 330, Section: "Django: %s", RuleDir: "python/django"},
+	{Name: detect.FastAPI, Label: "FastAPI", Priority: 340, Section: "FastAPI: %s", RuleDir: "python/fastapi"},
+	{Name: detect.Flask, Label: "Flask", Priority: 350, Section: "Flask: %s", RuleDir: "python/flask"},
+	{Name: detect.Ruby, Label: "Ruby", Priority: 360, Section: "Ruby: %s"},
+	{Name: detect.Rails, Label: "Rails", Priority: 370, Section: "Rails: %s"},
+	{Name: detect.Node, Label: "Node.js", Priority: 380, Section: "Node.js: %s", NoRules: true},
+	{Name: detect.JavaScript, Label: "JavaScript", Priority: 390, NoRules: true},
+	{Name: detect.TypeScript, Label: "TypeScript", Priority: 400, Section: "TypeScript: %s"},
+	{Name: detect.Pinia, Label: "Pinia", Priority: 500, Section: "Pinia: %s"},
+	{Name: detect.Vuex, Label: "Vuex", Priority: 510, Section: "Vuex: %s"},
+	{Name: detect.VueRouter, Label: "Vue Router", Priority: 520, Section: "Vue Router: %s"},
+	{Name: detect.Octane, Label: "Laravel Octane", Priority: 600, Section: "Laravel Octane: %s"},
+	{Name: detect.Horizon, Label: "Laravel Horizon", Priority: 610, Section: "Laravel Horizon: %s"},
+	{Name: detect.Scheduler, Label: "Scheduled tasks", Priority: 620, Section: "Scheduled tasks: %s"},
+	{Name: detect.Inertia, Label: "Inertia", Priority: 630, Section: "Inertia: %s"},
+	{Name: detect.Bazel, Label: "Bazel", Priority: 700, Section: "Build system: Bazel (%s)"},
+	{Name: detect.Nix, Label: "Nix", Priority: 710, Section: "Build environment: Nix (%s)"},
+	{Name: detect.PackageManager, Label: "Package manager", Priority: 800, NoRules: true},
+	{Name: detect.Composer, Label: "Composer", Priority: 810, NoRules: true},
+}
+
+// Technologies missing from stackEntries are listed last, by name.
+const unlistedStackPriority = 1 << 20
+
+// stackEntryFor returns the entry of a technology; unlisted technologies are
+// labeled by their condition name (detect.FieldName).
+func stackEntryFor(name string) stackEntry {
+	for _, e := range stackEntries {
+		if e.Name == name {
+			return e
+		}
+	}
+	label := detect.FieldName(name)
+	return stackEntry{Name: name, Label: label, Priority: unlistedStackPriority, Section: label + ": %s"}
+}
+
+// orderedTechnologies returns the detected technologies sorted by entry
+// priority, then name.
+func orderedTechnologies(stack *detect.DetectedStack) []detect.Technology {
+	if stack == nil {
+		return nil
+	}
+	techs := append([]detect.Technology(nil), stack.Technologies...)
+	sort.SliceStable(techs, func(i, j int) bool {
+		pi, pj := stackEntryFor(techs[i].Name).Priority, stackEntryFor(techs[j].Name).Priority
+		if pi != pj {
+			return pi < pj
+		}
+		return techs[i].Name < techs[j].Name
+	})
+	return techs
+}
+
+// ruleTechnologies returns the detected technologies that have rules
+// directories (rules/<name>, or their RuleDir), in stack order.
+func ruleTechnologies(stack *detect.DetectedStack) []detect.Technology {
+	var out []detect.Technology
+	for _, t := range orderedTechnologies(stack) {
+		if !stackEntryFor(t.Name).NoRules {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// normalizeStackValue collapses whitespace so equivalent inputs render the same.
+func normalizeStackValue(v string) string {
+	return strings.Join(strings.Fields(v), " ")
+}
+
+// stackLines lists the detected technologies in stable order, as "<Label>: <version>"
+// (section false) or in the generated stack section format (section true).
+func stackLines(stack *detect.DetectedStack, section bool) []string {
+	var lines []string
+	for _, t := range orderedTechnologies(stack) {
+		v := normalizeStackValue(t.Version)
+		if v == "" {
+			continue
+		}
+		if t.Confidence == detect.LowConfidence {
+			v += " (inferred from " + t.Source + ")"
+		}
+		e := stackEntryFor(t.Name)
+		// A marker file is no version: JavaScript (package.json), not JavaScript: package.json
+		label, marker := strings.CutSuffix(e.Section, ": %s")
+		switch {
+		case !section && t.Marker():
+			lines = append(lines, fmt.Sprintf("%s (%s)", e.Label, v))
+		case !section:
+			lines = append(lines, fmt.Sprintf("%s: %s", e.Label, v))
+		case e.Section != "" && t.Marker() && marker:
+			lines = append(lines, fmt.Sprintf("%s (%s)", label, v))
+		case e.Section != "":
+			lines = append(lines, fmt.Sprintf(e.Section, v))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/format"
+	"github.com/cego/ai-instructions/rules"
+)
+
+// flagStamp adds a provenance comment (CLI version and rules hash) to every
+// generated file. Off by default so output stays byte-for-byte reproducible.
+var flagStamp bool
+
+// addStampFlag registers --stamp on a command that writes instruction files.
+func addStampFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flagStamp,
+		"stamp",
+		false,
+		"Add a comment with the CLI version and rules hash to generated files (deterministic, no timestamps)",
+	)
+}
+
+func init() {
+	addStampFlag(generateCmd)
+	addStampFlag(validateCmd)
+	addStampFlag(renderCmd)
+}
+
+// stampText is the provenance note; it deliberately carries no time so
+// that regenerating with the same CLI and rules yields identical files.
+func stampText() (string, error) {
+	hash, err := rules.Hash()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Generated by ai-instructions %s (rules %s). Do not edit by hand.", version, hash), nil
+}
+
+// stampFiles prepends the provenance comment to each file when --stamp is
+// set, keeping a leading front matter block first.
+func stampFiles(files []renderedFile) ([]renderedFile, error) {
+	if !flagStamp {
+		return files, nil
+	}
+	text, err := stampText()
+	if err != nil {
+		return nil, err
+	}
+	converted, err := format.Lookup(flagFormat)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]renderedFile, len(files))
+	for i, f := range files {
+		switch {
+		case strings.HasSuffix(f.Path, ".json"):
+			// JSON has no comments
+		case converted.Ext() != ".md" && strings.HasSuffix(f.Path, converted.Ext()):
+			f.Content = insertStamp(f.Content, converted.Comment(text))
+		default:
+			f.Content = insertStamp(f.Content, "<!-- "+text+" -->")
+		}
+		out[i] = f
+	}
+	return out, nil
+}
+
+func insertStamp(content, line string) string {
+	if strings.HasPrefix(content, "---\n") {
+		if end := strings.Index(content[4:], "\n---\n"); end >= 0 {
+			split := 4 + end + len("\n---\n")
+			return content[:split] + line + "\n" + content[split:]
+		}
+	}
+	return line + "\n\n" + content
+}
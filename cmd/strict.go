@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/rules"
+)
+
+var flagStrict bool
+
+// missingExpectedRules returns the rule IDs the run expected but could not
+// use: the base general rule of every detected technology with rules of its
+// own (or of every --rule
+// in manual mode) and selected rules that failed to load.
+func missingExpectedRules(stack *detect.DetectedStack, ids []string) []string {
+	var expected []string
+	if stack != nil {
+		for _, t := range ruleTechnologies(stack) {
+			if dir := ruleDir(t.Name); hasRuleChain(dir) {
+				expected = append(expected, dir+"/general")
+			}
+		}
+	} else {
+		for _, r := range flagRules {
+			r = filepath.ToSlash(strings.TrimSpace(r))
+			if r != "" && !ruleExists(r) {
+				expected = append(expected, r+"/general")
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	var missing []string
+	for _, id := range expected {
+		if !ruleExists(id) && !seen[id] {
+			seen[id] = true
+			missing = append(missing, id)
+		}
+	}
+	for _, id := range ids {
+		if _, err := rules.Get(id); err != nil && !seen[id] {
+			seen[id] = true
+			missing = append(missing, id)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// hasRuleChain reports whether the rules directory of a technology has rules
+// of its own (rule files or version directories). Directories that only hold
+// cross-cutting rules (php/plain) or do not exist (vue) expect no base rule.
+func hasRuleChain(dir string) bool {
+	children, err := rules.Children(dir)
+	if err != nil {
+		return false
+	}
+	for _, c := range children {
+		if ruleExists(dir + "/" + c) {
+			return true
+		}
+	}
+	return hasVersionRules(dir)
+}
+
+// checkStrict fails when --strict is set and expected rules are missing or
+// selected rules require project settings (e.g. TypeScript strict mode) that
+// are not enabled.
+func checkStrict(stack *detect.DetectedStack, ids []string) error {
+	if !flagStrict {
+		return nil
+	}
+	missing := missingExpectedRules(stack, ids)
+	for _, id := range missing {
+		fmt.Printf("Missing rule: 'rules/%s.md'\n", id)
+	}
+	unmet := unmetRequirements(stack, ids)
+	for _, u := range unmet {
+		fmt.Printf("Unmet requirement: %s\n", u)
+	}
+
+	switch {
+	case len(missing) > 0 && len(unmet) > 0:
+		return fmt.Errorf("strict mode: %d expected rule(s) missing, %d requirement(s) unmet", len(missing), len(unmet))
+	case len(missing) > 0:
+		return fmt.Errorf("strict mode: %d expected rule(s) missing", len(missing))
+	case len(unmet) > 0:
+		return fmt.Errorf("strict mode: %d requirement(s) unmet", len(unmet))
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/markdown"
+	"github.com/cego/ai-instructions/rules"
+)
+
+var flagSummarize bool
+
+// Directory of the per-framework detail files. Copilot only loads
+// *.instructions.md from it, so the plain .md files are read on demand.
+const detailsDir = ".github/instructions"
+
+// Intermediate link prefix for detail files until the output location is known.
+const detailRefPrefix = "ai-instructions-detail:"
+
+var detailRefPattern = regexp.MustCompile(regexp.QuoteMeta(detailRefPrefix) + `([^)\s]+)`)
+
+// Rules of these directories are project-specific and stay in the top-level
+// file instead of getting a detail file.
+var inlineRuleDirs = map[string]bool{"local": true, "branch": true}
+
+// Headings listed per framework in the summary.
+const maxSummaryTopics = 8
+
+// frameworkRules are the general rules of one rule directory (e.g. laravel).
+type frameworkRules struct {
+	Name string
+	IDs  []string
+}
+
+// groupFrameworkRules groups ids by rule directory in order of first
+// appearance; rules of inlineRuleDirs are returned separately.
+func groupFrameworkRules(ids []string) ([]frameworkRules, []string) {
+	var (
+		groups []frameworkRules
+		inline []string
+	)
+	index := map[string]int{}
+	for _, id := range ids {
+		name, _, _ := strings.Cut(id, "/")
+		if inlineRuleDirs[name] {
+			inline = append(inline, id)
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, frameworkRules{Name: name})
+		}
+		groups[i].IDs = append(groups[i].IDs, id)
+	}
+	return groups, inline
+}
+
+// summarizeRules returns the top-level content for --summarize, with one
+// summary paragraph per framework linking its detail file, and the detail
+// files with the full merged rules of every framework.
+func summarizeRules(ids []string, assetsDir string) (string, []renderedFile, error) {
+	groups, inline := groupFrameworkRules(ids)
+
+	var (
+		b       strings.Builder
+		details []renderedFile
+	)
+	b.WriteString("# Frameworks\n\n")
+	b.WriteString("This file summarizes the instructions per framework. Before changing code that uses a framework, read its linked file and follow it.")
+	for _, g := range groups {
+		content, err := mergeRules(g.IDs, activeTags())
+		if err != nil {
+			return "", nil, err
+		}
+		detailPath := path.Join(detailsDir, g.Name+".md")
+		details = append(details, renderedFile{
+			Label:   "INSTRUCTIONS",
+			Path:    detailPath,
+			Content: wrapBoilerplate(resolveAssetLinks(content, detailPath, assetsDir)),
+		})
+
+		label := frameworkLabel(g.Name)
+		fmt.Fprintf(&b, "\n\n## %s\n\n", label)
+		if summary := ruleSummary(g.IDs[0]); summary != "" {
+			b.WriteString(summary + " ")
+		}
+		if topics := summaryTopics(content); len(topics) > 0 {
+			b.WriteString("Covers: " + strings.Join(topics, ", ") + ". ")
+		}
+		fmt.Fprintf(&b, "Details: [%s instructions](%s%s).", label, detailRefPrefix, detailPath)
+	}
+
+	if len(inline) > 0 {
+		content, err := mergeRules(inline, activeTags())
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString("\n\n---\n\n" + content)
+	}
+	return b.String(), details, nil
+}
+
+// frameworkLabel returns the stack label of a rule directory (nuxt_ui: Nuxt UI).
+func frameworkLabel(name string) string {
+	return stackEntryFor(name).Label
+}
+
+// ruleSummary returns the description of a rule, or the first paragraph of
+// prose in its body.
+func ruleSummary(id string) string {
+	r, err := rules.Load(id)
+	if err != nil {
+		return ""
+	}
+	if d := strings.TrimSpace(r.Meta.Description); d != "" {
+		return d
+	}
+	for _, para := range strings.Split(r.Body, "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" || markdown.HeadingLevel(para) > 0 || strings.HasPrefix(para, "```") ||
+			strings.HasPrefix(para, "- ") || strings.HasPrefix(para, "* ") || strings.HasPrefix(para, "<!--") {
+			continue
+		}
+		return strings.Join(strings.Fields(para), " ")
+	}
+	return ""
+}
+
+// summaryTopics returns the second-level headings of merged rules.
+func summaryTopics(content string) []string {
+	var topics []string
+	seen := map[string]bool{}
+	for _, s := range markdown.Sections(content) {
+		if s.Level != 2 || seen[s.Heading] {
+			continue
+		}
+		seen[s.Heading] = true
+		topics = append(topics, s.Heading)
+	}
+	if len(topics) > maxSummaryTopics {
+		topics = append(topics[:maxSummaryTopics], fmt.Sprintf("and %d more", len(topics)-maxSummaryTopics))
+	}
+	return topics
+}
+
+// resolveDetailLinks points detail file references at their path relative to
+// the file at outPath.
+func resolveDetailLinks(content, outPath string) string {
+	return detailRefPattern.ReplaceAllStringFunc(content, func(m string) string {
+		target := strings.TrimPrefix(m, detailRefPrefix)
+		rel, err := filepath.Rel(filepath.Dir(outPath), filepath.FromSlash(target))
+		if err != nil {
+			return target
+		}
+		return filepath.ToSlash(rel)
+	})
+}
+
+// addSummarizeFlag registers --summarize on a command that renders instructions.
+func addSummarizeFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flagSummarize,
+		"summarize",
+		false,
+		"For targets that follow links (copilot, agents), write one summary paragraph per framework and the full rules to "+detailsDir+"/<framework>.md",
+	)
+}
+
+func init() {
+	addSummarizeFlag(generateCmd)
+	addSummarizeFlag(validateCmd)
+}
+
+// buildSummaryContent returns the --summarize content (after the detected
+// sections) and detail files when a selected target follows links.
+func buildSummaryContent(selected []target, ids []string, detected, assetsDir string) (string, []renderedFile, error) {
+	if !flagSummarize || !hasLinkedTarget(selected) {
+		return "", nil, nil
+	}
+	summary, details, err := summarizeRules(ids, assetsDir)
+	if err != nil {
+		return "", nil, err
+	}
+	if detected != "" {
+		summary = detected + "\n\n---\n\n" + summary
+	}
+	return summary, details, nil
+}
+
+// hasLinkedTarget reports whether a selected target follows links.
+func hasLinkedTarget(selected []target) bool {
+	for _, t := range selected {
+		if t.LinkedFiles {
+			return true
+		}
+	}
+	return false
+}
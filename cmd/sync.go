@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/diff"
+)
+
+var flagSyncApply bool
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Detect manual edits in generated files and propagate them into the local rules",
+	Long: "Compares the generated files (AGENTS.md, copilot-instructions.md, ...) with what generate would\n" +
+		"produce. Lines added by hand are reported per file; with --apply they are appended to the\n" +
+		"local rules (" + defaultRulesDir + "/" + localGeneralRule + ".md) so every output picks them up on the next generate.",
+	GroupID: groupGeneration,
+	Example: "  # Show manual edits, then move them into the local rules\n" +
+		"  ai-instructions sync\n" +
+		"  ai-instructions sync --apply",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stack, err := detect.DetectStack(".")
+		if err != nil {
+			return fmt.Errorf("stack detection failed: %w", err)
+		}
+		// The same stack as generate and validate, so their outputs are compared
+		if err := applyStackOverrides(stack); err != nil {
+			return err
+		}
+
+		files, err := buildExpectedFiles(stack)
+		if err != nil {
+			return err
+		}
+
+		// Collect the hand-written delta of every file (deduplicated across files)
+		var blocks []string
+		seen := map[string]bool{}
+		edited := 0
+		for _, f := range files {
+			data, err := os.ReadFile(f.Path)
+			if err != nil {
+				continue
+			}
+			added := diff.Inserted(f.Content, string(data))
+			if len(added) == 0 {
+				continue
+			}
+
+			edited++
+			fmt.Printf("Manual edits in '%s':\n", f.Path)
+			for _, block := range added {
+				key := strings.TrimSpace(block)
+				fmt.Println(indentBlock(key, "  + "))
+				if !seen[key] {
+					seen[key] = true
+					blocks = append(blocks, key)
+				}
+			}
+		}
+
+		if edited == 0 {
+			fmt.Println("No manual edits found: generated files are in sync.")
+			return nil
+		}
+		if edited < len(files) {
+			fmt.Println("Generated files have diverged: not every output carries the manual edits.")
+		}
+
+		if !flagSyncApply {
+			fmt.Println("Run 'ai-instructions sync --apply' to move these edits into the local rules.")
+			return fmt.Errorf("sync check failed: %d file(s) edited manually", edited)
+		}
+
+		path := filepath.Join(flagRulesDir, filepath.FromSlash(localGeneralRule)+".md")
+		if err := appendLocalRule(path, blocks); err != nil {
+			return err
+		}
+		fmt.Printf("Manual edits appended to %s. Run 'ai-instructions generate' to update all outputs.\n", path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().BoolVar(
+		&flagSyncApply,
+		"apply",
+		false,
+		"Append the manual edits to the local rules instead of only reporting them",
+	)
+}
+
+// appendLocalRule appends blocks to the local rule file, creating it if needed.
+func appendLocalRule(path string, blocks []string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	content := strings.TrimRight(string(existing), "\n")
+	if content == "" {
+		content = "# Project-specific instructions"
+	}
+	for _, block := range blocks {
+		content += "\n\n" + block
+	}
+	return writeFileWithDirs(path, []byte(content+"\n"))
+}
+
+func indentBlock(block, prefix string) string {
+	lines := strings.Split(block, "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/markdown"
+	"github.com/cego/ai-instructions/rules"
+)
+
+// flagTags limits generated rules to the given topics (empty: no filtering).
+var flagTags []string
+
+func init() {
+	generateCmd.Flags().StringSliceVar(
+		&flagTags,
+		"tags",
+		nil,
+		"Only include rules and rule sections tagged with one of these topics (e.g. testing,security)",
+	)
+}
+
+// activeTags returns the normalized --tags.
+func activeTags() []string {
+	return normalizeTags(flagTags)
+}
+
+func normalizeTags(tags []string) []string {
+	var out []string
+	for _, t := range tags {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// taggedBody returns the part of a rule matching tags: the whole body when
+// the rule is tagged, its tagged sections otherwise ("" when nothing
+// matches). Without tags it returns the body unchanged.
+func taggedBody(r *rules.Rule, tags []string) string {
+	if len(tags) == 0 || matchesTags(r.Meta.Tags, tags) {
+		return r.Body
+	}
+	var headings []string
+	for heading, sectionTags := range r.Meta.SectionTags {
+		if matchesTags(sectionTags, tags) {
+			headings = append(headings, heading)
+		}
+	}
+	return markdown.ExtractSections(r.Body, headings)
+}
+
+// filterTagged drops the rules with no content for tags.
+func filterTagged(ids, tags []string) []string {
+	if len(tags) == 0 {
+		return ids
+	}
+	var out []string
+	for _, id := range ids {
+		if r, err := rules.Load(id); err == nil && taggedBody(r, tags) != "" {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func matchesTags(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if strings.EqualFold(strings.TrimSpace(h), w) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,393 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/cego/ai-instructions/internal/detect"
+)
+
+var (
+	flagTargets   []string
+	flagNoAgents  bool
+	flagNoCopilot bool
+)
+
+// target is an output file format the merged instructions can be rendered to.
+type target struct {
+	Name        string
+	Label       string
+	Path        string // default output path
+	Description string
+	// Render transforms the merged markdown into the target's file format
+	// (nil keeps the markdown as is).
+	Render func(content string) string
+	// Companions returns additional files that belong to the target (e.g. a
+	// config file that loads the rendered output), given the output path and
+	// rendered content.
+	Companions func(outPath, content string) []renderedFile
+	// Category selects rules of another category (review, commit-message,
+	// pull-request) instead of the authoring instructions; the target is
+	// skipped when the category has no rules.
+	Category string
+	// Files renders a target made of several files (e.g. one per prompt)
+	// from the detected stack; such targets ignore the merged content.
+	// The selected targets are passed for files that refer to other outputs.
+	Files func(stack *detect.DetectedStack, selected []target) ([]renderedFile, error)
+	// LinkedFiles marks targets whose readers follow relative links; with
+	// --summarize they get the framework summaries instead of the full rules.
+	LinkedFiles bool
+}
+
+// targets is the registry of supported outputs, in output order.
+var targets = []target{
+	{
+		Name:        "copilot",
+		Label:       "COPILOT",
+		Path:        ".github/copilot-instructions.md",
+		Description: "GitHub Copilot repository instructions",
+		LinkedFiles: true,
+	},
+	{
+		Name:        "copilot-review",
+		Label:       "COPILOT REVIEW",
+		Path:        ".github/copilot-review-instructions.md",
+		Description: "GitHub Copilot code review instructions (rules with audience: reviewer)",
+		Category:    categoryReview,
+	},
+	{
+		Name:        "copilot-commit",
+		Label:       "COPILOT COMMIT MESSAGE",
+		Path:        ".github/copilot-commit-message-instructions.md",
+		Description: "GitHub Copilot commit message instructions (git/commit-message rules)",
+		Category:    categoryCommitMessage,
+	},
+	{
+		Name:        "copilot-pr",
+		Label:       "COPILOT PULL REQUEST",
+		Path:        ".github/copilot-pull-request-description-instructions.md",
+		Description: "GitHub Copilot pull request description instructions (git/pull-request rules)",
+		Category:    categoryPullRequest,
+	},
+	{
+		Name:        "copilot-prompts",
+		Label:       "COPILOT PROMPT",
+		Path:        ".github/prompts",
+		Description: "GitHub Copilot prompt files (.prompt.md) from rules/prompts",
+		Files:       buildPromptFiles,
+	},
+	{
+		Name:        "copilot-chatmodes",
+		Label:       "COPILOT CHAT MODE",
+		Path:        ".github/chatmodes",
+		Description: "GitHub Copilot custom chat modes (.chatmode.md) from rules/chatmodes",
+		Files:       buildChatModeFiles,
+	},
+	{
+		Name:        "agents",
+		Label:       "AGENTS",
+		Path:        "AGENTS.md",
+		Description: "AGENTS.md for coding agents",
+		LinkedFiles: true,
+	},
+	{
+		Name:        "jetbrains",
+		Label:       "JETBRAINS",
+		Path:        ".aiassistant/rules/ai-instructions.md",
+		Description: "JetBrains AI Assistant project rules",
+	},
+	{
+		Name:        "zed",
+		Label:       "ZED",
+		Path:        ".rules",
+		Description: "Zed editor project rules",
+	},
+	{
+		Name:        "aider",
+		Label:       "AIDER",
+		Path:        "CONVENTIONS.md",
+		Description: "Aider conventions file loaded via .aider.conf.yml",
+		Companions:  aiderCompanions,
+	},
+	{
+		Name:        "continue",
+		Label:       "CONTINUE",
+		Path:        ".continue/rules/ai-instructions.md",
+		Description: "Continue.dev rules block",
+		Render:      renderContinueRule,
+	},
+	{
+		Name:        "gemini",
+		Label:       "GEMINI",
+		Path:        "GEMINI.md",
+		Description: "Gemini Code Assist context file plus .gemini/styleguide.md for code review",
+		Companions:  geminiCompanions,
+	},
+	{
+		Name:        "claude-agents",
+		Label:       "CLAUDE AGENT",
+		Path:        ".claude/agents",
+		Description: "Claude Code subagents from the rules/chatmodes personas",
+		Files:       buildClaudeAgentFiles,
+	},
+	{
+		Name:        "devcontainer",
+		Label:       "DEVCONTAINER",
+		Path:        devcontainerPath,
+		Description: "Dev container (Codespaces) VS Code settings that load the Copilot instruction files",
+		Files:       buildDevcontainerFiles,
+	},
+	{
+		Name:        "claude-settings",
+		Label:       "CLAUDE SETTINGS",
+		Path:        claudeSettingsPath,
+		Description: "Claude Code permission allow and deny lists from the config permissions and the policy",
+		Files:       buildClaudeSettingsFiles,
+	},
+	{
+		Name:        "copilot-firewall",
+		Label:       "COPILOT FIREWALL",
+		Path:        copilotFirewallPath,
+		Description: "Copilot coding agent firewall allowlist (hosts from the config permissions) to copy into the repository settings",
+		Files:       buildCopilotFirewallFiles,
+	},
+}
+
+// Targets generated when no --target flag is given. The other Copilot
+// customization files (copilot-review, copilot-prompts, ...) are opt-in, so
+// upgrading does not make validate expect new files in existing repositories.
+var defaultTargets = []string{"copilot", "agents"}
+
+func lookupTarget(name string) (target, bool) {
+	for _, t := range targets {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return target{}, false
+}
+
+func targetNames() []string {
+	var names []string
+	for _, t := range targets {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// selectedTargets resolves --target values (falling back to the config, then
+// defaultTargets) in registry order, minus targets disabled by flags or config.
+func selectedTargets(names []string) ([]target, error) {
+	if len(names) == 0 {
+		names = cfg.Targets
+	}
+	if len(names) == 0 {
+		names = defaultTargets
+	}
+
+	wanted := map[string]bool{}
+	for _, n := range names {
+		n = strings.ToLower(strings.TrimSpace(n))
+		if n == "" {
+			continue
+		}
+		if _, ok := lookupTarget(n); !ok {
+			return nil, fmt.Errorf("unknown target '%s' (available: %s)", n, strings.Join(targetNames(), ", "))
+		}
+		wanted[n] = true
+	}
+
+	if flagNoAgents || cfg.NoAgents {
+		delete(wanted, "agents")
+	}
+	if flagNoCopilot || cfg.NoCopilot {
+		delete(wanted, "copilot")
+		delete(wanted, "copilot-review")
+		delete(wanted, "copilot-commit")
+		delete(wanted, "copilot-pr")
+		delete(wanted, "copilot-prompts")
+		delete(wanted, "copilot-chatmodes")
+	}
+
+	var out []target
+	for _, t := range targets {
+		if wanted[t.Name] {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// renderedFile is one output file produced by generate and checked by validate.
+type renderedFile struct {
+	Label   string
+	Path    string
+	Content string
+}
+
+// finalizeFiles runs the passes applied to every output before it is written
+// or compared: secret scanning, HTML sanitizing, markdownlint fixes, the
+// --format conversion and the optional stamp.
+func finalizeFiles(files []renderedFile) ([]renderedFile, error) {
+	files, err := guardFileSecrets(files)
+	if err != nil {
+		return nil, err
+	}
+	files = sanitizeFiles(files)
+	if files, err = lintFiles(files); err != nil {
+		return nil, err
+	}
+	if files, err = convertFiles(files); err != nil {
+		return nil, err
+	}
+	return stampFiles(files)
+}
+
+// renderTargets renders the merged content for every selected target,
+// concurrently, keeping the registry order in the result. The agents target
+// receives agentsContent (which may carry per-project links), category
+// targets the content of their category and, when summary is set
+// (--summarize), targets with LinkedFiles the summary in place of content.
+func renderTargets(selected []target, content, agentsContent, summary string, categoryContents map[string]string, copilotPath, assetsDir string) []renderedFile {
+	rendered := make([][]renderedFile, len(selected))
+	var g errgroup.Group
+	g.SetLimit(runtime.GOMAXPROCS(0))
+	for i, t := range selected {
+		if t.Files != nil {
+			continue
+		}
+		g.Go(func() error {
+			outPath := t.Path
+			body := content
+			switch t.Name {
+			case "copilot":
+				outPath = copilotPath
+			case "agents":
+				body = agentsContent
+			}
+			if t.Category != "" {
+				if body = categoryContents[t.Category]; body == "" {
+					return nil
+				}
+			}
+			if t.LinkedFiles && summary != "" {
+				// Keeps what agentsContent adds to content (subproject links)
+				body = summary + strings.TrimPrefix(body, content)
+			}
+
+			body = wrapBoilerplate(resolveDetailLinks(resolveAssetLinks(body, outPath, assetsDir), outPath))
+			if t.Render != nil {
+				body = t.Render(body)
+			}
+			if t.Name == "copilot" {
+				body = markGenerated(body)
+			}
+			files := []renderedFile{{Label: t.Label, Path: filepath.ToSlash(outPath), Content: body}}
+			if t.Companions != nil {
+				files = append(files, t.Companions(outPath, body)...)
+			}
+			rendered[i] = files
+			return nil
+		})
+	}
+	_ = g.Wait() // rendering the content cannot fail
+	return slices.Concat(rendered...)
+}
+
+// renderFileTargets renders the selected multi-file targets for the stack,
+// concurrently, keeping the registry order in the result.
+func renderFileTargets(selected []target, stack *detect.DetectedStack) ([]renderedFile, error) {
+	rendered := make([][]renderedFile, len(selected))
+	var g errgroup.Group
+	g.SetLimit(runtime.GOMAXPROCS(0))
+	for i, t := range selected {
+		if t.Files == nil {
+			continue
+		}
+		g.Go(func() error {
+			files, err := t.Files(stack, selected)
+			rendered[i] = files
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return slices.Concat(rendered...), nil
+}
+
+// wrapBoilerplate adds the configured header, sections and footer blocks around content.
+func wrapBoilerplate(content string) string {
+	if sections := configSections(); sections != "" {
+		content = sections + "\n\n---\n\n" + content
+	}
+	if header := strings.TrimSpace(cfg.Header); header != "" {
+		content = header + "\n\n" + content
+	}
+	if footer := strings.TrimSpace(cfg.Footer); footer != "" {
+		content = strings.TrimRight(content, "\n") + "\n\n" + footer
+	}
+	return content
+}
+
+// aiderCompanions returns the .aider.conf.yml that makes Aider read the conventions file.
+func aiderCompanions(outPath, _ string) []renderedFile {
+	content := "# Generated by ai-instructions. Do not edit manually.\n" +
+		"read:\n" +
+		"  - " + filepath.ToSlash(outPath) + "\n"
+	return []renderedFile{{Label: "AIDER", Path: ".aider.conf.yml", Content: content}}
+}
+
+// geminiCompanions returns the style guide used by Gemini Code Assist code review.
+func geminiCompanions(_, content string) []renderedFile {
+	return []renderedFile{{Label: "GEMINI", Path: ".gemini/styleguide.md", Content: content}}
+}
+
+// renderContinueRule wraps the instructions in a Continue.dev rule block.
+func renderContinueRule(content string) string {
+	return "---\n" +
+		"name: Project instructions (ai-instructions)\n" +
+		"alwaysApply: true\n" +
+		"---\n\n" +
+		content
+}
+
+// hasTarget reports whether a target with the given name is selected.
+func hasTarget(selected []target, name string) bool {
+	for _, t := range selected {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// addTargetFlags registers the target selection flags shared by generate and validate.
+func addTargetFlags(cmd *cobra.Command, verb string) {
+	cmd.Flags().StringSliceVar(
+		&flagTargets,
+		"target",
+		nil,
+		"Output target(s) to "+verb+": "+strings.Join(targetNames(), ", ")+" (default "+strings.Join(defaultTargets, ",")+")",
+	)
+
+	cmd.Flags().BoolVar(
+		&flagNoAgents,
+		"no-agents",
+		false,
+		"Do not "+verb+" AGENTS.md",
+	)
+
+	cmd.Flags().BoolVar(
+		&flagNoCopilot,
+		"no-copilot",
+		false,
+		"Do not "+verb+" .github/copilot-instructions.md and the other Copilot customization files",
+	)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/rules"
+)
+
+// buildTypeScriptSection tells the AI how strict the TypeScript config is, so
+// generated code keeps (or improves) the type coverage.
+func buildTypeScriptSection(stack *detect.DetectedStack) string {
+	if stack == nil || stack.TSConfig == nil {
+		return ""
+	}
+	c := stack.TSConfig
+
+	var lines []string
+	if c.IsStrict() {
+		lines = append(lines, fmt.Sprintf("- `%s` has `strict` enabled: new code must type-check under strict mode; never disable strict flags.", c.Path))
+	} else {
+		lines = append(lines, fmt.Sprintf("- `%s` does not enable `strict`: write new code as if it did, and do not weaken any existing checks.", c.Path))
+	}
+	if !c.NoImplicitAnyEnabled() {
+		lines = append(lines, "- `noImplicitAny` is off: still annotate parameters and return types explicitly instead of relying on implicit `any`.")
+	}
+	if !c.StrictNullChecksEnabled() {
+		lines = append(lines, "- `strictNullChecks` is off: still handle `null`/`undefined` explicitly.")
+	}
+	lines = append(lines, "- Do not add `any`, `@ts-ignore` or `@ts-expect-error` to silence errors; fix the types (use `unknown` and narrow when the type is not known).")
+
+	return "## TypeScript\n\n" + strings.Join(lines, "\n")
+}
+
+// unmetRequirements returns "rule: requirement" for every selected rule whose
+// `requires:` is not satisfied by the project.
+func unmetRequirements(stack *detect.DetectedStack, ids []string) []string {
+	var unmet []string
+	for _, id := range ids {
+		r, err := rules.Load(id)
+		if err != nil {
+			continue
+		}
+		for _, req := range r.Meta.Requires {
+			if !requirementMet(stack, req) {
+				unmet = append(unmet, fmt.Sprintf("rules/%s.md requires %s", id, req))
+			}
+		}
+	}
+	return unmet
+}
+
+func requirementMet(stack *detect.DetectedStack, req string) bool {
+	switch req {
+	case rules.RequireTypeScriptStrict:
+		return stack != nil && stack.TSConfig.IsStrict()
+	}
+	// Unknown requirements are reported by rules lint, not enforced
+	return true
+}
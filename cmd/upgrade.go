@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/markdown"
+	"github.com/cego/ai-instructions/rules"
+)
+
+var flagUpgradeNotes bool
+
+// upgradeRuleFile names upgrade guides, e.g. rules/laravel/10-to-11/upgrade.md.
+const upgradeRuleFile = "upgrade"
+
+// upgradeRules returns the upgrade guides starting at the detected major
+// version of each technology, e.g. laravel/10-to-11/upgrade for Laravel ^10.
+func upgradeRules(stack *detect.DetectedStack) []string {
+	ids, err := rules.Glob("*/*-to-*/" + upgradeRuleFile)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, id := range ids {
+		parts := strings.Split(id, "/")
+		from, _, _ := strings.Cut(parts[1], "-to-")
+		major, _, _ := strings.Cut(normalizeVersion(stack.Version(parts[0])), ".")
+		if major != "" && major == from {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// buildUpgradeSection summarizes the breaking changes of the next major
+// version of the detected technologies. It is empty unless --upgrade-notes
+// is set.
+func buildUpgradeSection(stack *detect.DetectedStack) string {
+	if !flagUpgradeNotes || stack == nil {
+		return ""
+	}
+	ids := upgradeRules(stack)
+	if len(ids) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Upgrade considerations\n\n")
+	b.WriteString("A newer major version is available. When helping with the upgrade, " +
+		"or when writing new code, account for these breaking changes:")
+	for _, id := range ids {
+		body, err := rules.Get(id)
+		if err != nil {
+			continue
+		}
+		b.WriteString("\n\n")
+		b.WriteString(strings.TrimSpace(markdown.ShiftHeadings(body, 2)))
+	}
+	return b.String()
+}
+
+// addUpgradeNotesFlag registers --upgrade-notes on a command that renders instructions.
+func addUpgradeNotesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flagUpgradeNotes,
+		"upgrade-notes",
+		false,
+		"Add an upgrade considerations section when rules/<name>/<major>-to-<next>/upgrade.md exists for a detected version",
+	)
+}
+
+func init() {
+	addUpgradeNotesFlag(generateCmd)
+	addUpgradeNotesFlag(validateCmd)
+	addUpgradeNotesFlag(exportCmd)
+}
@@ -9,19 +9,25 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/cego/ai-instructions/internal/detect"
+	"github.com/cego/ai-instructions/internal/warnings"
 	"github.com/cego/ai-instructions/rules"
 )
 
 var validateCmd = &cobra.Command{
-	Use:   "validate",
-	Short: "Validate tech stack and ensure generated files are up to date",
+	Use:     "validate",
+	Short:   "Validate tech stack and ensure generated files are up to date",
+	GroupID: groupGeneration,
+	Example: "  ai-instructions validate\n" +
+		"\n" +
+		"  # In a pull request of a monorepo, check only the touched subprojects\n" +
+		"  ai-instructions validate --per-project --changed-since origin/main",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// 1) Basic embed sanity check
-		list, err := rules.List()
+		count, err := rules.Count()
 		if err != nil {
-			return fmt.Errorf("rules.List failed: %w", err)
+			return fmt.Errorf("rules.Count failed: %w", err)
 		}
-		if len(list) == 0 {
+		if count == 0 {
 			return fmt.Errorf("embedded rules are empty")
 		}
 
@@ -30,61 +36,38 @@ var validateCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("stack detection failed: %w", err)
 		}
+		if err := applyStackOverrides(stack); err != nil {
+			return err
+		}
 
-		// Resolve general rules
-		generalIDs := buildGeneralRulesFromDetection(stack)
-		if len(generalIDs) == 0 {
-			return fmt.Errorf("no general rules resolved from detection")
+		if err := checkStrict(stack, buildGeneralRulesFromDetection(stack)); err != nil {
+			return err
 		}
-		for _, id := range generalIDs {
-			if !ruleExists(id) {
-				return fmt.Errorf("missing embedded rule: 'rules/%s.md'", id)
-			}
+		if flagWithAudit && len(flagAuditReports) == 0 {
+			// Live results would fail validation whenever an advisory is published
+			warnings.Add("audit", "live audit results are not validated; pass --audit-report to compare a report")
+			flagWithAudit = false
 		}
-		generalContent, err := loadAndMergeRules(generalIDs)
+
+		files, err := buildExpectedFiles(stack)
 		if err != nil {
-			return fmt.Errorf("failed to merge general rules: %w", err)
+			return err
 		}
-
-		// Prepend stack section like generate does
-		stackSection := buildStackSection(stack)
-		if stackSection != "" {
-			var b bytes.Buffer
-			b.WriteString(stackSection)
-			b.WriteString("\n\n---\n\n")
-			b.WriteString(generalContent)
-			generalContent = b.String()
+		if flagDeterminism > 1 {
+			if err := checkDeterminism(files, flagDeterminism); err != nil {
+				return err
+			}
 		}
 
-		// Compare current files against expected content
-		copilotPath := filepath.ToSlash(".github/copilot-instructions.md")
-		agentsPath := filepath.ToSlash("AGENTS.md")
-
-		copilotStatus := compareFileStatus(copilotPath, generalContent)
-		agentsStatus := compareFileStatus(agentsPath, generalContent)
-
 		// 5) Report detailed status
 		var hadError bool
-		switch copilotStatus {
-		case statusMissing:
-			fmt.Printf("Missing: '%s'\n", copilotPath)
-			hadError = true
-		case statusOutdated:
-			fmt.Printf("Outdated: '%s'\n", copilotPath)
-			hadError = true
-		case statusUpToDate:
-			fmt.Printf("Up to date: '%s'\n", copilotPath)
-		}
-
-		switch agentsStatus {
-		case statusMissing:
-			fmt.Printf("Missing: '%s'\n", agentsPath)
-			hadError = true
-		case statusOutdated:
-			fmt.Printf("Outdated: '%s'\n", agentsPath)
-			hadError = true
-		case statusUpToDate:
-			fmt.Printf("Up to date: '%s'\n", agentsPath)
+		for _, f := range files {
+			hadError = reportFileStatus(f.Path, compareFileStatus(f.Path, f.Content)) || hadError
+			if existing, err := os.ReadFile(f.Path); err == nil {
+				for _, e := range expiredContent(string(existing)) {
+					warnings.Add("validate", "%s still contains %s; run generate to drop it", f.Path, e)
+				}
+			}
 		}
 
 		if hadError {
@@ -98,6 +81,36 @@ var validateCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().BoolVar(
+		&flagInlineAssets,
+		"inline-assets",
+		false,
+		"Expect images referenced by rules to be inlined as data URIs",
+	)
+
+	addTargetFlags(validateCmd, "validate")
+
+	validateCmd.Flags().BoolVar(
+		&flagPerProject,
+		"per-project",
+		false,
+		"Also validate the AGENTS.md of each detected subproject",
+	)
+
+	validateCmd.Flags().BoolVar(
+		&flagStrict,
+		"strict",
+		false,
+		"Fail on missing expected rules and on rule requirements the project does not meet (e.g. TypeScript strict mode)",
+	)
+
+	validateCmd.Flags().BoolVar(
+		&flagAnnotateSources,
+		"annotate-sources",
+		false,
+		"Expect source annotations after each section",
+	)
 }
 
 type fileStatus int
@@ -108,9 +121,111 @@ const (
 	statusOutdated
 )
 
+// buildExpectedFiles computes every file generate would write for the stack
+// and the selected targets. It renders in memory only: validate and detect
+// never write, so anything that fixes files belongs in generate.
+func buildExpectedFiles(stack *detect.DetectedStack) ([]renderedFile, error) {
+	// Resolve general rules
+	generalIDs := buildGeneralRulesFromDetection(stack)
+	if len(generalIDs) == 0 {
+		return nil, fmt.Errorf("no general rules resolved from detection")
+	}
+	for _, id := range generalIDs {
+		if !ruleExists(id) {
+			return nil, fmt.Errorf("missing embedded rule: 'rules/%s.md'", id)
+		}
+	}
+	generalContent, err := loadAndMergeRules(generalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge general rules: %w", err)
+	}
+
+	// Prepend stack section like generate does
+	detected := buildDetectedSections(".", stack)
+	if detected != "" {
+		var b bytes.Buffer
+		b.WriteString(detected)
+		b.WriteString("\n\n---\n\n")
+		b.WriteString(generalContent)
+		generalContent = b.String()
+	}
+
+	// Compare current files against expected content
+	selected, err := selectedTargets(flagTargets)
+	if err != nil {
+		return nil, err
+	}
+
+	copilotPath := filepath.ToSlash(".github/copilot-instructions.md")
+	assetsDir := assetsDirFor(copilotPath)
+
+	agentsContent, err := addAgentLevel(generalContent)
+	if err != nil {
+		return nil, err
+	}
+	var subprojects []subprojectFile
+	if flagPerProject && hasTarget(selected, "agents") {
+		scope, err := resolvePathScope()
+		if err != nil {
+			return nil, err
+		}
+		subprojects, err = buildSubprojectFiles(".", scope)
+		if err != nil {
+			return nil, fmt.Errorf("subproject detection failed: %w", err)
+		}
+		if section := buildSubprojectsSection(subprojects); section != "" {
+			agentsContent += "\n\n---\n\n" + section
+		}
+	}
+
+	categoryContents, err := buildCategoryContents(categoryRuleIDs(stack), stack)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge category rules: %w", err)
+	}
+
+	summary, details, err := buildSummaryContent(selected, generalIDs, detected, assetsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := renderTargets(selected, generalContent, agentsContent, summary, categoryContents, copilotPath, assetsDir)
+	files = append(files, details...)
+	fileTargets, err := renderFileTargets(selected, stack)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, fileTargets...)
+	files = append(files, renderSubprojects(subprojects, assetsDir)...)
+	outputs, err := buildOutputFiles(stack, generalIDs, buildAgentRulesFromDetection(stack), assetsDir)
+	if err != nil {
+		return nil, err
+	}
+	return finalizeFiles(append(files, outputs...))
+}
+
+// reportFileStatus prints the status of a file and reports whether it is a failure.
+func reportFileStatus(path string, status fileStatus) bool {
+	switch status {
+	case statusMissing:
+		fmt.Printf("Missing: '%s'\n", path)
+		return true
+	case statusOutdated:
+		fmt.Printf("Outdated: '%s'\n", path)
+		return true
+	}
+	fmt.Printf("Up to date: '%s'\n", path)
+	return false
+}
+
 // compareFileStatus returns whether a file is missing, outdated, or up to date.
 func compareFileStatus(path string, expected string) fileStatus {
 	data, err := os.ReadFile(path)
+	return fileStatusOf(data, err, expected)
+}
+
+// fileStatusOf compares the current content of a file (data and the error of
+// reading it) with the expected content.
+func fileStatusOf(data []byte, err error, expected string) fileStatus {
 	if err != nil {
 		if os.IsNotExist(err) {
 			return statusMissing
@@ -118,6 +233,7 @@ func compareFileStatus(path string, expected string) fileStatus {
 		// Treat unreadable as outdated
 		return statusOutdated
 	}
+	data, expected = withoutLiveAudit(data, expected)
 	if bytes.Equal(bytes.TrimSpace(data), bytes.TrimSpace([]byte(expected))) {
 		return statusUpToDate
 	}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/cego/ai-instructions/internal/config"
+	"github.com/cego/ai-instructions/internal/policy"
+	"github.com/cego/ai-instructions/internal/warnings"
+	"github.com/cego/ai-instructions/internal/watch"
+	"github.com/cego/ai-instructions/rules"
+)
+
+var flagWatch bool
+
+// Files whose changes trigger regeneration in watch mode (besides the rules directory).
+var watchedManifests = []string{
+	"composer.json",
+	"composer.lock",
+	"package.json",
+	"package-lock.json",
+}
+
+// watchAndRegenerate regenerates all outputs whenever local rules, the config
+// or manifests change, until interrupted.
+func watchAndRegenerate(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	paths := append([]string{flagRulesDir, flagConfig, policyPath(policy.DefaultPath)}, watchedManifests...)
+	fmt.Printf("Watching %s, %s, the policy and manifests for changes (Ctrl+C to stop)...\n", flagRulesDir, flagConfig)
+
+	return watch.Poll(ctx, paths, 500*time.Millisecond, func() {
+		fmt.Println("\nChange detected, regenerating...")
+		if err := reloadInputs(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return
+		}
+		warnings.Reset()
+		if err := runGenerate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		warnings.PrintSummary(os.Stderr)
+	})
+}
+
+// reloadInputs re-reads the config and local rules, invalidating cached content.
+func reloadInputs() error {
+	loaded, err := config.Load(flagConfig)
+	if err != nil {
+		return err
+	}
+	cfg = loaded
+	if err := checkMinVersion(); err != nil {
+		return err
+	}
+	rules.SetExperiments(activeExperiments())
+	if err := loadPolicy(policy.DefaultPath); err != nil {
+		return err
+	}
+
+	if err := useLocalRules(flagRulesDir); err != nil {
+		return err
+	}
+	rules.Reload()
+	return nil
+}
@@ -0,0 +1,119 @@
+// Package aiignore reads .aiignore, the list of paths AI agents must neither
+// read nor modify. Patterns follow a subset of the .gitignore syntax.
+package aiignore
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// File is the ignore file in the project root.
+const File = ".aiignore"
+
+// List is the parsed .aiignore. A nil List matches nothing.
+type List struct {
+	// Patterns are the lines as written, without comments and blank lines.
+	Patterns []string
+
+	rules []rule
+}
+
+type rule struct {
+	pattern  string
+	anchored bool // matched against the path from the root, else against each name
+	floating bool // a leading **/: the path may start in any directory
+	dirOnly  bool // a trailing slash: directories (and what is below them) only
+}
+
+// Load reads the .aiignore of projectRoot (nil when there is none).
+func Load(projectRoot string) (*List, error) {
+	f, err := os.Open(filepath.Join(projectRoot, File))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	l := &List{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		l.Add(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Add adds a pattern line. Comments, blank lines and negations ("!pattern",
+// not supported) are skipped.
+func (l *List) Add(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+		return
+	}
+	l.Patterns = append(l.Patterns, line)
+
+	r := rule{pattern: line}
+	if p, ok := strings.CutSuffix(r.pattern, "/"); ok {
+		r.pattern, r.dirOnly = p, true
+	}
+	if p, ok := strings.CutPrefix(r.pattern, "**/"); ok {
+		r.pattern, r.floating = p, true
+	} else if p, ok := strings.CutPrefix(r.pattern, "/"); ok {
+		r.pattern, r.anchored = p, true
+	}
+	if strings.Contains(r.pattern, "/") {
+		r.anchored = true
+	}
+	l.rules = append(l.rules, r)
+}
+
+// Empty reports whether the list has no patterns.
+func (l *List) Empty() bool {
+	return l == nil || len(l.rules) == 0
+}
+
+// Match reports whether rel (relative to the project root, slash separated)
+// or one of its parent directories is listed.
+func (l *List) Match(rel string, isDir bool) bool {
+	if l.Empty() {
+		return false
+	}
+	parts := strings.Split(path.Clean(filepath.ToSlash(rel)), "/")
+	for i := range parts {
+		dir := i < len(parts)-1 || isDir
+		for _, r := range l.rules {
+			if r.dirOnly && !dir {
+				continue
+			}
+			if r.matches(parts[:i+1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matches reports whether the rule matches the path of parts.
+func (r rule) matches(parts []string) bool {
+	if !r.anchored {
+		ok, _ := path.Match(r.pattern, parts[len(parts)-1])
+		return ok
+	}
+	start := 0
+	if r.floating {
+		start = len(parts) - 1
+	}
+	for j := 0; j <= start; j++ {
+		if ok, _ := path.Match(r.pattern, strings.Join(parts[j:], "/")); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+// Package audit reads dependency vulnerability reports (composer audit,
+// npm audit) from their JSON output, or runs the tools to produce them.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Finding is a dependency with known vulnerabilities.
+type Finding struct {
+	Ecosystem string `json:"ecosystem"` // "composer" or "npm"
+	Package   string `json:"package"`
+	Severity  string `json:"severity,omitempty"`
+	// Advisories are the advisory titles (or IDs when untitled).
+	Advisories []string `json:"advisories,omitempty"`
+}
+
+// severityRank orders severities; unknown ones rank lowest.
+var severityRank = map[string]int{"low": 1, "moderate": 2, "medium": 2, "high": 3, "critical": 4}
+
+// Parse reads a composer audit or npm audit JSON report.
+func Parse(data []byte) ([]Finding, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("invalid audit report: %w", err)
+	}
+	switch {
+	case probe["advisories"] != nil:
+		return parseComposer(probe["advisories"])
+	case probe["vulnerabilities"] != nil:
+		return parseNPM(probe["vulnerabilities"])
+	}
+	return nil, fmt.Errorf("unknown audit report format (expected composer audit or npm audit JSON)")
+}
+
+func parseComposer(raw json.RawMessage) ([]Finding, error) {
+	// composer prints [] instead of {} when there are no advisories
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		return nil, nil
+	}
+	var advisories map[string][]struct {
+		AdvisoryID string `json:"advisoryId"`
+		Title      string `json:"title"`
+		CVE        string `json:"cve"`
+		Severity   string `json:"severity"`
+	}
+	if err := json.Unmarshal(raw, &advisories); err != nil {
+		return nil, fmt.Errorf("invalid composer audit report: %w", err)
+	}
+	var out []Finding
+	for pkg, list := range advisories {
+		f := Finding{Ecosystem: "composer", Package: pkg}
+		for _, a := range list {
+			f.Severity = maxSeverity(f.Severity, a.Severity)
+			f.Advisories = append(f.Advisories, firstNonEmpty(a.Title, a.CVE, a.AdvisoryID))
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+func parseNPM(raw json.RawMessage) ([]Finding, error) {
+	var vulns map[string]struct {
+		Name     string            `json:"name"`
+		Severity string            `json:"severity"`
+		Via      []json.RawMessage `json:"via"`
+	}
+	if err := json.Unmarshal(raw, &vulns); err != nil {
+		return nil, fmt.Errorf("invalid npm audit report: %w", err)
+	}
+	var out []Finding
+	for name, v := range vulns {
+		f := Finding{Ecosystem: "npm", Package: firstNonEmpty(v.Name, name), Severity: v.Severity}
+		for _, via := range v.Via {
+			// via holds advisories, or names of vulnerable dependencies
+			var advisory struct {
+				Title string `json:"title"`
+				URL   string `json:"url"`
+			}
+			if json.Unmarshal(via, &advisory) == nil {
+				if t := firstNonEmpty(advisory.Title, advisory.URL); t != "" {
+					f.Advisories = append(f.Advisories, t)
+				}
+			}
+		}
+		// Packages only vulnerable through a dependency are reported there
+		if len(f.Advisories) > 0 {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// Run runs composer audit and npm audit in dir for the lockfiles present
+// there. Tools that are not installed are skipped.
+func Run(ctx context.Context, dir string) ([]Finding, error) {
+	var out []Finding
+	for _, tool := range []struct {
+		lockfile string
+		name     string
+		args     []string
+	}{
+		{"composer.lock", "composer", []string{"audit", "--format=json", "--locked", "--no-interaction"}},
+		{"package-lock.json", "npm", []string{"audit", "--json"}},
+	} {
+		if _, err := os.Stat(filepath.Join(dir, tool.lockfile)); err != nil {
+			continue
+		}
+		if _, err := exec.LookPath(tool.name); err != nil {
+			continue
+		}
+		cmd := exec.CommandContext(ctx, tool.name, tool.args...)
+		cmd.Dir = dir
+		data, err := cmd.Output()
+		// Both tools exit non-zero when they find vulnerabilities
+		var exitErr *exec.ExitError
+		if err != nil && !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("%s audit: %w", tool.name, err)
+		}
+		findings, perr := Parse(data)
+		if perr != nil {
+			if err != nil {
+				return nil, fmt.Errorf("%s audit: %w", tool.name, err)
+			}
+			return nil, fmt.Errorf("%s audit: %w", tool.name, perr)
+		}
+		out = append(out, findings...)
+	}
+	return out, nil
+}
+
+// Sort orders findings by ecosystem and package, with advisories sorted,
+// so output built from them is stable.
+func Sort(findings []Finding) {
+	for _, f := range findings {
+		sort.Strings(f.Advisories)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Ecosystem != findings[j].Ecosystem {
+			return findings[i].Ecosystem < findings[j].Ecosystem
+		}
+		return findings[i].Package < findings[j].Package
+	})
+}
+
+func maxSeverity(a, b string) string {
+	if severityRank[strings.ToLower(b)] > severityRank[strings.ToLower(a)] {
+		return strings.ToLower(b)
+	}
+	return a
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+package condition
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Eval evaluates a simple boolean expression against the given variables.
+//
+// Supported syntax:
+//
+//	stack.Laravel != "" && (stack.Vue != "" || stack.Nuxt != "")
+//	!stack.NuxtUI
+//
+// Identifiers resolve through vars (missing identifiers are empty strings),
+// operands are identifiers or double-quoted strings, and a bare operand is
+// true when it is non-empty.
+func Eval(expr string, vars map[string]string) (bool, error) {
+	toks, err := tokenize(expr)
+	if err != nil {
+		return false, err
+	}
+	p := &parser{toks: toks, vars: vars}
+	v, err := p.or()
+	if err != nil {
+		return false, err
+	}
+	if p.pos < len(p.toks) {
+		return false, fmt.Errorf("unexpected %q at end of expression", p.toks[p.pos].text)
+	}
+	return v, nil
+}
+
+// Validate reports whether the expression is syntactically valid.
+func Validate(expr string) error {
+	_, err := Eval(expr, nil)
+	return err
+}
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(expr string) ([]token, error) {
+	var toks []token
+	rs := []rune(expr)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			j := i + 1
+			var b strings.Builder
+			for j < len(rs) && rs[j] != '"' {
+				if rs[j] == '\\' && j+1 < len(rs) {
+					j++
+				}
+				b.WriteRune(rs[j])
+				j++
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("unterminated string in %q", expr)
+			}
+			toks = append(toks, token{kind: tokString, text: b.String()})
+			i = j + 1
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_' || rs[j] == '.') {
+				j++
+			}
+			toks = append(toks, token{kind: tokIdent, text: string(rs[i:j])})
+			i = j
+		default:
+			two := ""
+			if i+1 < len(rs) {
+				two = string(rs[i : i+2])
+			}
+			switch {
+			case two == "&&" || two == "||" || two == "==" || two == "!=":
+				toks = append(toks, token{kind: tokOp, text: two})
+				i += 2
+			case r == '!' || r == '(' || r == ')':
+				toks = append(toks, token{kind: tokOp, text: string(r)})
+				i++
+			default:
+				return nil, fmt.Errorf("unexpected character %q in %q", r, expr)
+			}
+		}
+	}
+	return toks, nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+	vars map[string]string
+}
+
+func (p *parser) peek(op string) bool {
+	return p.pos < len(p.toks) && p.toks[p.pos].kind == tokOp && p.toks[p.pos].text == op
+}
+
+func (p *parser) or() (bool, error) {
+	v, err := p.and()
+	if err != nil {
+		return false, err
+	}
+	for p.peek("||") {
+		p.pos++
+		r, err := p.and()
+		if err != nil {
+			return false, err
+		}
+		v = v || r
+	}
+	return v, nil
+}
+
+func (p *parser) and() (bool, error) {
+	v, err := p.unary()
+	if err != nil {
+		return false, err
+	}
+	for p.peek("&&") {
+		p.pos++
+		r, err := p.unary()
+		if err != nil {
+			return false, err
+		}
+		v = v && r
+	}
+	return v, nil
+}
+
+func (p *parser) unary() (bool, error) {
+	if p.peek("!") {
+		p.pos++
+		v, err := p.unary()
+		return !v, err
+	}
+	if p.peek("(") {
+		p.pos++
+		v, err := p.or()
+		if err != nil {
+			return false, err
+		}
+		if !p.peek(")") {
+			return false, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return v, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (bool, error) {
+	left, err := p.operand()
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case p.peek("=="):
+		p.pos++
+		right, err := p.operand()
+		return left == right, err
+	case p.peek("!="):
+		p.pos++
+		right, err := p.operand()
+		return left != right, err
+	}
+	return left != "", nil
+}
+
+func (p *parser) operand() (string, error) {
+	if p.pos >= len(p.toks) {
+		return "", fmt.Errorf("unexpected end of expression")
+	}
+	t := p.toks[p.pos]
+	p.pos++
+	switch t.kind {
+	case tokString:
+		return t.text, nil
+	case tokIdent:
+		return p.vars[t.text], nil
+	}
+	return "", fmt.Errorf("unexpected %q", t.text)
+}
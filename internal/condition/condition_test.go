@@ -0,0 +1,74 @@
+package condition
+
+import "testing"
+
+func TestEval(t *testing.T) {
+	vars := map[string]string{
+		"stack.Laravel":     "11.0",
+		"stack.Vue":         "3.4",
+		"stack.PHPManifest": "composer.json",
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`stack.Laravel`, true},
+		{`stack.Nuxt`, false},
+		{`!stack.Nuxt`, true},
+		{`!!stack.Vue`, true},
+		{`stack.Laravel != ""`, true},
+		{`stack.Nuxt == ""`, true},
+		{`stack.PHPManifest == "composer.json"`, true},
+		{`"composer.json" == stack.PHPManifest`, true},
+		{`stack.PHPManifest == ".tool-versions"`, false},
+		{`stack.Laravel && stack.Vue`, true},
+		{`stack.Laravel && stack.Nuxt`, false},
+		{`stack.Nuxt || stack.Vue`, true},
+		{`stack.Nuxt || stack.React`, false},
+		// && binds tighter than ||
+		{`stack.Vue || stack.Nuxt && stack.React`, true},
+		{`(stack.Vue || stack.Nuxt) && stack.React`, false},
+		{`stack.Laravel != "" && (stack.Vue != "" || stack.Nuxt != "")`, true},
+		{`!(stack.Laravel && stack.Vue)`, false},
+		{`"a \"quoted\" value" == "a \"quoted\" value"`, true},
+		{`stack.Missing_Name2 == ""`, true},
+		{"  stack.Vue\t", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Eval(tt.expr, vars)
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []string{
+		``,
+		`stack.Vue &&`,
+		`stack.Vue ==`,
+		`(stack.Vue`,
+		`stack.Vue)`,
+		`stack.Vue stack.Nuxt`,
+		`stack.Vue == "3.4`,
+		`stack.Vue = "3.4"`,
+		`stack.Vue & stack.Nuxt`,
+		`stack.Vue > "3"`,
+		`&& stack.Vue`,
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := Eval(expr, nil); err == nil {
+				t.Errorf("Eval(%q) succeeded, want an error", expr)
+			}
+			if err := Validate(expr); err == nil {
+				t.Errorf("Validate(%q) succeeded, want an error", expr)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultPath is the project config file read when no --config is given.
+const DefaultPath = ".ai-instructions.yaml"
+
+// Config is the optional project configuration (.ai-instructions.yaml).
+type Config struct {
+	// MinVersion is the oldest CLI release the project supports, e.g. 1.4.0;
+	// older binaries refuse to run.
+	MinVersion string `yaml:"minVersion,omitempty"`
+
+	// Targets selects the outputs to generate/validate (same as --target).
+	Targets []string `yaml:"targets,omitempty"`
+	// NoAgents disables AGENTS.md (same as --no-agents).
+	NoAgents bool `yaml:"noAgents,omitempty"`
+	// NoCopilot disables .github/copilot-instructions.md (same as --no-copilot).
+	NoCopilot bool `yaml:"noCopilot,omitempty"`
+
+	// Header is injected at the top of every generated instructions file
+	// (e.g. a license notice or "do not edit" banner).
+	Header string `yaml:"header,omitempty"`
+	// Footer is appended to every generated instructions file.
+	Footer string `yaml:"footer,omitempty"`
+
+	// Outputs are additional named documents generated and validated
+	// alongside the targets, e.g. a security-only file for a review agent.
+	Outputs []Output `yaml:"outputs,omitempty"`
+
+	// Sections are extra markdown sections added after the header; each is
+	// left out after its expiry date (YYYY-MM-DD, optional).
+	Sections []Section `yaml:"sections,omitempty"`
+
+	// Packs enables optional rule packs by tag, e.g. [a11y] (same as --pack).
+	Packs []string `yaml:"packs,omitempty"`
+
+	// Experiments selects rule variants (rules/<id>@<experiment>.md), in order
+	// of preference (same as --experiment).
+	Experiments []string `yaml:"experiments,omitempty"`
+
+	// Policy is the organization's AI policy file, rendered as the
+	// Guardrails section (default .ai-instructions/policy.yaml when it exists).
+	Policy string `yaml:"policy,omitempty"`
+
+	// AgentLevel is the autonomy level agents work at (readonly-assistant,
+	// pair-programmer or autonomous-agent); AGENTS.md gets the guardrails of
+	// the level (same as --agent-level).
+	AgentLevel string `yaml:"agentLevel,omitempty"`
+
+	// Branches adds rules on matching git branches, e.g. a bugfix-only rule
+	// on release/* branches. Every matching profile applies, in order.
+	Branches []BranchProfile `yaml:"branches,omitempty"`
+
+	// Glossary is the project's domain language, rendered as the Glossary
+	// section.
+	Glossary Glossary `yaml:"glossary,omitempty"`
+
+	// Permissions are the commands and hosts agent tools may use, emitted as
+	// tool configuration by the claude-settings and copilot-firewall targets.
+	Permissions Permissions `yaml:"permissions,omitempty"`
+
+	// Markdownlint fixes generated markdown for a subset of markdownlint rules.
+	Markdownlint Markdownlint `yaml:"markdownlint,omitempty"`
+
+	// Lint configures rules lint.
+	Lint Lint `yaml:"lint,omitempty"`
+}
+
+// Glossary selects glossary rules and defines project terms.
+type Glossary struct {
+	// Rules are glossary rules (rules/glossary/<name>), e.g. [igaming].
+	Rules []string `yaml:"rules,omitempty"`
+	// Terms are project terms; they replace rule terms of the same name.
+	Terms []Term `yaml:"terms,omitempty"`
+}
+
+// Term is one glossary entry.
+type Term struct {
+	Term       string `yaml:"term"`
+	Definition string `yaml:"definition"`
+	// Aliases are other names in use for the term (e.g. in older code), such
+	// as "player" for "punter".
+	Aliases []string `yaml:"aliases,omitempty"`
+}
+
+// Permissions are allow and deny lists for agent tools.
+type Permissions struct {
+	// Allow are commands agents may run without asking, e.g. "npm run test".
+	Allow []string `yaml:"allow,omitempty"`
+	// Deny are commands agents must never run; the forbidden commands of the
+	// policy are added to them.
+	Deny []string `yaml:"deny,omitempty"`
+	// Domains are the hosts agents may reach, e.g. registry.npmjs.org.
+	Domains []string `yaml:"domains,omitempty"`
+}
+
+// Markdownlint selects the markdownlint rules generated files are fixed for.
+type Markdownlint struct {
+	// Rules are markdownlint IDs, e.g. [MD001, MD009, MD040]; empty means
+	// all supported rules when --markdownlint is set, and none otherwise.
+	Rules []string `yaml:"rules,omitempty"`
+	// CodeLanguage is added to fenced code blocks without a language (MD040).
+	CodeLanguage string `yaml:"codeLanguage,omitempty"`
+}
+
+// Output is a named document with its own path and rule selection.
+type Output struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+	// Rules selects the content: "general" (default, the detected rules) or
+	// "agents" (the agent rules).
+	Rules string `yaml:"rules,omitempty"`
+	// Tags limits general rules to the tagged rules and sections (see --tags).
+	Tags []string `yaml:"tags,omitempty"`
+}
+
+// Output rule selections.
+const (
+	OutputGeneral = "general"
+	OutputAgents  = "agents"
+)
+
+// Section is a config-injected markdown section ("## Title" and Body).
+type Section struct {
+	Title   string `yaml:"title"`
+	Body    string `yaml:"body"`
+	Expires string `yaml:"expires,omitempty"`
+}
+
+// BranchProfile selects extra rules for the branches matching Pattern.
+type BranchProfile struct {
+	// Pattern is a path.Match pattern on the branch name, e.g. "release/*".
+	Pattern string `yaml:"pattern"`
+	// Rules are rule IDs added after the detected rules, e.g. branch/bugfix-only.
+	Rules []string `yaml:"rules"`
+}
+
+// Lint holds org-specific dictionaries and size limits for rules lint.
+type Lint struct {
+	// Terminology maps preferred terms to forbidden variants, e.g. "Nuxt UI": [NuxtUI].
+	Terminology map[string][]string `yaml:"terminology,omitempty"`
+	// Spelling maps misspellings to corrections.
+	Spelling map[string]string `yaml:"spelling,omitempty"`
+	// MaxRuleBytes is the largest rule file allowed (0 for the default).
+	MaxRuleBytes int `yaml:"maxRuleBytes,omitempty"`
+	// MaxTotalBytes limits all rule files and assets together (0 for the default).
+	MaxTotalBytes int `yaml:"maxTotalBytes,omitempty"`
+}
+
+// Stdin is the config path that reads the config from standard input.
+const Stdin = "-"
+
+// stdin is read once, so reloading (watch mode) sees the same config.
+var (
+	stdinOnce sync.Once
+	stdinData []byte
+	stdinErr  error
+)
+
+// ReadFile returns the raw config at path, reading stdin for Stdin.
+func ReadFile(path string) ([]byte, error) {
+	if path != Stdin {
+		return os.ReadFile(path)
+	}
+	stdinOnce.Do(func() {
+		stdinData, stdinErr = io.ReadAll(os.Stdin)
+	})
+	return stdinData, stdinErr
+}
+
+// Load reads the config at path (Stdin reads standard input). A missing file
+// yields an empty config.
+func Load(path string) (*Config, error) {
+	data, err := ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return nil, err
+	}
+	name := path
+	if path == Stdin {
+		name = "from stdin"
+	}
+	return Parse(data, name)
+}
+
+// Parse decodes config YAML; name is used in error messages.
+func Parse(data []byte, name string) (*Config, error) {
+	var c Config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", name, err)
+	}
+	if err := c.checkPaths(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", name, err)
+	}
+	return &c, nil
+}
+
+// checkPaths rejects output and policy paths that are absolute or climb out
+// of the repository: generate writes them and validate reads them, so a
+// config from an untrusted repository must not reach other files.
+func (c *Config) checkPaths() error {
+	for _, o := range c.Outputs {
+		if o.Path != "" && !LocalPath(o.Path) {
+			return fmt.Errorf("output '%s': path '%s' must be relative and inside the repository", o.Name, o.Path)
+		}
+	}
+	if c.Policy != "" && !LocalPath(c.Policy) {
+		return fmt.Errorf("policy '%s' must be relative and inside the repository", c.Policy)
+	}
+	return nil
+}
+
+// LocalPath reports whether path is relative and stays inside the directory
+// it is resolved from (no leading /, no drive letter, no .. escaping it).
+func LocalPath(path string) bool {
+	return filepath.IsLocal(filepath.FromSlash(path))
+}
@@ -0,0 +1,43 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// detectBuildSystem detects Bazel and Nix at the project root. Bazel is the
+// version pinned in .bazelversion, else the marker file; Nix is the marker file.
+func detectBuildSystem(projectRoot string, stack *DetectedStack) error {
+	if !stack.Has(Bazel) {
+		marker := ""
+		for _, name := range []string{"MODULE.bazel", "WORKSPACE.bazel", "WORKSPACE"} {
+			if fileExists(filepath.Join(projectRoot, name)) {
+				marker = name
+				break
+			}
+		}
+		if marker != "" {
+			path := filepath.Join(projectRoot, ".bazelversion")
+			data, err := os.ReadFile(path)
+			if err != nil && !os.IsNotExist(err) {
+				return readError("build system", path, err)
+			}
+			if v := strings.TrimSpace(string(data)); v != "" {
+				stack.accept(Bazel, v, path, "pinned version")
+			} else {
+				stack.accept(Bazel, marker, filepath.Join(projectRoot, marker), markerSource)
+			}
+		}
+	}
+
+	if !stack.Has(Nix) {
+		for _, name := range []string{"flake.nix", "default.nix", "shell.nix"} {
+			if path := filepath.Join(projectRoot, name); fileExists(path) {
+				stack.accept(Nix, name, path, markerSource)
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+package detect
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// CommitConventions describes how a project enforces its commit messages
+// (commitlint) and which release tooling reads them.
+type CommitConventions struct {
+	// Commitlint is the commitlint config file ("package.json" for the
+	// commitlint key); empty when commits are not linted.
+	Commitlint string `json:"commitlint,omitempty"`
+	// Extends are the shared configs the commitlint config extends, e.g.
+	// @commitlint/config-conventional.
+	Extends []string `json:"extends,omitempty"`
+	// Rules are the enabled commitlint rules, with the defaults of known
+	// shared configs filled in. They are empty when the config cannot be read
+	// statically (e.g. JS) and extends no known config.
+	Rules []CommitRule `json:"rules,omitempty"`
+	// Hook is the git hook running commitlint, e.g. "husky commit-msg".
+	Hook string `json:"hook,omitempty"`
+
+	// Release is the release tool generating versions or changelogs from the
+	// commits: semantic-release, standard-version, commit-and-tag-version,
+	// release-please or conventional-changelog.
+	Release string `json:"release,omitempty"`
+	// ReleaseConfig is the config file of the release tool, if any.
+	ReleaseConfig string `json:"release_config,omitempty"`
+	// ChangelogTypes are the commit types shown in the changelog, when the
+	// release config lists them (standard-version types).
+	ChangelogTypes []string `json:"changelog_types,omitempty"`
+}
+
+// CommitRule is one enabled commitlint rule, e.g. type-enum always
+// [feat, fix] (error).
+type CommitRule struct {
+	Name string `json:"name"`
+	// Level is error or warning.
+	Level string `json:"level"`
+	// Never is set when the rule is negated (applicable "never").
+	Never bool     `json:"never,omitempty"`
+	Value []string `json:"value,omitempty"`
+}
+
+// Empty reports whether neither commitlint nor release tooling was found.
+func (c *CommitConventions) Empty() bool {
+	return c == nil || (c.Commitlint == "" && c.Release == "")
+}
+
+// Rule returns the enabled commitlint rule by name, or nil.
+func (c *CommitConventions) Rule(name string) *CommitRule {
+	for i := range c.Rules {
+		if c.Rules[i].Name == name {
+			return &c.Rules[i]
+		}
+	}
+	return nil
+}
+
+// Commitlint config files, in the order commitlint looks for them.
+var commitlintConfigs = []string{".commitlintrc", ".commitlintrc.json", ".commitlintrc.yaml", ".commitlintrc.yml",
+	".commitlintrc.js", ".commitlintrc.cjs", ".commitlintrc.mjs", ".commitlintrc.ts",
+	"commitlint.config.js", "commitlint.config.cjs", "commitlint.config.mjs", "commitlint.config.ts"}
+
+// commitlintSharedConfig matches the shared configs named in a JS config.
+var commitlintSharedConfig = regexp.MustCompile(`['"](@commitlint/config-[a-z-]+)['"]`)
+
+// conventionalTypes are the commit types of the Angular convention, allowed
+// by @commitlint/config-conventional and config-angular.
+var conventionalTypes = []string{"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test"}
+
+// commitlintPresets are the rules of the common shared configs, which the
+// rules of the project config override.
+var commitlintPresets = map[string][]CommitRule{
+	"@commitlint/config-conventional": {
+		{Name: "type-enum", Level: "error", Value: conventionalTypes},
+		{Name: "type-case", Level: "error", Value: []string{"lower-case"}},
+		{Name: "type-empty", Level: "error", Never: true},
+		{Name: "subject-case", Level: "error", Never: true, Value: []string{"sentence-case", "start-case", "pascal-case", "upper-case"}},
+		{Name: "subject-empty", Level: "error", Never: true},
+		{Name: "subject-full-stop", Level: "error", Never: true, Value: []string{"."}},
+		{Name: "header-max-length", Level: "error", Value: []string{"100"}},
+		{Name: "body-max-line-length", Level: "error", Value: []string{"100"}},
+		{Name: "footer-max-line-length", Level: "error", Value: []string{"100"}},
+	},
+	"@commitlint/config-angular": {
+		{Name: "type-enum", Level: "error", Value: conventionalTypes},
+		{Name: "type-case", Level: "error", Value: []string{"lower-case"}},
+		{Name: "type-empty", Level: "error", Never: true},
+		{Name: "subject-case", Level: "error", Never: true, Value: []string{"sentence-case", "start-case", "pascal-case", "upper-case"}},
+		{Name: "subject-empty", Level: "error", Never: true},
+		{Name: "subject-full-stop", Level: "error", Never: true, Value: []string{"."}},
+		{Name: "header-max-length", Level: "error", Value: []string{"72"}},
+	},
+}
+
+// releaseTools are the package.json dependencies of release tools reading
+// commit messages, in order of precedence.
+var releaseTools = []string{"semantic-release", "standard-version", "commit-and-tag-version", "conventional-changelog-cli", "conventional-changelog"}
+
+// releaseConfigs are the config files of each release tool.
+var releaseConfigs = map[string][]string{
+	"semantic-release":       {".releaserc", ".releaserc.json", ".releaserc.yaml", ".releaserc.yml", ".releaserc.js", ".releaserc.cjs", "release.config.js", "release.config.cjs"},
+	"standard-version":       {".versionrc", ".versionrc.json", ".versionrc.js"},
+	"commit-and-tag-version": {".versionrc", ".versionrc.json", ".versionrc.js"},
+	"release-please":         {"release-please-config.json"},
+}
+
+// DetectCommitConventions reads the commitlint config, the git hook running
+// it and the release tooling in projectRoot.
+func DetectCommitConventions(projectRoot string) (*CommitConventions, error) {
+	commits := &CommitConventions{}
+
+	path := filepath.Join(projectRoot, "package.json")
+	data, err := os.ReadFile(path)
+	traceRead(path, err)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, readError("commits", path, err)
+	}
+	// A malformed package.json is reported by the javascript detector
+	var p struct {
+		packageJSON
+		Commitlint map[string]any `json:"commitlint"`
+		Release    map[string]any `json:"release"`
+	}
+	if err == nil {
+		_ = json.Unmarshal(data, &p)
+	}
+
+	if err := detectCommitlint(projectRoot, p.Commitlint, commits); err != nil {
+		return nil, err
+	}
+	if commits.Commitlint != "" {
+		cmds, err := readHookScript(filepath.Join(projectRoot, ".husky", "commit-msg"))
+		if err != nil {
+			return nil, err
+		}
+		for _, cmd := range cmds {
+			if strings.Contains(cmd, "commitlint") {
+				commits.Hook = "husky commit-msg"
+				break
+			}
+		}
+	}
+
+	for _, tool := range releaseTools {
+		_, dep := p.Dependencies[tool]
+		_, devDep := p.DevDependencies[tool]
+		if dep || devDep {
+			commits.Release = strings.TrimSuffix(tool, "-cli")
+			break
+		}
+	}
+	if commits.Release == "" && fileExists(filepath.Join(projectRoot, "release-please-config.json")) {
+		commits.Release = "release-please"
+	}
+	if commits.Release == "semantic-release" && len(p.Release) > 0 {
+		commits.ReleaseConfig = "package.json"
+	}
+	for _, name := range releaseConfigs[commits.Release] {
+		if commits.ReleaseConfig != "" {
+			break
+		}
+		if fileExists(filepath.Join(projectRoot, name)) {
+			commits.ReleaseConfig = name
+		}
+	}
+	if commits.ReleaseConfig == ".versionrc" || commits.ReleaseConfig == ".versionrc.json" {
+		if err := readVersionrcTypes(filepath.Join(projectRoot, commits.ReleaseConfig), commits); err != nil {
+			return nil, err
+		}
+	}
+	return commits, nil
+}
+
+// detectCommitlint reads the commitlint config from the package.json key
+// (pkg), then from the first config file. The extends and rules of JS
+// configs are not evaluated; the shared configs they name are recognized.
+func detectCommitlint(projectRoot string, pkg map[string]any, commits *CommitConventions) error {
+	var config map[string]any
+	if len(pkg) > 0 {
+		commits.Commitlint = "package.json"
+		config = pkg
+	}
+	for _, name := range commitlintConfigs {
+		if commits.Commitlint != "" {
+			break
+		}
+		path := filepath.Join(projectRoot, name)
+		if !fileExists(path) {
+			continue
+		}
+		commits.Commitlint = name
+		data, err := os.ReadFile(path)
+		traceRead(path, err)
+		if err != nil {
+			return readError("commits", path, err)
+		}
+		switch filepath.Ext(name) {
+		case ".js", ".cjs", ".mjs", ".ts":
+			for _, m := range commitlintSharedConfig.FindAllStringSubmatch(string(data), -1) {
+				commits.Extends = appendUnique(commits.Extends, m[1])
+			}
+		default:
+			// JSON is valid YAML, so one parser covers every static config
+			if err := yaml.Unmarshal(data, &config); err != nil {
+				return parseError("commits", path, data, err)
+			}
+		}
+	}
+	if commits.Commitlint == "" {
+		return nil
+	}
+
+	switch extends := config["extends"].(type) {
+	case string:
+		commits.Extends = append(commits.Extends, extends)
+	case []any:
+		for _, e := range extends {
+			if s, ok := e.(string); ok {
+				commits.Extends = appendUnique(commits.Extends, s)
+			}
+		}
+	}
+	for _, e := range commits.Extends {
+		for _, rule := range commitlintPresets[e] {
+			setCommitRule(commits, rule)
+		}
+	}
+	rules, _ := config["rules"].(map[string]any)
+	for _, name := range sortedAnyKeys(rules) {
+		rule, ok := commitlintRule(name, rules[name])
+		if !ok {
+			continue
+		}
+		if rule.Level == "" {
+			// Level 0 disables the rule of a shared config
+			removeCommitRule(commits, name)
+			continue
+		}
+		setCommitRule(commits, rule)
+	}
+	return nil
+}
+
+// commitlintRule parses a rule of the form [level, applicable, value]; a
+// disabled rule has no Level.
+func commitlintRule(name string, raw any) (CommitRule, bool) {
+	parts, ok := raw.([]any)
+	if !ok || len(parts) == 0 {
+		return CommitRule{}, false
+	}
+	rule := CommitRule{Name: name}
+	switch formatValue(parts[0]) {
+	case "0":
+	case "1":
+		rule.Level = "warning"
+	case "2":
+		rule.Level = "error"
+	default:
+		return CommitRule{}, false
+	}
+	if len(parts) > 1 {
+		rule.Never = parts[1] == "never"
+	}
+	if len(parts) > 2 {
+		switch v := parts[2].(type) {
+		case []any:
+			for _, item := range v {
+				rule.Value = append(rule.Value, formatValue(item))
+			}
+		case nil:
+		default:
+			rule.Value = []string{formatValue(v)}
+		}
+	}
+	return rule, true
+}
+
+func setCommitRule(commits *CommitConventions, rule CommitRule) {
+	if existing := commits.Rule(rule.Name); existing != nil {
+		*existing = rule
+		return
+	}
+	commits.Rules = append(commits.Rules, rule)
+}
+
+func removeCommitRule(commits *CommitConventions, name string) {
+	for i, r := range commits.Rules {
+		if r.Name == name {
+			commits.Rules = append(commits.Rules[:i], commits.Rules[i+1:]...)
+			return
+		}
+	}
+}
+
+// readVersionrcTypes reads the commit types standard-version shows in the
+// changelog (types not marked hidden).
+func readVersionrcTypes(path string, commits *CommitConventions) error {
+	data, err := os.ReadFile(path)
+	traceRead(path, err)
+	if err != nil {
+		return readError("commits", path, err)
+	}
+	var config struct {
+		Types []struct {
+			Type   string `json:"type"`
+			Hidden bool   `json:"hidden"`
+		} `json:"types"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return parseError("commits", path, data, err)
+	}
+	for _, t := range config.Types {
+		if t.Type != "" && !t.Hidden {
+			commits.ChangelogTypes = appendUnique(commits.ChangelogTypes, t.Type)
+		}
+	}
+	return nil
+}
+
+// appendUnique appends s to list unless it holds it already.
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -17,38 +17,25 @@ func detectFromComposer(projectRoot string, stack *DetectedStack) error {
 	path := filepath.Join(projectRoot, "composer.json")
 
 	data, err := os.ReadFile(path)
+	traceRead(path, err)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
 		}
-		return err
+		return readError("composer", path, err)
 	}
 
 	var c composerJSON
 	if err := json.Unmarshal(data, &c); err != nil {
-		return err
+		return parseError("composer", path, data, err)
 	}
 
-	// Detect PHP
-	if stack.PHP == "" && c.Config.Platform != nil {
-		if php, ok := c.Config.Platform["php"]; ok {
-			stack.PHP = php
-		}
-	}
-
-	// Detect PHP
-	if stack.PHP == "" {
-		if php, ok := c.Require["php"]; ok {
-			stack.PHP = php
-		}
-	}
+	// Detect PHP (the platform override wins over the requirement)
+	stack.accept(PHP, c.Config.Platform["php"], path, `config.platform["php"]`)
+	stack.accept(PHP, c.Require["php"], path, `require["php"]`)
 
 	// Detect Laravel
-	if stack.Laravel == "" {
-		if v, ok := c.Require["laravel/framework"]; ok {
-			stack.Laravel = v
-		}
-	}
+	stack.accept(Laravel, c.Require["laravel/framework"], path, `require["laravel/framework"]`)
 
 	return nil
 }
@@ -57,11 +44,12 @@ func detectFromComposerLock(projectRoot string, stack *DetectedStack) error {
 	path := filepath.Join(projectRoot, "composer.lock")
 
 	data, err := os.ReadFile(path)
+	traceRead(path, err)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
 		}
-		return err
+		return readError("composer", path, err)
 	}
 
 	var lock struct {
@@ -71,15 +59,13 @@ func detectFromComposerLock(projectRoot string, stack *DetectedStack) error {
 		} `json:"packages"`
 	}
 	if err := json.Unmarshal(data, &lock); err != nil {
-		return err
+		return parseError("composer", path, data, err)
 	}
 
-	if stack.Laravel == "" {
-		for _, pkg := range lock.Packages {
-			if pkg.Name == "laravel/framework" {
-				stack.Laravel = pkg.Version
-				break
-			}
+	for _, pkg := range lock.Packages {
+		if pkg.Name == "laravel/framework" {
+			stack.accept(Laravel, pkg.Version, path, `packages["laravel/framework"]`)
+			break
 		}
 	}
 	return nil
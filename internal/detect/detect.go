@@ -1,33 +1,72 @@
 package detect
 
 import (
+	"fmt"
 	"io/fs"
 	"path/filepath"
 	"strings"
+
+	"github.com/cego/ai-instructions/internal/aiignore"
+	"github.com/cego/ai-instructions/internal/warnings"
 )
 
 // DetectStack is used to detect the stack of a project (recursively)
 func DetectStack(projectRoot string) (*DetectedStack, error) {
-	stack := &DetectedStack{}
+	stack := &DetectedStack{root: projectRoot}
 
-	// First: try the root, so root gets to "win"
-	if err := detectFromComposer(projectRoot, stack); err != nil {
+	// First: try the root, so root gets to "win". Malformed manifests are
+	// skipped with a warning; other errors (e.g. unreadable files) abort.
+	for _, detectFn := range []func(string, *DetectedStack) error{
+		detectToolVersions,
+		detectDevcontainer,
+		detectFromComposer,
+		detectFromPackageJson,
+		detectFromPackageLockJson,
+		detectFromGoWork,
+		detectFromGoMod,
+		detectFromPyproject,
+		detectFromPipfile,
+		detectFromRequirements,
+		detectFromGemfile,
+		detectFromGemfileLock,
+		detectTypeScript,
+		detectLaravelRuntime,
+		detectPackageManagers,
+		detectBuildSystem,
+	} {
+		if err := skipMalformed(detectFn(projectRoot, stack)); err != nil {
+			return nil, err
+		}
+	}
+	if hooks, err := DetectGitHooks(projectRoot); err != nil {
 		return nil, err
+	} else if !hooks.Empty() {
+		stack.Hooks = hooks
 	}
-	if err := detectFromPackageJson(projectRoot, stack); err != nil {
+	i18n, err := DetectI18n(projectRoot)
+	if err != nil {
 		return nil, err
 	}
-	if err := detectFromPackageLockJson(projectRoot, stack); err != nil {
+	stack.I18n = i18n
+	if budgets, err := DetectPerformanceBudgets(projectRoot); skipMalformed(err) != nil {
 		return nil, err
+	} else if !budgets.Empty() {
+		stack.Performance = budgets
+	}
+	if commits, err := DetectCommitConventions(projectRoot); skipMalformed(err) != nil {
+		return nil, err
+	} else if !commits.Empty() {
+		stack.Commits = commits
 	}
 
-	ignoredDirs := map[string]bool{
-		"node_modules": true,
-		"composer":     true,
-		"vendor":       true,
+	ignore, err := aiignore.Load(projectRoot)
+	if err != nil {
+		return nil, err
 	}
 
-	err := filepath.WalkDir(projectRoot, func(path string, d fs.DirEntry, err error) error {
+	languages := languageCounter{}
+	var dockerfiles []string
+	err = filepath.WalkDir(projectRoot, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			// if there's a random permission error somewhere, just skip it
 			return nil
@@ -39,30 +78,50 @@ func DetectStack(projectRoot string) (*DetectedStack, error) {
 		}
 
 		if d.IsDir() {
-			name := d.Name()
-
-			// skip dot-folders: .git, .idea, .vscode, ...
-			if strings.HasPrefix(name, ".") {
+			if skipDir(d.Name()) {
+				traceSkip(path, "ignored directory")
 				return fs.SkipDir
 			}
-
-			// skip specific folders
-			if ignoredDirs[name] {
+			if restricted(ignore, projectRoot, path, true) {
 				return fs.SkipDir
 			}
-
 			return nil
 		}
+		if restricted(ignore, projectRoot, path, false) {
+			return nil
+		}
+
+		languages.add(d)
+		if isDockerfile(d.Name()) {
+			dockerfiles = append(dockerfiles, path)
+		}
 
+		var detectErr error
 		switch d.Name() {
 		case "composer.json":
-			_ = detectFromComposer(filepath.Dir(path), stack)
+			detectErr = detectFromComposer(filepath.Dir(path), stack)
 		case "composer.lock":
-			_ = detectFromComposerLock(filepath.Dir(path), stack)
+			detectErr = detectFromComposerLock(filepath.Dir(path), stack)
 		case "package.json":
-			_ = detectFromPackageJson(filepath.Dir(path), stack)
+			detectErr = detectFromPackageJson(filepath.Dir(path), stack)
 		case "package-lock.json":
-			_ = detectFromPackageLockJson(filepath.Dir(path), stack)
+			detectErr = detectFromPackageLockJson(filepath.Dir(path), stack)
+		case "go.mod":
+			detectErr = detectFromGoMod(filepath.Dir(path), stack)
+		case "pyproject.toml":
+			detectErr = detectFromPyproject(filepath.Dir(path), stack)
+		case "Pipfile":
+			detectErr = detectFromPipfile(filepath.Dir(path), stack)
+		case "requirements.txt":
+			detectErr = detectFromRequirements(filepath.Dir(path), stack)
+		case "Gemfile":
+			detectErr = detectFromGemfile(filepath.Dir(path), stack)
+		case "Gemfile.lock":
+			detectErr = detectFromGemfileLock(filepath.Dir(path), stack)
+		}
+		if err := skipMalformed(detectErr); err != nil {
+			warnings.Add("detect", "skipped unreadable %v", err)
+			traceSkip(path, fmt.Sprintf("unreadable: %v", err))
 		}
 
 		return nil
@@ -71,6 +130,42 @@ func DetectStack(projectRoot string) (*DetectedStack, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Last, so that any manifest wins over the base images
+	if err := detectFromDockerfiles(dockerfiles, stack); err != nil {
+		return nil, err
+	}
+	stack.Languages = languages.shares()
 
 	return stack, nil
 }
+
+var ignoredDirs = map[string]bool{
+	"node_modules": true,
+	"composer":     true,
+	"vendor":       true,
+}
+
+// skipDir reports whether the walk should not descend into a directory.
+func skipDir(name string) bool {
+	// skip dot-folders: .git, .idea, .vscode, ...
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+
+	// skip specific folders
+	return ignoredDirs[name]
+}
+
+// restricted reports whether path is listed in the .aiignore of projectRoot,
+// so detection must not read it.
+func restricted(ignore *aiignore.List, projectRoot, path string, isDir bool) bool {
+	if ignore.Empty() {
+		return false
+	}
+	rel, err := filepath.Rel(projectRoot, path)
+	if err != nil || !ignore.Match(rel, isDir) {
+		return false
+	}
+	traceSkip(path, "listed in "+aiignore.File)
+	return true
+}
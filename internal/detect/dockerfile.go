@@ -0,0 +1,128 @@
+package detect
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// dockerImages maps official base images to the runtime their tag versions.
+var dockerImages = map[string]string{
+	"php":    PHP,
+	"node":   Node,
+	"python": Python,
+	"ruby":   Ruby,
+}
+
+var (
+	// dockerArgPattern matches $VAR and ${VAR} references in FROM lines.
+	dockerArgPattern = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)\}?`)
+	// leadingVersion matches the version at the start of a tag or tool
+	// version, e.g. 8.3 in 8.3-fpm.
+	leadingVersion = regexp.MustCompile(`^\d+(\.\d+){0,2}`)
+)
+
+// isDockerfile reports whether name is a Dockerfile (Dockerfile,
+// Dockerfile.prod or app.Dockerfile).
+func isDockerfile(name string) bool {
+	return name == "Dockerfile" || strings.HasPrefix(name, "Dockerfile.") || strings.HasSuffix(name, ".Dockerfile")
+}
+
+// dockerfilesIn lists the Dockerfiles directly in dir.
+func dockerfilesIn(dir string) []string {
+	entries, _ := os.ReadDir(dir)
+	var out []string
+	for _, e := range entries {
+		if !e.IsDir() && isDockerfile(e.Name()) {
+			out = append(out, filepath.Join(dir, e.Name()))
+		}
+	}
+	return out
+}
+
+// detectFromDockerfiles infers runtime versions from the FROM images of the
+// given Dockerfiles (e.g. php:8.3-fpm), shallowest first. It runs after the
+// manifests, so it only fills in versions no manifest declares, and marks
+// them as low confidence: the image may be a build stage only.
+func detectFromDockerfiles(paths []string, stack *DetectedStack) error {
+	sort.SliceStable(paths, func(i, j int) bool {
+		di, dj := strings.Count(filepath.ToSlash(paths[i]), "/"), strings.Count(filepath.ToSlash(paths[j]), "/")
+		if di != dj {
+			return di < dj
+		}
+		return paths[i] < paths[j]
+	})
+	for _, p := range paths {
+		images, err := dockerfileImages(p)
+		traceRead(p, err)
+		if err != nil {
+			return readError("dockerfile", p, err)
+		}
+		for _, image := range images {
+			name, tag := splitDockerImage(image)
+			tech, ok := dockerImages[name]
+			if !ok {
+				continue
+			}
+			stack.infer(tech, leadingVersion.FindString(tag), p, "FROM "+image)
+		}
+	}
+	return nil
+}
+
+// dockerfileImages returns the images of the FROM instructions in a
+// Dockerfile, with ARG defaults declared before them substituted.
+func dockerfileImages(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Only ARGs before the first FROM are in scope of FROM lines
+	args := map[string]string{}
+	inStage := false
+	var images []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "ARG":
+			if name, value, ok := strings.Cut(fields[1], "="); ok && !inStage {
+				args[name] = strings.Trim(value, `"'`)
+			}
+		case "FROM":
+			inStage = true
+			image := ""
+			for _, field := range fields[1:] {
+				if !strings.HasPrefix(field, "--") { // e.g. --platform=linux/amd64
+					image = field
+					break
+				}
+			}
+			image = dockerArgPattern.ReplaceAllStringFunc(image, func(ref string) string {
+				return args[dockerArgPattern.FindStringSubmatch(ref)[1]]
+			})
+			if image != "" {
+				images = append(images, image)
+			}
+		}
+	}
+	return images, scanner.Err()
+}
+
+// splitDockerImage returns the repository name without registry or namespace
+// and the tag, e.g. "php" and "8.3-fpm" for docker.io/library/php:8.3-fpm.
+func splitDockerImage(image string) (name, tag string) {
+	image, _, _ = strings.Cut(image, "@") // digest
+	ref := path.Base(image)
+	name, tag, _ = strings.Cut(ref, ":")
+	return strings.ToLower(name), tag
+}
@@ -0,0 +1,76 @@
+package detect
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cego/ai-instructions/internal/warnings"
+)
+
+// DetectError is a detector failure on a file: the file could not be read or,
+// when Malformed, not parsed. Malformed files are skipped with a warning.
+type DetectError struct {
+	Detector string // e.g. "composer", "typescript"
+	Path     string
+	// Line is the line of a parse error (0 when unknown).
+	Line      int
+	Malformed bool
+	Err       error
+}
+
+func (e *DetectError) Error() string {
+	return fmt.Sprintf("%s: %s detector: %v", e.location(), e.Detector, e.Err)
+}
+
+func (e *DetectError) Unwrap() error {
+	return e.Err
+}
+
+// location is the path, with the line when known (path:line).
+func (e *DetectError) location() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d", e.Path, e.Line)
+	}
+	return e.Path
+}
+
+// readError wraps a failure to read path.
+func readError(detector, path string, err error) error {
+	return &DetectError{Detector: detector, Path: path, Err: err}
+}
+
+// parseError wraps a failure to parse data (read from path), locating JSON
+// syntax and type errors by line. YAML errors name the line themselves.
+func parseError(detector, path string, data []byte, err error) error {
+	e := &DetectError{Detector: detector, Path: path, Malformed: true, Err: err}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		e.Line = lineAt(data, syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		e.Line = lineAt(data, typeErr.Offset)
+	}
+	return e
+}
+
+// lineAt returns the 1-based line of a byte offset in data.
+func lineAt(data []byte, offset int64) int {
+	offset = min(max(offset, 0), int64(len(data)))
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// skipMalformed records a warning for a malformed file and returns nil, so
+// detection continues without it; any other error is returned as is.
+func skipMalformed(err error) error {
+	var de *DetectError
+	if !errors.As(err, &de) || !de.Malformed {
+		return err
+	}
+	// Detectors reading the same file report the same warning, recorded once
+	warnings.Add("detect", "skipped malformed %s: %v", de.location(), de.Err)
+	traceSkip(de.Path, fmt.Sprintf("malformed (%s detector): %v", de.Detector, de.Err))
+	return nil
+}
@@ -0,0 +1,15 @@
+package detect
+
+// frontendFramework is a frontend UI framework detected from package.json.
+type frontendFramework struct {
+	Name    string
+	Package string
+}
+
+// frontendFrameworks are the supported frontends, in detection order.
+var frontendFrameworks = []frontendFramework{
+	{Name: Vue, Package: "vue"},
+	{Name: React, Package: "react"},
+	{Name: Svelte, Package: "svelte"},
+	{Name: Angular, Package: "@angular/core"},
+}
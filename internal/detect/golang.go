@@ -0,0 +1,147 @@
+package detect
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// goModFile is the subset of go.mod we care about.
+type goModFile struct {
+	Module   string
+	Go       string
+	Requires []string // direct (non-indirect) requirements
+}
+
+// goWorkFile is the subset of go.work we care about.
+type goWorkFile struct {
+	Go   string
+	Uses []string
+}
+
+func detectFromGoMod(projectRoot string, stack *DetectedStack) error {
+	path := filepath.Join(projectRoot, "go.mod")
+	mod, err := readGoMod(path)
+	if err != nil {
+		return readError("go", path, err)
+	}
+	if mod == nil {
+		return nil
+	}
+	stack.accept(Go, mod.Go, path, "go directive")
+	return nil
+}
+
+func detectFromGoWork(projectRoot string, stack *DetectedStack) error {
+	path := filepath.Join(projectRoot, "go.work")
+	work, err := readGoWork(path)
+	if err != nil {
+		return readError("go", path, err)
+	}
+	if work == nil {
+		return nil
+	}
+	stack.accept(Go, work.Go, path, "go directive")
+	return nil
+}
+
+// readGoMod parses go.mod (nil when the file does not exist).
+func readGoMod(path string) (*goModFile, error) {
+	lines, err := readDirectiveLines(path)
+	if err != nil || lines == nil {
+		return nil, err
+	}
+
+	mod := &goModFile{}
+	for _, d := range lines {
+		switch d.verb {
+		case "module":
+			mod.Module = strings.Trim(d.args, `"`)
+		case "go":
+			mod.Go = d.args
+		case "require":
+			if d.indirect {
+				continue
+			}
+			if fields := strings.Fields(d.args); len(fields) > 0 {
+				mod.Requires = append(mod.Requires, fields[0])
+			}
+		}
+	}
+	return mod, nil
+}
+
+// readGoWork parses go.work (nil when the file does not exist).
+func readGoWork(path string) (*goWorkFile, error) {
+	lines, err := readDirectiveLines(path)
+	if err != nil || lines == nil {
+		return nil, err
+	}
+
+	work := &goWorkFile{}
+	for _, d := range lines {
+		switch d.verb {
+		case "go":
+			work.Go = d.args
+		case "use":
+			if fields := strings.Fields(d.args); len(fields) > 0 {
+				work.Uses = append(work.Uses, strings.Trim(fields[0], `"`))
+			}
+		}
+	}
+	return work, nil
+}
+
+type directive struct {
+	verb     string
+	args     string
+	indirect bool
+}
+
+// readDirectiveLines flattens go.mod/go.work syntax (including blocks like
+// "require ( ... )") into one directive per line.
+func readDirectiveLines(path string) ([]directive, error) {
+	f, err := os.Open(path)
+	traceRead(path, err)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	out := []directive{}
+	block := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		indirect := strings.Contains(line, "// indirect")
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if block != "" {
+			if line == ")" {
+				block = ""
+				continue
+			}
+			out = append(out, directive{verb: block, args: line, indirect: indirect})
+			continue
+		}
+
+		verb, args, _ := strings.Cut(line, " ")
+		args = strings.TrimSpace(args)
+		if args == "(" {
+			block = verb
+			continue
+		}
+		out = append(out, directive{verb: verb, args: args, indirect: indirect})
+	}
+	return out, scanner.Err()
+}
@@ -0,0 +1,158 @@
+package detect
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// GitHooks describes the git hook tooling configured in a project.
+type GitHooks struct {
+	// Husky maps hook names (pre-commit, commit-msg, ...) to the commands in .husky/<hook>.
+	Husky map[string][]string `json:"husky,omitempty"`
+	// LintStaged maps file globs to the commands lint-staged runs on staged files.
+	LintStaged map[string][]string `json:"lint_staged,omitempty"`
+	// LintStagedConfig is the lint-staged config file, when it cannot be read statically (e.g. JS).
+	LintStagedConfig string `json:"lint_staged_config,omitempty"`
+	// Lefthook and PreCommit report other hook managers by their config file.
+	Lefthook  string `json:"lefthook,omitempty"`
+	PreCommit string `json:"pre_commit,omitempty"`
+}
+
+// Empty reports whether no hook tooling was found.
+func (h *GitHooks) Empty() bool {
+	return h == nil || (len(h.Husky) == 0 && len(h.LintStaged) == 0 && h.LintStagedConfig == "" &&
+		h.Lefthook == "" && h.PreCommit == "")
+}
+
+// Hook names husky projects commonly define.
+var huskyHooks = []string{"pre-commit", "commit-msg", "pre-push"}
+
+// DetectGitHooks reads husky, lint-staged, lefthook and pre-commit configuration in projectRoot.
+func DetectGitHooks(projectRoot string) (*GitHooks, error) {
+	hooks := &GitHooks{}
+
+	for _, name := range huskyHooks {
+		cmds, err := readHookScript(filepath.Join(projectRoot, ".husky", name))
+		if err != nil {
+			return nil, err
+		}
+		if len(cmds) > 0 {
+			if hooks.Husky == nil {
+				hooks.Husky = map[string][]string{}
+			}
+			hooks.Husky[name] = cmds
+		}
+	}
+
+	if err := skipMalformed(detectLintStaged(projectRoot, hooks)); err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{"lefthook.yml", "lefthook.yaml", ".lefthook.yml"} {
+		if fileExists(filepath.Join(projectRoot, name)) {
+			hooks.Lefthook = name
+			break
+		}
+	}
+	if fileExists(filepath.Join(projectRoot, ".pre-commit-config.yaml")) {
+		hooks.PreCommit = ".pre-commit-config.yaml"
+	}
+
+	return hooks, nil
+}
+
+// readHookScript returns the commands of a hook script, without comments and husky boilerplate.
+func readHookScript(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, readError("git hooks", path, err)
+	}
+	defer f.Close()
+
+	var cmds []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.Contains(line, "husky.sh") {
+			continue
+		}
+		cmds = append(cmds, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, readError("git hooks", path, err)
+	}
+	return cmds, nil
+}
+
+// detectLintStaged reads lint-staged config from package.json or a .lintstagedrc file.
+func detectLintStaged(projectRoot string, hooks *GitHooks) error {
+	path := filepath.Join(projectRoot, "package.json")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return readError("git hooks", path, err)
+	}
+	if err == nil {
+		var p struct {
+			LintStaged map[string]any `json:"lint-staged"`
+		}
+		if err := json.Unmarshal(data, &p); err != nil {
+			return parseError("git hooks", path, data, err)
+		}
+		if len(p.LintStaged) > 0 {
+			hooks.LintStaged = lintStagedTasks(p.LintStaged)
+			return nil
+		}
+	}
+
+	// JSON is valid YAML, so one parser covers .lintstagedrc, .json and .yaml
+	for _, name := range []string{".lintstagedrc", ".lintstagedrc.json", ".lintstagedrc.yaml", ".lintstagedrc.yml"} {
+		path := filepath.Join(projectRoot, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return readError("git hooks", path, err)
+		}
+		var raw map[string]any
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return parseError("git hooks", path, data, err)
+		}
+		hooks.LintStaged = lintStagedTasks(raw)
+		return nil
+	}
+
+	for _, name := range []string{"lint-staged.config.js", "lint-staged.config.mjs", "lint-staged.config.cjs", ".lintstagedrc.js", ".lintstagedrc.mjs", ".lintstagedrc.cjs"} {
+		if fileExists(filepath.Join(projectRoot, name)) {
+			hooks.LintStagedConfig = name
+			return nil
+		}
+	}
+	return nil
+}
+
+// lintStagedTasks normalizes lint-staged tasks (a command or a list of commands per glob).
+func lintStagedTasks(raw map[string]any) map[string][]string {
+	tasks := map[string][]string{}
+	for glob, v := range raw {
+		switch v := v.(type) {
+		case string:
+			tasks[glob] = []string{v}
+		case []any:
+			for _, c := range v {
+				if s, ok := c.(string); ok {
+					tasks[glob] = append(tasks[glob], s)
+				}
+			}
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,136 @@
+package detect
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// I18n describes the localization setup of a project.
+type I18n struct {
+	// Libraries are the i18n libraries in use: vue-i18n, @nuxtjs/i18n and
+	// laravel (translation files in a lang directory).
+	Libraries []string `json:"libraries"`
+	// Dirs are the directories holding translation files, relative to the
+	// project root.
+	Dirs []string `json:"dirs,omitempty"`
+	// Locales are the locales with translation files, sorted.
+	Locales []string `json:"locales,omitempty"`
+}
+
+// i18nPackages are the package.json dependencies of JavaScript i18n libraries.
+var i18nPackages = []string{"vue-i18n", "@nuxtjs/i18n"}
+
+// laravelLangDirs hold Laravel translations: lang/ since Laravel 9,
+// resources/lang/ before.
+var laravelLangDirs = []string{"lang", "resources/lang"}
+
+// jsLocaleDirs are the conventional locale message directories of vue-i18n
+// and @nuxtjs/i18n projects.
+var jsLocaleDirs = []string{"locales", "i18n/locales", "src/locales", "src/i18n/locales", "src/lang"}
+
+// localePattern matches locale codes such as en, da, en-US, pt_BR and zh-Hans.
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}([-_][A-Za-z0-9]{2,4})?$`)
+
+// DetectI18n reads the i18n libraries from package.json and the Laravel and
+// JavaScript translation directories in projectRoot. It returns nil when the
+// project is not localized.
+func DetectI18n(projectRoot string) (*I18n, error) {
+	i18n := &I18n{}
+	locales := map[string]bool{}
+
+	path := filepath.Join(projectRoot, "package.json")
+	data, err := os.ReadFile(path)
+	traceRead(path, err)
+	switch {
+	case err == nil:
+		// A malformed package.json is reported by the javascript detector
+		var p packageJSON
+		_ = json.Unmarshal(data, &p)
+		for _, name := range i18nPackages {
+			_, dep := p.Dependencies[name]
+			_, devDep := p.DevDependencies[name]
+			if dep || devDep {
+				i18n.Libraries = append(i18n.Libraries, name)
+			}
+		}
+	case !os.IsNotExist(err):
+		return nil, readError("i18n", path, err)
+	}
+
+	if fileExists(filepath.Join(projectRoot, "composer.json")) {
+		laravel := false
+		for _, dir := range laravelLangDirs {
+			found, err := readLocales(projectRoot, dir, true, locales)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				i18n.Dirs = append(i18n.Dirs, dir)
+				laravel = true
+			}
+		}
+		if laravel {
+			i18n.Libraries = append(i18n.Libraries, "laravel")
+		}
+	}
+	if len(i18n.Libraries) > 0 {
+		for _, dir := range jsLocaleDirs {
+			found, err := readLocales(projectRoot, dir, false, locales)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				i18n.Dirs = append(i18n.Dirs, dir)
+			}
+		}
+	}
+
+	if len(i18n.Libraries) == 0 {
+		return nil, nil
+	}
+	for locale := range locales {
+		i18n.Locales = append(i18n.Locales, locale)
+	}
+	sort.Strings(i18n.Locales)
+	return i18n, nil
+}
+
+// readLocales adds the locales of the translation files in dir: files named
+// by locale (en.json, da.yaml, en-US.ts) and, when subdirs is set, Laravel's
+// per-locale directories (lang/en/*.php). It reports whether dir holds any.
+func readLocales(projectRoot, dir string, subdirs bool, locales map[string]bool) (bool, error) {
+	path := filepath.Join(projectRoot, filepath.FromSlash(dir))
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return false, nil
+	}
+	entries, err := os.ReadDir(path)
+	traceRead(path, err)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, readError("i18n", path, err)
+	}
+
+	found := false
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			if !subdirs || !localePattern.MatchString(name) {
+				continue
+			}
+		} else {
+			name = strings.TrimSuffix(name, filepath.Ext(name))
+			if !localePattern.MatchString(name) {
+				continue
+			}
+		}
+		locales[strings.ReplaceAll(name, "_", "-")] = true
+		found = true
+	}
+	return found, nil
+}
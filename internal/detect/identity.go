@@ -0,0 +1,122 @@
+package detect
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/gitmeta"
+)
+
+// ProjectIdentity is the basic project metadata read from git and the manifests.
+type ProjectIdentity struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Repository is the origin URL, normalized to https without credentials.
+	Repository string `json:"repository,omitempty"`
+	// Branch is the primary branch the origin HEAD points to.
+	Branch  string `json:"branch,omitempty"`
+	License string `json:"license,omitempty"`
+}
+
+// Empty reports whether nothing was found.
+func (p *ProjectIdentity) Empty() bool {
+	return p == nil || *p == ProjectIdentity{}
+}
+
+// Identity manifests, in order of precedence for name, description and license.
+var identityManifests = []string{"composer.json", "package.json"}
+
+// licenseFiles are reported when no manifest declares a license.
+var licenseFiles = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "COPYING"}
+
+// DetectIdentity reads the project name, description and license from
+// composer.json / package.json (falling back to the go.mod module path) and
+// the origin URL and primary branch from the git metadata of projectRoot.
+func DetectIdentity(projectRoot string) (*ProjectIdentity, error) {
+	id := &ProjectIdentity{}
+
+	for _, name := range identityManifests {
+		path := filepath.Join(projectRoot, name)
+		data, err := os.ReadFile(path)
+		traceRead(path, err)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, readError("identity", path, err)
+		}
+		var m struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			License     any    `json:"license"`
+		}
+		if err := json.Unmarshal(data, &m); err != nil {
+			// A malformed manifest only loses its identity fields
+			skipMalformed(parseError("identity", path, data, err))
+			continue
+		}
+		accept(&id.Name, "Name", m.Name, path, "name")
+		accept(&id.Description, "Description", m.Description, path, "description")
+		accept(&id.License, "License", manifestLicense(m.License), path, "license")
+	}
+
+	if id.Name == "" {
+		path := filepath.Join(projectRoot, "go.mod")
+		mod, err := readGoMod(path)
+		if err != nil {
+			return nil, readError("identity", path, err)
+		}
+		if mod != nil {
+			accept(&id.Name, "Name", mod.Module, path, "module")
+		}
+	}
+
+	if id.License == "" {
+		for _, name := range licenseFiles {
+			if fileExists(filepath.Join(projectRoot, name)) {
+				accept(&id.License, "License", "see "+name, name, "file")
+				break
+			}
+		}
+	}
+
+	repo, err := gitmeta.Open(projectRoot)
+	if err != nil || repo == nil {
+		return id, err
+	}
+	origin, err := repo.OriginURL()
+	if err != nil {
+		return nil, err
+	}
+	traceRead(repo.ConfigPath(), nil)
+	accept(&id.Repository, "Repository", gitmeta.NormalizeRemoteURL(origin), repo.ConfigPath(), `remote "origin".url`)
+
+	branch, err := repo.DefaultBranch()
+	traceRead(repo.OriginHeadPath(), err)
+	if err != nil {
+		return nil, err
+	}
+	accept(&id.Branch, "Branch", branch, repo.OriginHeadPath(), "symbolic ref")
+
+	return id, nil
+}
+
+// manifestLicense returns an SPDX license, or licenses joined with " OR "
+// (composer.json allows a list).
+func manifestLicense(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []any:
+		var out []string
+		for _, l := range v {
+			if s, ok := l.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return strings.Join(out, " OR ")
+	}
+	return ""
+}
@@ -2,6 +2,7 @@ package detect
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 )
@@ -15,41 +16,60 @@ func detectFromPackageJson(projectRoot string, stack *DetectedStack) error {
 	path := filepath.Join(projectRoot, "package.json")
 
 	data, err := os.ReadFile(path)
+	traceRead(path, err)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
 		}
-		return err
+		return readError("javascript", path, err)
 	}
 
 	var p packageJSON
 	if err := json.Unmarshal(data, &p); err != nil {
-		return err
+		return parseError("javascript", path, data, err)
 	}
+	stack.accept(JavaScript, "package.json", path, markerSource)
 
-	get := func(name string) (string, bool) {
+	// version returns a package's version and where it was found
+	// (dependencies before devDependencies)
+	version := func(name string) (string, string) {
 		if v, ok := p.Dependencies[name]; ok {
-			return v, true
+			return v, fmt.Sprintf("dependencies[%q]", name)
 		}
 		if v, ok := p.DevDependencies[name]; ok {
-			return v, true
+			return v, fmt.Sprintf("devDependencies[%q]", name)
 		}
-		return "", false
+		return "", ""
 	}
-
-	if v, ok := get("nuxt"); ok && stack.Nuxt == "" {
-		stack.Nuxt = v
+	// dependency offers a package's version for a technology
+	dependency := func(tech, name string) {
+		v, source := version(name)
+		stack.accept(tech, v, path, source)
 	}
-	if v, ok := get("vue"); ok && stack.Vue == "" {
-		stack.Vue = v
+
+	dependency(Nuxt, "nuxt")
+	for _, f := range frontendFrameworks {
+		dependency(f.Name, f.Package)
 	}
-	if v, ok := get("@nuxt/ui"); ok && stack.NuxtUI == "" {
-		stack.NuxtUI = v
+	dependency(NuxtUI, "@nuxt/ui")
+	dependency(Pinia, "pinia")
+	dependency(Pinia, "@pinia/nuxt")
+	dependency(Vuex, "vuex")
+	// Nuxt bundles its own router; only an explicit dependency counts
+	dependency(VueRouter, "vue-router")
+	for _, name := range inertiaPackages {
+		dependency(Inertia, name)
 	}
 
 	return nil
 }
 
+// inertiaPackages are the Inertia client adapters, current and legacy.
+var inertiaPackages = []string{
+	"@inertiajs/vue3", "@inertiajs/react", "@inertiajs/svelte",
+	"@inertiajs/inertia-vue3", "@inertiajs/inertia-vue", "@inertiajs/inertia-react", "@inertiajs/inertia",
+}
+
 type lockFile struct {
 	Dependencies map[string]struct {
 		Version string `json:"version"`
@@ -64,29 +84,25 @@ type lockFile struct {
 func detectFromPackageLockJson(projectRoot string, stack *DetectedStack) error {
 	path := filepath.Join(projectRoot, "package-lock.json")
 	data, err := os.ReadFile(path)
+	traceRead(path, err)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
 		}
-		return err
+		return readError("javascript", path, err)
 	}
 
 	var lock lockFile
 	if err := json.Unmarshal(data, &lock); err != nil {
-		return err
+		return parseError("javascript", path, data, err)
 	}
 
-	if stack.NuxtUI == "" {
-		if dep, ok := lock.Dependencies["@nuxt/ui"]; ok && dep.Version != "" {
-			stack.NuxtUI = dep.Version
-			return nil
-		}
-
-		if pkg, ok := lock.Packages["node_modules/@nuxt/ui"]; ok && pkg.Version != "" {
-			stack.NuxtUI = pkg.Version
-			return nil
-		}
+	if dep, ok := lock.Dependencies["@nuxt/ui"]; ok && dep.Version != "" {
+		stack.accept(NuxtUI, dep.Version, path, `dependencies["@nuxt/ui"]`)
+		return nil
+	}
+	if pkg, ok := lock.Packages["node_modules/@nuxt/ui"]; ok && pkg.Version != "" {
+		stack.accept(NuxtUI, pkg.Version, path, `packages["node_modules/@nuxt/ui"]`)
 	}
-
 	return nil
 }
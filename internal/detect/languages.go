@@ -0,0 +1,87 @@
+package detect
+
+import (
+	"io/fs"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LanguageShare is the share of one language in the source files, by bytes.
+type LanguageShare struct {
+	Name    string  `json:"name"`
+	Bytes   int64   `json:"bytes"`
+	Percent float64 `json:"percent"`
+}
+
+// languageExtensions maps file extensions to languages; other files
+// (documentation, data, assets) are not counted.
+var languageExtensions = map[string]string{
+	".go":    "Go",
+	".php":   "PHP",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".mts":   "TypeScript",
+	".cts":   "TypeScript",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".mjs":   "JavaScript",
+	".cjs":   "JavaScript",
+	".vue":   "Vue",
+	".css":   "CSS",
+	".scss":  "SCSS",
+	".py":    "Python",
+	".rb":    "Ruby",
+	".sh":    "Shell",
+	".bzl":   "Starlark",
+	".nix":   "Nix",
+	".sql":   "SQL",
+	".swift": "Swift",
+	".kt":    "Kotlin",
+	".java":  "Java",
+	".rs":    "Rust",
+}
+
+// languageCounter sums file sizes by language during the detection walk.
+type languageCounter map[string]int64
+
+func (c languageCounter) add(d fs.DirEntry) {
+	name := strings.ToLower(d.Name())
+	lang := languageExtensions[filepath.Ext(name)]
+	if strings.HasSuffix(name, ".blade.php") {
+		lang = "Blade"
+	}
+	if lang == "" {
+		return
+	}
+	info, err := d.Info()
+	if err != nil {
+		return
+	}
+	c[lang] += info.Size()
+}
+
+// shares returns the languages by descending size, with percentages
+// rounded to one decimal (nil when no source file was found).
+func (c languageCounter) shares() []LanguageShare {
+	var total int64
+	for _, n := range c {
+		total += n
+	}
+	if total == 0 {
+		return nil
+	}
+	out := make([]LanguageShare, 0, len(c))
+	for name, n := range c {
+		pct := math.Round(float64(n)*1000/float64(total)) / 10
+		out = append(out, LanguageShare{Name: name, Bytes: n, Percent: pct})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Bytes != out[j].Bytes {
+			return out[i].Bytes > out[j].Bytes
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
@@ -0,0 +1,56 @@
+package detect
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Files where Laravel applications define scheduled tasks, with the marker of a definition.
+var schedulerFiles = []struct {
+	Path   string
+	Marker string
+}{
+	{"routes/console.php", "Schedule::"},
+	{"bootstrap/app.php", "withSchedule("},
+	{"app/Console/Kernel.php", "$schedule->"},
+}
+
+// detectLaravelRuntime detects the Laravel runtime model: Octane, Horizon and
+// the Inertia server adapter from composer.json, and scheduled tasks from
+// their definition files.
+func detectLaravelRuntime(projectRoot string, stack *DetectedStack) error {
+	path := filepath.Join(projectRoot, "composer.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return readError("laravel", path, err)
+	}
+
+	var c composerJSON
+	if err := json.Unmarshal(data, &c); err != nil {
+		return parseError("laravel", path, data, err)
+	}
+	stack.accept(Octane, c.Require["laravel/octane"], path, `require["laravel/octane"]`)
+	stack.accept(Horizon, c.Require["laravel/horizon"], path, `require["laravel/horizon"]`)
+	stack.accept(Inertia, c.Require["inertiajs/inertia-laravel"], path, `require["inertiajs/inertia-laravel"]`)
+
+	if !stack.Has(Scheduler) {
+		for _, f := range schedulerFiles {
+			schedulerPath := filepath.Join(projectRoot, filepath.FromSlash(f.Path))
+			content, err := os.ReadFile(schedulerPath)
+			traceRead(schedulerPath, err)
+			if err != nil {
+				continue
+			}
+			if strings.Contains(string(content), f.Marker) {
+				stack.accept(Scheduler, f.Path, schedulerPath, "contains "+f.Marker)
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -1,9 +1,330 @@
 package detect
 
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Technology is a detected language, framework, library or tool.
+type Technology struct {
+	// Name identifies the technology and names its rules directory, e.g.
+	// laravel or nuxt_ui (see the constants below).
+	Name string `json:"name"`
+	// Version is the detected version or constraint, or the file that marks
+	// the technology when there is no version (e.g. flake.nix).
+	Version string `json:"version"`
+	// Source is the file and key the version came from, e.g.
+	// composer.json require["laravel/framework"].
+	Source string `json:"source,omitempty"`
+	// Paths are the directories (relative to the project root, "." for the
+	// root) where the technology was found.
+	Paths []string `json:"paths,omitempty"`
+	// Confidence is LowConfidence for versions inferred indirectly (e.g. from
+	// a Dockerfile base image) and empty for declared versions.
+	Confidence string `json:"confidence,omitempty"`
+}
+
+// LowConfidence marks a version inferred rather than declared.
+const LowConfidence = "low"
+
+// markerSource is the source of a technology found without a version, whose
+// Version is the file that marks it (e.g. package.json for JavaScript).
+const markerSource = "file exists"
+
+// Manifest returns the name of the file the version came from, e.g.
+// composer.json or .tool-versions.
+func (t Technology) Manifest() string {
+	file, _, _ := strings.Cut(t.Source, " ")
+	if file == "" {
+		return ""
+	}
+	return path.Base(file)
+}
+
+// Marker reports whether Version is the file that marks the technology
+// rather than a version.
+func (t Technology) Marker() bool {
+	return t.Source == markerSource || strings.HasSuffix(t.Source, " "+markerSource)
+}
+
+// Names of the technologies the detectors report.
+const (
+	PHP            = "php"
+	Laravel        = "laravel"
+	Nuxt           = "nuxt"
+	NuxtUI         = "nuxt_ui"
+	Go             = "go"
+	Node           = "node"
+	JavaScript     = "javascript"
+	TypeScript     = "typescript"
+	Pinia          = "pinia"
+	Vuex           = "vuex"
+	VueRouter      = "vue_router"
+	Octane         = "octane"
+	Horizon        = "horizon"
+	Scheduler      = "scheduler"
+	Inertia        = "inertia"
+	Bazel          = "bazel"
+	Nix            = "nix"
+	PackageManager = "package_manager"
+	Composer       = "composer"
+	Python         = "python"
+	Django         = "django"
+	FastAPI        = "fastapi"
+	Flask          = "flask"
+	Ruby           = "ruby"
+	Rails          = "rails"
+
+	// Frontend UI frameworks (see Frontends).
+	Vue     = "vue"
+	React   = "react"
+	Svelte  = "svelte"
+	Angular = "angular"
+)
+
+// Known lists the technology names the detectors report, for --set.
+var Known = []string{
+	PHP, Laravel, Nuxt, NuxtUI, Go, Node, JavaScript, TypeScript, Pinia, Vuex, VueRouter,
+	Octane, Horizon, Scheduler, Inertia, Bazel, Nix, PackageManager, Composer,
+	Python, Django, FastAPI, Flask, Ruby, Rails,
+	Vue, React, Svelte, Angular,
+}
+
+// DetectedStack is the result of stack detection: the detected technologies
+// plus structured details of some of them.
 type DetectedStack struct {
-	PHP     string `json:"php,omitempty"`
-	Laravel string `json:"laravel,omitempty"`
-	Nuxt    string `json:"nuxt,omitempty"`
-	Vue     string `json:"vue,omitempty"`
-	NuxtUI  string `json:"nuxt_ui,omitempty"`
+	// Technologies are in detection order; each name occurs once.
+	Technologies []Technology `json:"technologies,omitempty"`
+
+	// TSConfig holds the strictness flags of tsconfig.json.
+	TSConfig *TSConfig `json:"tsconfig,omitempty"`
+
+	// Languages is the breakdown of source files by language (bytes).
+	Languages []LanguageShare `json:"languages,omitempty"`
+
+	// Hooks is the git hook tooling of the project root (nil when not detected).
+	Hooks *GitHooks `json:"hooks,omitempty"`
+
+	// I18n is the localization setup of the project root (nil when the
+	// project is not localized).
+	I18n *I18n `json:"i18n,omitempty"`
+
+	// Performance holds the budgets of the performance tooling of the project
+	// root (nil when not detected).
+	Performance *PerformanceBudgets `json:"performance,omitempty"`
+
+	// Commits describes the commit message enforcement and release tooling
+	// of the project root (nil when not detected).
+	Commits *CommitConventions `json:"commits,omitempty"`
+
+	// root is the detection root, which Paths and Source are relative to.
+	root string
+}
+
+// Get returns the technology by name, ignoring case and underscores
+// (NuxtUI finds nuxt_ui).
+func (s *DetectedStack) Get(name string) (Technology, bool) {
+	if i := s.index(name); i >= 0 {
+		return s.Technologies[i], true
+	}
+	return Technology{}, false
+}
+
+// Version returns the detected version of a technology ("" when not detected).
+func (s *DetectedStack) Version(name string) string {
+	t, _ := s.Get(name)
+	return t.Version
+}
+
+// Has reports whether a technology was detected.
+func (s *DetectedStack) Has(name string) bool {
+	return s.index(name) >= 0
+}
+
+func (s *DetectedStack) index(name string) int {
+	if s == nil {
+		return -1
+	}
+	key := normalizeFieldName(name)
+	for i, t := range s.Technologies {
+		if normalizeFieldName(t.Name) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Frontends returns the detected frontend UI frameworks, in detection order.
+// Projects in transition (e.g. from Vue to React) use several at once.
+func (s *DetectedStack) Frontends() []Technology {
+	var out []Technology
+	if s == nil {
+		return out
+	}
+	for _, t := range s.Technologies {
+		for _, f := range frontendFrameworks {
+			if t.Name == f.Name {
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
+
+// Values returns the detected versions keyed by their name in rule
+// conditions (FieldName, e.g. "Laravel" for stack.Laravel).
+func (s *DetectedStack) Values() map[string]string {
+	out := map[string]string{}
+	if s == nil {
+		return out
+	}
+	for _, t := range s.Technologies {
+		out[FieldName(t.Name)] = t.Version
+	}
+	return out
+}
+
+// Set overrides one version by name ("nuxt_ui") or field name ("NuxtUI"),
+// ignoring case; an empty version drops the technology.
+func (s *DetectedStack) Set(name, version string) error {
+	canonical := ""
+	key := normalizeFieldName(name)
+	for _, k := range Known {
+		if normalizeFieldName(k) == key {
+			canonical = k
+		}
+	}
+	if i := s.index(name); i >= 0 {
+		canonical = s.Technologies[i].Name
+	}
+	if canonical == "" {
+		known := append([]string(nil), Known...)
+		sort.Strings(known)
+		return fmt.Errorf("unknown technology '%s' (known: %s)", name, strings.Join(known, ", "))
+	}
+
+	i := s.index(canonical)
+	switch {
+	case version == "" && i >= 0:
+		s.Technologies = append(s.Technologies[:i], s.Technologies[i+1:]...)
+	case version == "":
+	case i >= 0:
+		// The override replaces what detection found
+		s.Technologies[i].Version = version
+		s.Technologies[i].Source = ""
+		s.Technologies[i].Confidence = ""
+	default:
+		s.Technologies = append(s.Technologies, Technology{Name: canonical, Version: version})
+	}
+	return nil
+}
+
+// accept records value as the version of a technology unless it is already
+// known (the project root and earlier sources win), recording the decision
+// like accept. Either way the directory of path is added to its Paths.
+func (s *DetectedStack) accept(name, value, path, source string) {
+	if value == "" {
+		return
+	}
+	i := s.index(name)
+	if i < 0 {
+		s.Technologies = append(s.Technologies, Technology{Name: name})
+		i = len(s.Technologies) - 1
+	}
+	t := &s.Technologies[i]
+	if t.Version == "" {
+		t.Source = strings.TrimSpace(s.rel(path) + " " + source)
+	}
+	accept(&t.Version, name, value, path, source)
+
+	dir := filepath.ToSlash(filepath.Dir(s.rel(path)))
+	for _, p := range t.Paths {
+		if p == dir {
+			return
+		}
+	}
+	t.Paths = append(t.Paths, dir)
+}
+
+// infer is accept for a version inferred indirectly, which is marked as
+// LowConfidence when no earlier source declared one.
+func (s *DetectedStack) infer(name, value, path, source string) {
+	declared := s.Version(name) != ""
+	s.accept(name, value, path, source)
+	if i := s.index(name); i >= 0 && !declared && s.Technologies[i].Version != "" {
+		s.Technologies[i].Confidence = LowConfidence
+	}
+}
+
+// UnmarshalJSON also accepts technologies as top-level name/version pairs,
+// e.g. {"php": "^8.3", "laravel": "^11.0"}, for hand-written stacks.
+func (s *DetectedStack) UnmarshalJSON(data []byte) error {
+	type plain DetectedStack
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var version string
+		if json.Unmarshal(fields[name], &version) != nil {
+			continue // a structured field, e.g. tsconfig
+		}
+		if err := s.Set(name, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rel returns path relative to the detection root.
+func (s *DetectedStack) rel(path string) string {
+	if s.root == "" {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// fieldNames are the condition names that differ from the title-cased
+// technology name.
+var fieldNames = map[string]string{
+	PHP:        "PHP",
+	NuxtUI:     "NuxtUI",
+	JavaScript: "JavaScript",
+	TypeScript: "TypeScript",
+	FastAPI:    "FastAPI",
+}
+
+// FieldName returns the name of a technology in rule conditions, e.g.
+// Laravel for laravel and VueRouter for vue_router.
+func FieldName(name string) string {
+	if f, ok := fieldNames[name]; ok {
+		return f
+	}
+	parts := strings.Split(name, "_")
+	for i, p := range parts {
+		if p != "" {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
 }
@@ -0,0 +1,94 @@
+package detect
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// JavaScript lockfiles and the package manager that writes them, in priority order.
+var nodeLockfiles = []struct {
+	File    string
+	Manager string
+}{
+	{"pnpm-lock.yaml", "pnpm"},
+	{"bun.lock", "bun"},
+	{"bun.lockb", "bun"},
+	{"yarn.lock", "yarn"},
+	{"package-lock.json", "npm"},
+}
+
+// detectPackageManagers determines the JavaScript package manager (from the
+// packageManager field of package.json, then lockfiles) and the Composer version.
+func detectPackageManagers(projectRoot string, stack *DetectedStack) error {
+	if !stack.Has(PackageManager) {
+		pm, err := packageManagerField(projectRoot)
+		if err != nil {
+			return err
+		}
+		stack.accept(PackageManager, pm, filepath.Join(projectRoot, "package.json"), "packageManager")
+	}
+
+	if !stack.Has(PackageManager) && fileExists(filepath.Join(projectRoot, "package.json")) {
+		for _, l := range nodeLockfiles {
+			if path := filepath.Join(projectRoot, l.File); fileExists(path) {
+				stack.accept(PackageManager, l.Manager, path, "lockfile exists")
+				break
+			}
+		}
+	}
+
+	if !stack.Has(Composer) {
+		v, err := composerPluginAPIVersion(projectRoot)
+		if err != nil {
+			return err
+		}
+		stack.accept(Composer, v, filepath.Join(projectRoot, "composer.lock"), "plugin-api-version")
+	}
+	return nil
+}
+
+// packageManagerField reads the corepack "packageManager" field, e.g. "pnpm@9.1.0".
+func packageManagerField(projectRoot string) (string, error) {
+	path := filepath.Join(projectRoot, "package.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", readError("package manager", path, err)
+	}
+
+	var p struct {
+		PackageManager string `json:"packageManager"`
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return "", parseError("package manager", path, data, err)
+	}
+
+	// Drop the integrity hash: "pnpm@9.1.0+sha512.abc"
+	pm, _, _ := strings.Cut(strings.TrimSpace(p.PackageManager), "+")
+	return pm, nil
+}
+
+// composerPluginAPIVersion returns the Composer plugin API version recorded in
+// composer.lock, which tracks the Composer release that wrote it.
+func composerPluginAPIVersion(projectRoot string) (string, error) {
+	path := filepath.Join(projectRoot, "composer.lock")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", readError("package manager", path, err)
+	}
+
+	var lock struct {
+		PluginAPIVersion string `json:"plugin-api-version"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return "", parseError("package manager", path, data, err)
+	}
+	return lock.PluginAPIVersion, nil
+}
@@ -0,0 +1,266 @@
+package detect
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// PerformanceBudgets are the limits enforced by the performance tooling of a
+// project (Lighthouse CI, bundlesize).
+type PerformanceBudgets struct {
+	// LighthouseConfig is the Lighthouse CI config file; when it cannot be
+	// read statically (e.g. JS) Assertions and Budgets are empty.
+	LighthouseConfig string `json:"lighthouse_config,omitempty"`
+	// Assertions are the Lighthouse CI assertions (ci.assert.assertions), by audit.
+	Assertions []LighthouseAssertion `json:"assertions,omitempty"`
+	// BudgetsFile is the Lighthouse budgets file (ci.assert.budgetsFile).
+	BudgetsFile string `json:"budgets_file,omitempty"`
+	// Budgets are the limits of the budgets file.
+	Budgets []LighthouseBudget `json:"budgets,omitempty"`
+
+	// BundlesizeConfig is where the bundlesize limits are configured.
+	BundlesizeConfig string `json:"bundlesize_config,omitempty"`
+	// Bundles are the bundlesize limits.
+	Bundles []BundleBudget `json:"bundles,omitempty"`
+}
+
+// LighthouseAssertion is one Lighthouse CI assertion, e.g. largest-contentful-paint
+// at most 2500 (warn).
+type LighthouseAssertion struct {
+	Audit string `json:"audit"`
+	// Level is error or warn.
+	Level string `json:"level"`
+	// Limit is the option compared against, e.g. maxNumericValue or minScore
+	// ("" when the audit only has to pass).
+	Limit string `json:"limit,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// LighthouseBudget is one limit of a Lighthouse budgets file: a timing (ms),
+// resource size (KB) or resource count for the pages matching Path.
+type LighthouseBudget struct {
+	Path string `json:"path,omitempty"`
+	// Kind is timing, size or count.
+	Kind string `json:"kind"`
+	// Name is the metric (timings) or resource type (sizes and counts).
+	Name   string  `json:"name"`
+	Budget float64 `json:"budget"`
+}
+
+// BundleBudget is the maximum size of the files matching Path.
+type BundleBudget struct {
+	Path        string `json:"path"`
+	MaxSize     string `json:"max_size"`
+	Compression string `json:"compression,omitempty"`
+}
+
+// Empty reports whether no performance tooling was found.
+func (p *PerformanceBudgets) Empty() bool {
+	return p == nil || (p.LighthouseConfig == "" && p.BundlesizeConfig == "")
+}
+
+// Lighthouse CI config files, in the order lhci looks for them.
+var lighthouseConfigs = []string{".lighthouserc.json", "lighthouserc.json", ".lighthouserc.yml", "lighthouserc.yml", ".lighthouserc.yaml", "lighthouserc.yaml", ".lighthouserc.js", "lighthouserc.js", ".lighthouserc.cjs", "lighthouserc.cjs"}
+
+// DetectPerformanceBudgets reads the Lighthouse CI and bundlesize
+// configuration in projectRoot.
+func DetectPerformanceBudgets(projectRoot string) (*PerformanceBudgets, error) {
+	budgets := &PerformanceBudgets{}
+	if err := detectLighthouse(projectRoot, budgets); err != nil {
+		return nil, err
+	}
+	if err := detectBundlesize(projectRoot, budgets); err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func detectLighthouse(projectRoot string, budgets *PerformanceBudgets) error {
+	for _, name := range lighthouseConfigs {
+		path := filepath.Join(projectRoot, name)
+		if !fileExists(path) {
+			continue
+		}
+		budgets.LighthouseConfig = name
+		if filepath.Ext(name) == ".js" || filepath.Ext(name) == ".cjs" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		traceRead(path, err)
+		if err != nil {
+			return readError("performance", path, err)
+		}
+		// JSON is valid YAML, so one parser covers every static config
+		var config struct {
+			CI struct {
+				Assert struct {
+					Assertions  map[string]any `yaml:"assertions"`
+					BudgetsFile string         `yaml:"budgetsFile"`
+				} `yaml:"assert"`
+			} `yaml:"ci"`
+		}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return parseError("performance", path, data, err)
+		}
+		budgets.Assertions = lighthouseAssertions(config.CI.Assert.Assertions)
+		if file := config.CI.Assert.BudgetsFile; file != "" {
+			budgets.BudgetsFile = filepath.ToSlash(file)
+			return readLighthouseBudgets(filepath.Join(projectRoot, file), budgets)
+		}
+		return nil
+	}
+	return nil
+}
+
+// lighthouseAssertions normalizes assertions ("error" or ["error", {options}]),
+// leaving out those turned off.
+func lighthouseAssertions(raw map[string]any) []LighthouseAssertion {
+	var out []LighthouseAssertion
+	for _, audit := range sortedAnyKeys(raw) {
+		var level string
+		var options map[string]any
+		switch v := raw[audit].(type) {
+		case string:
+			level = v
+		case []any:
+			if len(v) > 0 {
+				level, _ = v[0].(string)
+			}
+			if len(v) > 1 {
+				options, _ = v[1].(map[string]any)
+			}
+		}
+		if level == "" || level == "off" {
+			continue
+		}
+
+		a := LighthouseAssertion{Audit: audit, Level: level}
+		for _, limit := range []string{"minScore", "maxNumericValue", "maxLength", "minLength"} {
+			if v, ok := options[limit]; ok {
+				a.Limit, a.Value = limit, formatValue(v)
+				break
+			}
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func readLighthouseBudgets(path string, budgets *PerformanceBudgets) error {
+	data, err := os.ReadFile(path)
+	traceRead(path, err)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return readError("performance", path, err)
+	}
+	type limit struct {
+		Metric       string  `json:"metric"`
+		ResourceType string  `json:"resourceType"`
+		Budget       float64 `json:"budget"`
+	}
+	var raw []struct {
+		Path           string  `json:"path"`
+		Timings        []limit `json:"timings"`
+		ResourceSizes  []limit `json:"resourceSizes"`
+		ResourceCounts []limit `json:"resourceCounts"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return parseError("performance", path, data, err)
+	}
+	for _, b := range raw {
+		for _, t := range b.Timings {
+			budgets.Budgets = append(budgets.Budgets, LighthouseBudget{Path: b.Path, Kind: "timing", Name: t.Metric, Budget: t.Budget})
+		}
+		for _, s := range b.ResourceSizes {
+			budgets.Budgets = append(budgets.Budgets, LighthouseBudget{Path: b.Path, Kind: "size", Name: s.ResourceType, Budget: s.Budget})
+		}
+		for _, c := range b.ResourceCounts {
+			budgets.Budgets = append(budgets.Budgets, LighthouseBudget{Path: b.Path, Kind: "count", Name: c.ResourceType, Budget: c.Budget})
+		}
+	}
+	return nil
+}
+
+// detectBundlesize reads the bundlesize limits from package.json, then from
+// bundlesize.config.json or .bundlesizerc.
+func detectBundlesize(projectRoot string, budgets *PerformanceBudgets) error {
+	type file struct {
+		Path        string `json:"path"`
+		MaxSize     string `json:"maxSize"`
+		Compression string `json:"compression"`
+	}
+	add := func(config string, files []file) {
+		budgets.BundlesizeConfig = config
+		for _, f := range files {
+			if f.Path != "" && f.MaxSize != "" {
+				budgets.Bundles = append(budgets.Bundles, BundleBudget{Path: f.Path, MaxSize: f.MaxSize, Compression: f.Compression})
+			}
+		}
+	}
+
+	path := filepath.Join(projectRoot, "package.json")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return readError("performance", path, err)
+	}
+	if err == nil {
+		// A malformed package.json is reported by the javascript detector
+		var p struct {
+			Bundlesize []file `json:"bundlesize"`
+		}
+		if json.Unmarshal(data, &p) == nil && len(p.Bundlesize) > 0 {
+			add("package.json", p.Bundlesize)
+			return nil
+		}
+	}
+
+	for _, name := range []string{"bundlesize.config.json", ".bundlesizerc"} {
+		path := filepath.Join(projectRoot, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return readError("performance", path, err)
+		}
+		traceRead(path, nil)
+		var config struct {
+			Files []file `json:"files"`
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return parseError("performance", path, data, err)
+		}
+		add(name, config.Files)
+		return nil
+	}
+	return nil
+}
+
+// formatValue formats a YAML or JSON scalar without a trailing ".0".
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	}
+	return fmt.Sprint(v)
+}
+
+func sortedAnyKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
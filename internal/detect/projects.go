@@ -0,0 +1,128 @@
+package detect
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/cego/ai-instructions/internal/aiignore"
+	"github.com/cego/ai-instructions/internal/warnings"
+)
+
+// Project is a subdirectory with its own manifests (composer.json / package.json / go.mod /
+// pyproject.toml, Pipfile or requirements.txt / Gemfile).
+type Project struct {
+	// Path relative to the project root, using forward slashes.
+	Path  string         `json:"path"`
+	Stack *DetectedStack `json:"stack"`
+	// Dependencies lists notable direct dependencies (e.g. Go module requirements).
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// DetectProjects finds all subprojects below projectRoot and detects the stack
+// of each one independently (non-recursively), for monorepo layouts.
+func DetectProjects(projectRoot string) ([]Project, error) {
+	var projects []Project
+
+	ignore, err := aiignore.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.WalkDir(projectRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if path == projectRoot || !d.IsDir() {
+			return nil
+		}
+		if skipDir(d.Name()) || restricted(ignore, projectRoot, path, true) {
+			return fs.SkipDir
+		}
+		if !hasManifest(path) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(projectRoot, path)
+		if err != nil {
+			return err
+		}
+		projects = append(projects, detectProject(path, filepath.ToSlash(rel)))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Go workspaces: every module listed in go.work is a project, even when
+	// the walk skipped its directory
+	work, err := readGoWork(filepath.Join(projectRoot, "go.work"))
+	if err != nil {
+		return nil, err
+	}
+	if work != nil {
+		known := map[string]bool{}
+		for _, p := range projects {
+			known[p.Path] = true
+		}
+		for _, use := range work.Uses {
+			rel := filepath.ToSlash(filepath.Clean(use))
+			if rel == "." || known[rel] || !hasManifest(filepath.Join(projectRoot, use)) {
+				continue
+			}
+			known[rel] = true
+			projects = append(projects, detectProject(filepath.Join(projectRoot, use), rel))
+		}
+	}
+
+	return projects, nil
+}
+
+// detectProject detects the stack of a single directory (non-recursively).
+func detectProject(dir, rel string) Project {
+	stack := &DetectedStack{root: dir}
+	for _, detectFn := range []func(string, *DetectedStack) error{
+		detectToolVersions,
+		detectDevcontainer,
+		detectFromComposer,
+		detectFromComposerLock,
+		detectFromPackageJson,
+		detectFromPackageLockJson,
+		detectFromGoMod,
+		detectFromPyproject,
+		detectFromPipfile,
+		detectFromRequirements,
+		detectFromGemfile,
+		detectFromGemfileLock,
+		detectTypeScript,
+		detectLaravelRuntime,
+		detectPackageManagers,
+		func(dir string, stack *DetectedStack) error {
+			return detectFromDockerfiles(dockerfilesIn(dir), stack)
+		},
+	} {
+		if err := skipMalformed(detectFn(dir, stack)); err != nil {
+			warnings.Add("detect", "skipped unreadable manifest in %s: %v", rel, err)
+		}
+	}
+
+	p := Project{Path: rel, Stack: stack}
+	if mod, err := readGoMod(filepath.Join(dir, "go.mod")); err == nil && mod != nil {
+		p.Dependencies = mod.Requires
+	}
+	return p
+}
+
+func hasManifest(dir string) bool {
+	for _, name := range []string{"composer.json", "package.json", "go.mod", "pyproject.toml", "Pipfile", "requirements.txt", "Gemfile"} {
+		if fileExists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
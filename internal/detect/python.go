@@ -0,0 +1,268 @@
+package detect
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pythonFrameworks maps the Python web frameworks to their PyPI package.
+var pythonFrameworks = []struct{ Name, Package string }{
+	{Django, "django"},
+	{FastAPI, "fastapi"},
+	{Flask, "flask"},
+}
+
+var (
+	// pythonRequirement splits a PEP 508 requirement into the package name
+	// and the version specifier, e.g. "Django[argon2]>=5.0,<6".
+	pythonRequirement = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*(?:\(([^)]*)\)|([^;@]*))`)
+	// pythonNameSeparators are normalized in package names (PEP 503).
+	pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+)
+
+// pythonPackage normalizes a package name, so that Django, django and
+// Flask_SQLAlchemy match their PyPI names.
+func pythonPackage(name string) string {
+	return pythonNameSeparators.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "-")
+}
+
+// acceptPythonDeps offers the versions of the frameworks among deps (package
+// name to version specifier). Unpinned frameworks are marked by the
+// manifest, like JavaScript by package.json.
+func acceptPythonDeps(stack *DetectedStack, deps map[string]string, path, key string) {
+	for _, f := range pythonFrameworks {
+		spec, ok := deps[f.Package]
+		if !ok {
+			continue
+		}
+		source := fmt.Sprintf("%s[%q]", key, f.Package)
+		if spec == "" || spec == "*" {
+			spec, source = filepath.Base(path), markerSource
+		}
+		stack.accept(f.Name, spec, path, source)
+	}
+}
+
+// detectFromPyproject reads the Python version and frameworks of
+// pyproject.toml: PEP 621 ([project] requires-python and dependencies) and
+// Poetry ([tool.poetry.dependencies]).
+func detectFromPyproject(projectRoot string, stack *DetectedStack) error {
+	path := filepath.Join(projectRoot, "pyproject.toml")
+	tables, err := readTOMLTables(path, "project", "tool.poetry.dependencies")
+	if err != nil {
+		return readError("python", path, err)
+	}
+	if tables == nil {
+		return nil
+	}
+
+	project := tables["project"]
+	poetry := tables["tool.poetry.dependencies"]
+	stack.accept(Python, tomlScalar(project["requires-python"]), path, "project.requires-python")
+	stack.accept(Python, tomlScalar(poetry["python"]), path, "tool.poetry.dependencies.python")
+	stack.accept(Python, "pyproject.toml", path, markerSource)
+
+	deps := map[string]string{}
+	for _, req := range tomlArray(project["dependencies"]) {
+		if name, spec, ok := parseRequirement(req); ok {
+			deps[name] = spec
+		}
+	}
+	acceptPythonDeps(stack, deps, path, "project.dependencies")
+
+	deps = map[string]string{}
+	for name, value := range poetry {
+		deps[pythonPackage(name)] = tomlScalar(value)
+	}
+	acceptPythonDeps(stack, deps, path, "tool.poetry.dependencies")
+	return nil
+}
+
+// detectFromPipfile reads the Python version ([requires]) and the frameworks
+// ([packages]) of a Pipfile.
+func detectFromPipfile(projectRoot string, stack *DetectedStack) error {
+	path := filepath.Join(projectRoot, "Pipfile")
+	tables, err := readTOMLTables(path, "requires", "packages")
+	if err != nil {
+		return readError("python", path, err)
+	}
+	if tables == nil {
+		return nil
+	}
+
+	requires := tables["requires"]
+	stack.accept(Python, tomlScalar(requires["python_full_version"]), path, "requires.python_full_version")
+	stack.accept(Python, tomlScalar(requires["python_version"]), path, "requires.python_version")
+	stack.accept(Python, "Pipfile", path, markerSource)
+
+	deps := map[string]string{}
+	for name, value := range tables["packages"] {
+		deps[pythonPackage(name)] = tomlScalar(value)
+	}
+	acceptPythonDeps(stack, deps, path, "packages")
+	return nil
+}
+
+// detectFromRequirements reads the frameworks of requirements.txt. It holds
+// no Python version, so Python is marked by the file.
+func detectFromRequirements(projectRoot string, stack *DetectedStack) error {
+	path := filepath.Join(projectRoot, "requirements.txt")
+	f, err := os.Open(path)
+	traceRead(path, err)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return readError("python", path, err)
+	}
+	defer f.Close()
+
+	deps := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), " #")
+		line = strings.TrimSpace(line)
+		// Options (-r other.txt, -e ., --index-url) and URLs name no package
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+		if name, spec, ok := parseRequirement(line); ok {
+			deps[name] = spec
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return readError("python", path, err)
+	}
+
+	stack.accept(Python, "requirements.txt", path, markerSource)
+	acceptPythonDeps(stack, deps, path, "requirements")
+	return nil
+}
+
+// parseRequirement returns the normalized package name and the version
+// specifier of a PEP 508 requirement, without environment markers.
+func parseRequirement(req string) (name, spec string, ok bool) {
+	m := pythonRequirement.FindStringSubmatch(strings.TrimSpace(req))
+	if m == nil {
+		return "", "", false
+	}
+	return pythonPackage(m[1]), strings.TrimSpace(m[2] + m[3]), true
+}
+
+// readTOMLTables returns the keys of the given tables of a TOML file, with
+// their raw values (nil when the file does not exist). Multi-line arrays are
+// joined into one value; nested tables are not read.
+func readTOMLTables(path string, names ...string) (map[string]map[string]string, error) {
+	f, err := os.Open(path)
+	traceRead(path, err)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	tables := map[string]map[string]string{}
+	for _, name := range names {
+		tables[name] = map[string]string{}
+	}
+	var current map[string]string
+	key, value := "", ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripTOMLComment(scanner.Text()))
+		if key != "" {
+			// Continuation of a multi-line array
+			value += " " + line
+			if tomlArrayClosed(value) {
+				current[key], key = value, ""
+			}
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && !strings.Contains(line, "=") {
+			current = tables[strings.Trim(line, "[] ")]
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if current == nil || !ok {
+			continue
+		}
+		k, v = strings.Trim(strings.TrimSpace(k), `"'`), strings.TrimSpace(v)
+		if strings.HasPrefix(v, "[") && !tomlArrayClosed(v) {
+			key, value = k, v
+			continue
+		}
+		current[k] = v
+	}
+	return tables, scanner.Err()
+}
+
+// stripTOMLComment removes the comment of a TOML line, keeping # in strings.
+func stripTOMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// tomlArrayClosed reports whether the brackets of an array value balance,
+// ignoring those inside strings.
+func tomlArrayClosed(value string) bool {
+	depth := 0
+	var quote rune
+	for _, r := range value {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		}
+	}
+	return depth == 0
+}
+
+// tomlScalar returns the string of a raw TOML value, or the version of an
+// inline table (e.g. { version = "^5.0", extras = ["bcrypt"] }).
+func tomlScalar(raw string) string {
+	m := tomlString.FindStringSubmatch(raw)
+	if strings.HasPrefix(raw, "{") {
+		m = tomlVersionKey.FindStringSubmatch(raw)
+	}
+	if m == nil {
+		return ""
+	}
+	return m[1] + m[2]
+}
+
+// tomlArray returns the strings of a raw TOML array.
+func tomlArray(raw string) []string {
+	var out []string
+	for _, m := range tomlString.FindAllStringSubmatch(raw, -1) {
+		out = append(out, m[1]+m[2])
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const samplePyproject = `# Project metadata
+[project]
+name = "shop"
+requires-python = ">=3.11" # the oldest supported release
+dependencies = [
+    "django>=5.0,<6",  # web
+    "celery[redis] ~= 5.3",
+    "weird # name",
+]
+"quoted-key" = 'single # quoted'
+
+[project.optional-dependencies]
+test = ["pytest"]
+
+[tool.poetry.dependencies]
+python = "^3.12"
+Django = { version = "^5.0", extras = ["bcrypt"] }
+fastapi = "*"
+
+[[tool.other]]
+name = "ignored"
+`
+
+func TestReadTOMLTables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pyproject.toml")
+	if err := os.WriteFile(path, []byte(samplePyproject), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readTOMLTables(path, "project", "tool.poetry.dependencies", "requires")
+	if err != nil {
+		t.Fatalf("readTOMLTables() error = %v", err)
+	}
+	want := map[string]map[string]string{
+		"project": {
+			"name":            `"shop"`,
+			"requires-python": `">=3.11"`,
+			"dependencies":    `[ "django>=5.0,<6", "celery[redis] ~= 5.3", "weird # name", ]`,
+			"quoted-key":      `'single # quoted'`,
+		},
+		"tool.poetry.dependencies": {
+			"python":  `"^3.12"`,
+			"Django":  `{ version = "^5.0", extras = ["bcrypt"] }`,
+			"fastapi": `"*"`,
+		},
+		"requires": {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readTOMLTables() =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestReadTOMLTablesMissingFile(t *testing.T) {
+	got, err := readTOMLTables(filepath.Join(t.TempDir(), "Pipfile"), "packages")
+	if got != nil || err != nil {
+		t.Errorf("readTOMLTables() = %v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestTOMLValues(t *testing.T) {
+	scalars := map[string]string{
+		`"^3.12"`: "^3.12",
+		`'3.11'`:  "3.11",
+		`{ version = "^5.0", extras = ["bcrypt"] }`: "^5.0",
+		`{ git = "https://example.com/repo.git" }`:  "",
+		`true`: "",
+	}
+	for raw, want := range scalars {
+		if got := tomlScalar(raw); got != want {
+			t.Errorf("tomlScalar(%s) = %q, want %q", raw, got, want)
+		}
+	}
+
+	got := tomlArray(`[ "django>=5.0,<6", 'flask', ]`)
+	if want := []string{"django>=5.0,<6", "flask"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tomlArray() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package detect
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// gemfileRuby matches the Ruby directive of a Gemfile, e.g. ruby "3.3.0"
+	// or ruby '~> 3.2'.
+	gemfileRuby = regexp.MustCompile(`^ruby\s*\(?\s*["']([^"']+)["']`)
+	// gemfileGem matches a gem declaration with its first version
+	// requirement, e.g. gem "rails", "~> 7.1".
+	gemfileGem = regexp.MustCompile(`^gem\s*\(?\s*["']([^"']+)["'](?:\s*,\s*["']([^"']+)["'])?`)
+	// lockfileSpec matches a top-level gem of the specs of a Gemfile.lock,
+	// e.g. "    rails (7.1.3)".
+	lockfileSpec = regexp.MustCompile(`^    ([^\s(]+) \(([^)]+)\)$`)
+)
+
+// detectFromGemfile reads the Ruby version (the ruby directive) and the
+// Rails requirement of a Gemfile.
+func detectFromGemfile(projectRoot string, stack *DetectedStack) error {
+	path := filepath.Join(projectRoot, "Gemfile")
+	f, err := os.Open(path)
+	traceRead(path, err)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return readError("ruby", path, err)
+	}
+	defer f.Close()
+
+	railsGem, rails := false, ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " #")
+		if m := gemfileRuby.FindStringSubmatch(line); m != nil {
+			stack.accept(Ruby, m[1], path, "ruby")
+		}
+		if m := gemfileGem.FindStringSubmatch(line); m != nil && m[1] == "rails" {
+			railsGem, rails = true, m[2]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return readError("ruby", path, err)
+	}
+	stack.accept(Rails, rails, path, `gem "rails"`)
+
+	// Unconstrained gems and Ruby are versioned by the lockfile, when there is one
+	if fileExists(filepath.Join(projectRoot, "Gemfile.lock")) {
+		return nil
+	}
+	if railsGem && rails == "" {
+		stack.accept(Rails, "Gemfile", path, markerSource)
+	}
+	stack.accept(Ruby, "Gemfile", path, markerSource)
+	return nil
+}
+
+// detectFromGemfileLock reads the locked Ruby version (RUBY VERSION) and
+// Rails version (the rails spec of the GEM section).
+func detectFromGemfileLock(projectRoot string, stack *DetectedStack) error {
+	path := filepath.Join(projectRoot, "Gemfile.lock")
+	f, err := os.Open(path)
+	traceRead(path, err)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return readError("ruby", path, err)
+	}
+	defer f.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" && !strings.HasPrefix(line, " ") {
+			section = line
+			continue
+		}
+		switch section {
+		case "RUBY VERSION":
+			// e.g. "   ruby 3.3.0p0"
+			if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "ruby" {
+				stack.accept(Ruby, leadingVersion.FindString(fields[1]), path, "RUBY VERSION")
+			}
+		case "GEM":
+			if m := lockfileSpec.FindStringSubmatch(line); m != nil && m[1] == "rails" {
+				stack.accept(Rails, m[2], path, "GEM specs rails")
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return readError("ruby", path, err)
+	}
+	// Every bundle runs on Ruby, even when the lockfile does not record it
+	stack.accept(Ruby, "Gemfile.lock", path, markerSource)
+	return nil
+}
@@ -0,0 +1,225 @@
+package detect
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/jsonc"
+)
+
+// runtimeTools maps asdf/mise tool and devcontainer feature names to the
+// runtime they pin.
+var runtimeTools = map[string]string{
+	"php":    PHP,
+	"node":   Node,
+	"nodejs": Node,
+	"go":     Go,
+	"golang": Go,
+	"python": Python,
+	"ruby":   Ruby,
+}
+
+// versionFiles are the single-runtime version files of pyenv and rbenv (or
+// chruby), which hold one version per line; the first is the default.
+var versionFiles = []struct{ Name, Runtime string }{
+	{".python-version", Python},
+	{".ruby-version", Ruby},
+}
+
+// miseFiles are the mise config files, in order of precedence.
+var miseFiles = []string{"mise.toml", ".mise.toml", filepath.Join(".config", "mise.toml")}
+
+var (
+	// tomlString matches a basic or literal TOML string.
+	tomlString = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+	// tomlVersionKey matches the version of an inline table, e.g. { version = "20" }.
+	tomlVersionKey = regexp.MustCompile(`\bversion\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// detectToolVersions reads the runtime versions pinned by asdf
+// (.tool-versions), mise (mise.toml), pyenv (.python-version) and rbenv
+// (.ruby-version). Developers
+// run exactly these, so they are read before the manifests, whose
+// constraints are ranges.
+func detectToolVersions(projectRoot string, stack *DetectedStack) error {
+	path := filepath.Join(projectRoot, ".tool-versions")
+	tools, err := readToolVersions(path)
+	if err != nil {
+		return readError("tool versions", path, err)
+	}
+	for _, t := range tools {
+		stack.accept(runtimeTools[t.name], leadingVersion.FindString(t.version), path, t.name)
+	}
+
+	for _, name := range miseFiles {
+		path := filepath.Join(projectRoot, name)
+		tools, err := readMiseTools(path)
+		if err != nil {
+			return readError("tool versions", path, err)
+		}
+		for _, t := range tools {
+			stack.accept(runtimeTools[t.name], leadingVersion.FindString(t.version), path, "[tools]."+t.name)
+		}
+	}
+
+	for _, file := range versionFiles {
+		path := filepath.Join(projectRoot, file.Name)
+		data, err := os.ReadFile(path)
+		traceRead(path, err)
+		if err != nil && !os.IsNotExist(err) {
+			return readError("tool versions", path, err)
+		}
+		if fields := strings.Fields(string(data)); len(fields) > 0 {
+			// rbenv also accepts the engine prefix, e.g. ruby-3.3.0
+			version := strings.TrimPrefix(fields[0], file.Runtime+"-")
+			stack.accept(file.Runtime, leadingVersion.FindString(version), path, "file contents")
+		}
+	}
+	return nil
+}
+
+// pinnedTool is a runtime tool with its pinned version.
+type pinnedTool struct {
+	name    string
+	version string
+}
+
+// readToolVersions returns the runtime tools of an asdf .tool-versions file,
+// e.g. "nodejs 20.11.0" (nil when the file does not exist). Of several
+// versions on a line, the first is the default.
+func readToolVersions(path string) ([]pinnedTool, error) {
+	f, err := os.Open(path)
+	traceRead(path, err)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []pinnedTool
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 || runtimeTools[fields[0]] == "" {
+			continue
+		}
+		out = append(out, pinnedTool{name: fields[0], version: fields[1]})
+	}
+	return out, scanner.Err()
+}
+
+// readMiseTools returns the runtime tools of the [tools] table of a mise
+// config (nil when the file does not exist). Values are a version, a list of
+// versions (the first is the default) or an inline table with a version.
+func readMiseTools(path string) ([]pinnedTool, error) {
+	f, err := os.Open(path)
+	traceRead(path, err)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []pinnedTool
+	inTools := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && !strings.Contains(line, "=") {
+			inTools = line == "[tools]"
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !inTools || !ok {
+			continue
+		}
+		name := strings.Trim(strings.TrimSpace(key), `"'`)
+		if runtimeTools[name] == "" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		m := tomlString.FindStringSubmatch(value)
+		if strings.HasPrefix(value, "{") {
+			m = tomlVersionKey.FindStringSubmatch(value)
+		}
+		if m != nil {
+			out = append(out, pinnedTool{name: name, version: m[1] + m[2]})
+		}
+	}
+	return out, scanner.Err()
+}
+
+// detectDevcontainer reads the runtime versions of the features of the dev
+// container configurations (.devcontainer/devcontainer.json,
+// .devcontainer.json and .devcontainer/<name>/devcontainer.json), e.g.
+// "ghcr.io/devcontainers/features/node:1": {"version": "20"}.
+func detectDevcontainer(projectRoot string, stack *DetectedStack) error {
+	paths := []string{
+		filepath.Join(projectRoot, ".devcontainer", "devcontainer.json"),
+		filepath.Join(projectRoot, ".devcontainer.json"),
+	}
+	nested, _ := filepath.Glob(filepath.Join(projectRoot, ".devcontainer", "*", "devcontainer.json"))
+	sort.Strings(nested)
+	paths = append(paths, nested...)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		traceRead(path, err)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return readError("devcontainer", path, err)
+		}
+		var c struct {
+			Features map[string]json.RawMessage `json:"features"`
+		}
+		if err := jsonc.Unmarshal(data, &c); err != nil {
+			return parseError("devcontainer", path, data, err)
+		}
+
+		ids := make([]string, 0, len(c.Features))
+		for id := range c.Features {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			tech := runtimeTools[featureName(id)]
+			if tech == "" {
+				continue
+			}
+			// Options are an object, or the version as a shorthand
+			version := ""
+			if json.Unmarshal(c.Features[id], &version) != nil {
+				var opts struct {
+					Version string `json:"version"`
+				}
+				if json.Unmarshal(c.Features[id], &opts) == nil {
+					version = opts.Version
+				}
+			}
+			stack.accept(tech, leadingVersion.FindString(version), path, fmt.Sprintf("features[%q].version", id))
+		}
+	}
+	return nil
+}
+
+// featureName returns the name of a dev container feature ID, e.g. node for
+// ghcr.io/devcontainers/features/node:1.
+func featureName(id string) string {
+	name, _, _ := strings.Cut(path.Base(id), ":")
+	name, _, _ = strings.Cut(name, "@")
+	return strings.ToLower(name)
+}
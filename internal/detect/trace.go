@@ -0,0 +1,115 @@
+package detect
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// TraceEvent is one step of stack detection: a file examined or skipped, or a
+// candidate value that was accepted or rejected for a stack field.
+type TraceEvent struct {
+	Step   int    `json:"step"`
+	Action string `json:"action"`
+	Path   string `json:"path,omitempty"`
+	Field  string `json:"field,omitempty"`
+	Value  string `json:"value,omitempty"`
+	// Source is where in the file the value came from, e.g. devDependencies["vue"].
+	Source string `json:"source,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Trace actions.
+const (
+	TraceExamine = "examine"
+	TraceMissing = "missing"
+	TraceSkip    = "skip"
+	TraceError   = "error"
+	TraceAccept  = "accept"
+	TraceReject  = "reject"
+)
+
+// The active trace, recorded only between StartTrace and StopTrace.
+var (
+	traceMu sync.Mutex
+	tracing bool
+	events  []TraceEvent
+	origins map[string]string
+)
+
+// StartTrace begins recording detection decisions (see StopTrace).
+func StartTrace() {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	tracing, events, origins = true, nil, map[string]string{}
+}
+
+// StopTrace stops recording and returns the recorded events.
+func StopTrace() []TraceEvent {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	out := events
+	tracing, events, origins = false, nil, nil
+	return out
+}
+
+func record(e TraceEvent) {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	if !tracing {
+		return
+	}
+	e.Path = filepath.ToSlash(e.Path)
+	e.Step = len(events) + 1
+	events = append(events, e)
+}
+
+// traceRead records the outcome of reading a manifest.
+func traceRead(path string, err error) {
+	switch {
+	case err == nil:
+		record(TraceEvent{Action: TraceExamine, Path: path})
+	case os.IsNotExist(err):
+		record(TraceEvent{Action: TraceMissing, Path: path})
+	default:
+		record(TraceEvent{Action: TraceError, Path: path, Reason: err.Error()})
+	}
+}
+
+// traceSkip records a file or directory that detection ignores.
+func traceSkip(path, reason string) {
+	record(TraceEvent{Action: TraceSkip, Path: path, Reason: reason})
+}
+
+// accept sets *dst to value unless the field is already set (the project
+// root and earlier sources win), recording the decision either way.
+func accept(dst *string, field, value, path, source string) {
+	if value == "" {
+		return
+	}
+	e := TraceEvent{Path: path, Field: field, Value: value, Source: source}
+	if *dst != "" {
+		e.Action = TraceReject
+		e.Reason = fmt.Sprintf("already set to %q", *dst)
+		traceMu.Lock()
+		if origin, ok := origins[field]; ok {
+			e.Reason += " from " + origin
+		}
+		traceMu.Unlock()
+		record(e)
+		return
+	}
+
+	*dst = value
+	e.Action = TraceAccept
+	traceMu.Lock()
+	if origins != nil {
+		origins[field] = filepath.ToSlash(path)
+		if source != "" {
+			origins[field] += " " + source
+		}
+	}
+	traceMu.Unlock()
+	record(e)
+}
@@ -0,0 +1,149 @@
+package detect
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/jsonc"
+)
+
+// TSConfig holds the type-checking strictness flags of tsconfig.json (after
+// following relative "extends"). Nil flags are not set anywhere.
+type TSConfig struct {
+	Path             string `json:"path"`
+	Strict           *bool  `json:"strict,omitempty"`
+	NoImplicitAny    *bool  `json:"no_implicit_any,omitempty"`
+	StrictNullChecks *bool  `json:"strict_null_checks,omitempty"`
+}
+
+// IsStrict reports whether strict mode is enabled.
+func (c *TSConfig) IsStrict() bool {
+	return c != nil && c.Strict != nil && *c.Strict
+}
+
+// NoImplicitAnyEnabled reports whether implicit any is rejected (strict implies it unless overridden).
+func (c *TSConfig) NoImplicitAnyEnabled() bool {
+	if c == nil {
+		return false
+	}
+	if c.NoImplicitAny != nil {
+		return *c.NoImplicitAny
+	}
+	return c.IsStrict()
+}
+
+// StrictNullChecksEnabled reports whether null checks are enabled (strict implies it unless overridden).
+func (c *TSConfig) StrictNullChecksEnabled() bool {
+	if c == nil {
+		return false
+	}
+	if c.StrictNullChecks != nil {
+		return *c.StrictNullChecks
+	}
+	return c.IsStrict()
+}
+
+// Maximum depth of relative "extends" chains followed.
+const maxTSConfigExtends = 5
+
+// detectTypeScript detects the TypeScript version (package.json) and the
+// strictness flags of tsconfig.json.
+func detectTypeScript(projectRoot string, stack *DetectedStack) error {
+	pkgPath := filepath.Join(projectRoot, "package.json")
+	data, err := os.ReadFile(pkgPath)
+	if err != nil && !os.IsNotExist(err) {
+		return readError("typescript", pkgPath, err)
+	}
+	if err == nil {
+		var p packageJSON
+		if err := json.Unmarshal(data, &p); err != nil {
+			return parseError("typescript", pkgPath, data, err)
+		}
+		if v, ok := p.DevDependencies["typescript"]; ok {
+			stack.accept(TypeScript, v, pkgPath, `devDependencies["typescript"]`)
+		} else if v, ok := p.Dependencies["typescript"]; ok {
+			stack.accept(TypeScript, v, pkgPath, `dependencies["typescript"]`)
+		}
+	}
+
+	if stack.TSConfig != nil {
+		return nil
+	}
+	path := filepath.Join(projectRoot, "tsconfig.json")
+	if !fileExists(path) {
+		return nil
+	}
+
+	cfg := &TSConfig{Path: "tsconfig.json"}
+	if err := readTSConfig(path, cfg, 0); err != nil {
+		return err
+	}
+	stack.TSConfig = cfg
+	stack.accept(TypeScript, "tsconfig.json", path, markerSource)
+	return nil
+}
+
+// readTSConfig fills unset flags of cfg from path, then from the file it extends.
+func readTSConfig(path string, cfg *TSConfig, depth int) error {
+	data, err := os.ReadFile(path)
+	traceRead(path, err)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// e.g. a generated .nuxt/tsconfig.json that does not exist yet
+			return nil
+		}
+		return readError("typescript", path, err)
+	}
+
+	var raw struct {
+		Extends         json.RawMessage `json:"extends"`
+		CompilerOptions struct {
+			Strict           *bool `json:"strict"`
+			NoImplicitAny    *bool `json:"noImplicitAny"`
+			StrictNullChecks *bool `json:"strictNullChecks"`
+		} `json:"compilerOptions"`
+	}
+	// tsconfig files (e.g. the one generated in .nuxt) may contain comments
+	if err := jsonc.Unmarshal(data, &raw); err != nil {
+		return parseError("typescript", path, data, err)
+	}
+
+	if cfg.Strict == nil {
+		cfg.Strict = raw.CompilerOptions.Strict
+	}
+	if cfg.NoImplicitAny == nil {
+		cfg.NoImplicitAny = raw.CompilerOptions.NoImplicitAny
+	}
+	if cfg.StrictNullChecks == nil {
+		cfg.StrictNullChecks = raw.CompilerOptions.StrictNullChecks
+	}
+
+	if depth >= maxTSConfigExtends || len(raw.Extends) == 0 {
+		return nil
+	}
+	// "extends" is a path or (TypeScript 5) a list of paths; only relative ones are followed
+	var bases []string
+	if err := json.Unmarshal(raw.Extends, &bases); err != nil {
+		var base string
+		if err := json.Unmarshal(raw.Extends, &base); err != nil {
+			return nil
+		}
+		bases = []string{base}
+	}
+	// Later entries override earlier ones
+	for i := len(bases) - 1; i >= 0; i-- {
+		base := bases[i]
+		if !strings.HasPrefix(base, ".") {
+			continue
+		}
+		if !strings.HasSuffix(base, ".json") {
+			base += ".json"
+		}
+		if err := readTSConfig(filepath.Join(filepath.Dir(path), filepath.FromSlash(base)), cfg, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
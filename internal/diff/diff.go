@@ -0,0 +1,145 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind is the type of a diff operation.
+type Kind int
+
+const (
+	Equal Kind = iota
+	Insert
+	Delete
+)
+
+// Op is a single line in a line-based diff.
+type Op struct {
+	Kind Kind
+	Text string
+}
+
+// Lines computes a line diff turning a into b (longest common subsequence).
+func Lines(a, b string) []Op {
+	al := splitLines(a)
+	bl := splitLines(b)
+
+	// lcs[i][j] = length of the LCS of al[i:] and bl[j:]
+	lcs := make([][]int, len(al)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bl)+1)
+	}
+	for i := len(al) - 1; i >= 0; i-- {
+		for j := len(bl) - 1; j >= 0; j-- {
+			if al[i] == bl[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []Op
+	i, j := 0, 0
+	for i < len(al) && j < len(bl) {
+		switch {
+		case al[i] == bl[j]:
+			ops = append(ops, Op{Kind: Equal, Text: al[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, Op{Kind: Delete, Text: al[i]})
+			i++
+		default:
+			ops = append(ops, Op{Kind: Insert, Text: bl[j]})
+			j++
+		}
+	}
+	for ; i < len(al); i++ {
+		ops = append(ops, Op{Kind: Delete, Text: al[i]})
+	}
+	for ; j < len(bl); j++ {
+		ops = append(ops, Op{Kind: Insert, Text: bl[j]})
+	}
+	return ops
+}
+
+// Inserted returns the blocks of consecutive lines present in b but not in a.
+func Inserted(a, b string) []string {
+	var blocks []string
+	var current []string
+	flush := func() {
+		if text := strings.TrimSpace(strings.Join(current, "\n")); text != "" {
+			blocks = append(blocks, strings.Join(current, "\n"))
+		}
+		current = nil
+	}
+	for _, op := range Lines(a, b) {
+		if op.Kind == Insert {
+			current = append(current, op.Text)
+			continue
+		}
+		flush()
+	}
+	flush()
+	return blocks
+}
+
+// Unified renders a compact unified-style diff with the given context lines.
+func Unified(fromName, toName, a, b string, context int) string {
+	ops := Lines(a, b)
+
+	changed := false
+	for _, op := range ops {
+		if op.Kind != Equal {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	lastPrinted := -1
+	for idx, op := range ops {
+		if op.Kind == Equal && !nearChange(ops, idx, context) {
+			continue
+		}
+		if lastPrinted >= 0 && idx > lastPrinted+1 {
+			sb.WriteString("@@\n")
+		}
+		switch op.Kind {
+		case Equal:
+			sb.WriteString(" " + op.Text + "\n")
+		case Insert:
+			sb.WriteString("+" + op.Text + "\n")
+		case Delete:
+			sb.WriteString("-" + op.Text + "\n")
+		}
+		lastPrinted = idx
+	}
+	return sb.String()
+}
+
+func nearChange(ops []Op, idx, context int) bool {
+	for k := idx - context; k <= idx+context; k++ {
+		if k >= 0 && k < len(ops) && ops[k].Kind != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimRight(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,139 @@
+// Package envvars extracts the names of environment variables a project uses
+// from example env files and config files. Values are never read.
+package envvars
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Example env files listing the variables a project expects.
+var exampleFiles = []string{".env.example", ".env.dist", ".env.sample", ".env.template"}
+
+// Patterns of an env lookup in config files.
+var (
+	phpEnvCall = regexp.MustCompile(`\benv\(\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]`)
+	jsEnvRef   = regexp.MustCompile(`\bprocess\.env\.([A-Za-z_][A-Za-z0-9_]*)|\bprocess\.env\[\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]\s*\]`)
+	envName    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// JavaScript config files scanned for process.env references.
+var jsConfigFiles = []string{"nuxt.config.ts", "nuxt.config.js", "vite.config.ts", "vite.config.js"}
+
+// Source is a file and the variable names found in it.
+type Source struct {
+	Path  string   // relative to the scanned root
+	Names []string // sorted, unique
+}
+
+// Scan returns the env variable names found in root, per source file.
+// Files are listed in a stable order; files without variables are omitted.
+func Scan(root string) ([]Source, error) {
+	var sources []Source
+	add := func(rel string, names []string) {
+		if len(names) > 0 {
+			sources = append(sources, Source{Path: filepath.ToSlash(rel), Names: unique(names)})
+		}
+	}
+
+	for _, name := range exampleFiles {
+		names, err := readExampleFile(filepath.Join(root, name))
+		if err != nil {
+			return nil, err
+		}
+		add(name, names)
+	}
+
+	// Laravel style config/*.php
+	phpConfigs, err := filepath.Glob(filepath.Join(root, "config", "*.php"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(phpConfigs)
+	for _, path := range phpConfigs {
+		names, err := scanFile(path, phpEnvCall)
+		if err != nil {
+			return nil, err
+		}
+		rel, _ := filepath.Rel(root, path)
+		add(rel, names)
+	}
+
+	for _, name := range jsConfigFiles {
+		names, err := scanFile(filepath.Join(root, name), jsEnvRef)
+		if err != nil {
+			return nil, err
+		}
+		add(name, names)
+	}
+
+	return sources, nil
+}
+
+// readExampleFile returns the variable names of a dotenv file, skipping values and comments.
+func readExampleFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		name, _, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if name = strings.TrimSpace(name); envName.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	return names, scanner.Err()
+}
+
+// scanFile returns the names captured by pattern in a file (missing files yield nothing).
+func scanFile(path string, pattern *regexp.Regexp) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, m := range pattern.FindAllStringSubmatch(string(data), -1) {
+		for _, g := range m[1:] {
+			if g != "" {
+				names = append(names, g)
+			}
+		}
+	}
+	return names, nil
+}
+
+func unique(names []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,83 @@
+package format
+
+import (
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/markdown"
+)
+
+// asciidocRenderer renders AsciiDoc: markdown heading level n becomes a
+// section title with n equals signs, so "# Title" is the document title.
+type asciidocRenderer struct{}
+
+func (asciidocRenderer) Ext() string { return ".adoc" }
+
+func (asciidocRenderer) Comment(text string) string {
+	if strings.Contains(text, "\n") {
+		return "////\n" + text + "\n////"
+	}
+	return "// " + text
+}
+
+func (r asciidocRenderer) Render(doc markdown.Document) string {
+	return join(doc, func(b markdown.Block) string {
+		switch b.Kind {
+		case markdown.BlockHeading:
+			return strings.Repeat("=", b.Level) + " " + asciidocInline(b.Text)
+		case markdown.BlockListItem:
+			marker := "*"
+			if b.Ordered {
+				marker = "."
+			}
+			// Continuation lines are joined with "+" to stay in the item
+			return strings.Repeat(marker, b.Level) + " " + strings.ReplaceAll(asciidocInline(b.Text), "\n", " +\n")
+		case markdown.BlockCode:
+			header := "----"
+			if b.Lang != "" {
+				header = "[source," + b.Lang + "]\n----"
+			}
+			return header + "\n" + strings.Join(b.Lines, "\n") + "\n----"
+		case markdown.BlockQuote:
+			return "____\n" + asciidocInline(strings.Join(b.Lines, "\n")) + "\n____"
+		case markdown.BlockTable:
+			var t strings.Builder
+			t.WriteString("[options=\"header\"]\n|===")
+			for i, row := range b.Rows {
+				if i <= 1 {
+					t.WriteString("\n")
+				}
+				for _, cell := range row {
+					t.WriteString("\n| " + strings.ReplaceAll(asciidocInline(cell), "|", "\\|"))
+				}
+			}
+			t.WriteString("\n|===")
+			return t.String()
+		case markdown.BlockRule:
+			return "'''"
+		case markdown.BlockComment:
+			return r.Comment(b.Text)
+		}
+		return asciidocInline(b.Text)
+	})
+}
+
+// asciidocInline converts emphasis, links, images and code spans (as literal
+// monospace, so that their content is not formatted).
+func asciidocInline(text string) string {
+	return inline(text, func(s string) string {
+		s = imagePattern.ReplaceAllString(s, "image:$2[$1]")
+		s = linkPattern.ReplaceAllStringFunc(s, func(m string) string {
+			parts := linkPattern.FindStringSubmatch(m)
+			target := parts[2]
+			if !strings.Contains(target, ":") {
+				target = "link:" + relink(target, ".adoc")
+			}
+			return target + "[" + parts[1] + "]"
+		})
+		s = boldPattern.ReplaceAllString(s, "\x00$2\x00")
+		s = italicPattern.ReplaceAllString(s, "${1}_${2}_")
+		return strings.ReplaceAll(s, "\x00", "*")
+	}, func(code string) string {
+		return "`+" + code + "+`"
+	})
+}
@@ -0,0 +1,124 @@
+// Package format renders the generated markdown into the document formats
+// of documentation systems that do not consume markdown (AsciiDoc, plain
+// text). Every format renders the same block model (markdown.Parse).
+package format
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/markdown"
+)
+
+// Renderer renders a parsed markdown document in one output format.
+type Renderer interface {
+	// Ext is the file extension of the format, including the dot.
+	Ext() string
+	// Render returns the document in the format, ending with a newline when
+	// the markdown did.
+	Render(doc markdown.Document) string
+	// Comment returns text as a comment, or as plain text where the format
+	// has no comments.
+	Comment(text string) string
+}
+
+// Markdown is the default format; it keeps the source unchanged.
+const Markdown = "markdown"
+
+var renderers = map[string]Renderer{
+	Markdown:   markdownRenderer{},
+	"asciidoc": asciidocRenderer{},
+	"txt":      textRenderer{},
+}
+
+// Names lists the supported formats.
+var Names = []string{Markdown, "asciidoc", "txt"}
+
+// Lookup returns the renderer of a format ("" is markdown).
+func Lookup(name string) (Renderer, error) {
+	if name == "" {
+		name = Markdown
+	}
+	r, ok := renderers[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown format '%s' (available: %s)", name, strings.Join(Names, ", "))
+	}
+	return r, nil
+}
+
+// Render converts markdown content with r.
+func Render(r Renderer, md string) string {
+	return r.Render(markdown.Parse(md))
+}
+
+// Path replaces the .md extension of a markdown file path with the
+// extension of r.
+func Path(r Renderer, path string) string {
+	return strings.TrimSuffix(path, ".md") + r.Ext()
+}
+
+var markdownLinkPattern = regexp.MustCompile(`^([^#:]*)\.md(#.*)?$`)
+
+// relink points a relative link to a markdown file at the file converted to ext.
+func relink(target, ext string) string {
+	return markdownLinkPattern.ReplaceAllString(target, "${1}"+ext+"${2}")
+}
+
+// join renders the blocks with fn and separates them by blank lines, except
+// between consecutive list items. Blocks rendered empty are left out.
+func join(doc markdown.Document, fn func(markdown.Block) string) string {
+	var b strings.Builder
+	var previous markdown.BlockKind
+	for _, block := range doc.Blocks {
+		text := fn(block)
+		if text == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			if block.Kind == markdown.BlockListItem && previous == markdown.BlockListItem {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(text)
+		previous = block.Kind
+	}
+	if b.Len() > 0 && strings.HasSuffix(doc.Source, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+var (
+	codeSpanPattern = regexp.MustCompile("`[^`]+`")
+	imagePattern    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	linkPattern     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	boldPattern     = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	italicPattern   = regexp.MustCompile(`(^|[^*\w])[*_]([^*_\s][^*_]*)[*_]`)
+)
+
+// inline applies fn to text with its code spans set aside, then puts back
+// the spans rendered by code (which receives the span without backticks).
+func inline(text string, fn, code func(string) string) string {
+	var spans []string
+	text = codeSpanPattern.ReplaceAllStringFunc(text, func(m string) string {
+		spans = append(spans, code(m[1:len(m)-1]))
+		return "\x01" + strconv.Itoa(len(spans)-1) + "\x01"
+	})
+	text = fn(text)
+	for i, span := range spans {
+		text = strings.Replace(text, "\x01"+strconv.Itoa(i)+"\x01", span, 1)
+	}
+	return text
+}
+
+type markdownRenderer struct{}
+
+func (markdownRenderer) Ext() string { return ".md" }
+
+func (markdownRenderer) Render(doc markdown.Document) string { return doc.Source }
+
+func (markdownRenderer) Comment(text string) string { return "<!-- " + text + " -->" }
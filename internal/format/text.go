@@ -0,0 +1,94 @@
+package format
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cego/ai-instructions/internal/markdown"
+)
+
+// textRenderer renders plain text: headings are underlined, lists keep
+// their markers, code is indented and markup is removed.
+type textRenderer struct{}
+
+func (textRenderer) Ext() string { return ".txt" }
+
+func (textRenderer) Comment(text string) string { return text }
+
+func (textRenderer) Render(doc markdown.Document) string {
+	// Ordered items are numbered per list level
+	var numbers []int
+	return join(doc, func(b markdown.Block) string {
+		if b.Kind != markdown.BlockListItem {
+			numbers = nil
+		} else {
+			for len(numbers) < b.Level {
+				numbers = append(numbers, 0)
+			}
+			numbers = numbers[:b.Level]
+			numbers[b.Level-1]++
+		}
+
+		switch b.Kind {
+		case markdown.BlockHeading:
+			text := textInline(b.Text)
+			underline := "-"
+			if b.Level <= 2 {
+				underline = "="
+			}
+			return text + "\n" + strings.Repeat(underline, len([]rune(text)))
+		case markdown.BlockListItem:
+			indent := strings.Repeat("  ", b.Level-1)
+			marker := "- "
+			if b.Ordered {
+				marker = strconv.Itoa(numbers[b.Level-1]) + ". "
+			}
+			return indent + marker + strings.ReplaceAll(textInline(b.Text), "\n", "\n"+indent+"  ")
+		case markdown.BlockCode:
+			lines := make([]string, len(b.Lines))
+			for i, line := range b.Lines {
+				if line != "" {
+					lines[i] = "    " + line
+				}
+			}
+			return strings.Join(lines, "\n")
+		case markdown.BlockQuote:
+			return "  " + strings.ReplaceAll(textInline(strings.Join(b.Lines, "\n")), "\n", "\n  ")
+		case markdown.BlockTable:
+			rows := make([]string, len(b.Rows))
+			for i, row := range b.Rows {
+				cells := make([]string, len(row))
+				for j, cell := range row {
+					cells[j] = textInline(cell)
+				}
+				rows[i] = strings.Join(cells, " | ")
+			}
+			return strings.Join(rows, "\n")
+		case markdown.BlockRule:
+			return strings.Repeat("-", 40)
+		case markdown.BlockComment:
+			return ""
+		}
+		return textInline(b.Text)
+	})
+}
+
+// textInline removes emphasis and code markers; links keep their text and
+// target, images their alt text.
+func textInline(text string) string {
+	return inline(text, func(s string) string {
+		s = imagePattern.ReplaceAllString(s, "$1")
+		s = linkPattern.ReplaceAllStringFunc(s, func(m string) string {
+			parts := linkPattern.FindStringSubmatch(m)
+			target := relink(parts[2], ".txt")
+			if parts[1] == parts[2] {
+				return target
+			}
+			return parts[1] + " (" + target + ")"
+		})
+		s = boldPattern.ReplaceAllString(s, "$2")
+		return italicPattern.ReplaceAllString(s, "$1$2")
+	}, func(code string) string {
+		return code
+	})
+}
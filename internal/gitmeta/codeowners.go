@@ -0,0 +1,64 @@
+package gitmeta
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OwnerRule is a CODEOWNERS line: the owners of the paths matching Pattern.
+// A rule without owners leaves the paths unowned.
+type OwnerRule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners,omitempty"`
+}
+
+// codeOwnersFiles are the locations GitHub and GitLab read, in order.
+var codeOwnersFiles = []string{
+	filepath.Join(".github", "CODEOWNERS"),
+	"CODEOWNERS",
+	filepath.Join("docs", "CODEOWNERS"),
+}
+
+// CodeOwners reads the first CODEOWNERS file of projectRoot and returns its
+// path (relative to projectRoot) and rules in file order; the last matching
+// rule wins. The path is "" when there is no CODEOWNERS file.
+func CodeOwners(projectRoot string) (string, []OwnerRule, error) {
+	for _, name := range codeOwnersFiles {
+		data, err := os.ReadFile(filepath.Join(projectRoot, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", nil, err
+		}
+		rules, err := ParseCodeOwners(data)
+		return filepath.ToSlash(name), rules, err
+	}
+	return "", nil, nil
+}
+
+// ParseCodeOwners parses CODEOWNERS content. Comments, blank lines and
+// GitLab section headers ([Section] or ^[Section]) are skipped.
+func ParseCodeOwners(data []byte) ([]OwnerRule, error) {
+	var rules []OwnerRule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") ||
+			strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+		rules = append(rules, OwnerRule{
+			Pattern: strings.ReplaceAll(fields[0], `\ `, " "),
+			Owners:  fields[1:],
+		})
+	}
+	return rules, scanner.Err()
+}
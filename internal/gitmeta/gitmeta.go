@@ -0,0 +1,174 @@
+// Package gitmeta reads repository metadata straight from the .git directory
+// and the files git hosts interpret (CODEOWNERS), without running git.
+package gitmeta
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Repo is the git metadata of a checkout.
+type Repo struct {
+	// Dir is the common git directory (config, refs); for worktrees it
+	// differs from WorktreeDir, which holds the worktree's own HEAD.
+	Dir         string
+	WorktreeDir string
+}
+
+// Open returns the repository checked out in projectRoot (nil when it is not
+// one), following the "gitdir:" file of worktrees and submodules.
+func Open(projectRoot string) (*Repo, error) {
+	path := filepath.Join(projectRoot, ".git")
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if info.IsDir() {
+		return &Repo{Dir: path, WorktreeDir: path}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return nil, nil
+	}
+	dir = strings.TrimSpace(dir)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(projectRoot, dir)
+	}
+	repo := &Repo{Dir: dir, WorktreeDir: dir}
+	// Worktrees keep the config and remote refs in the common directory
+	if common, err := os.ReadFile(filepath.Join(dir, "commondir")); err == nil {
+		c := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(c) {
+			c = filepath.Join(dir, c)
+		}
+		repo.Dir = c
+	}
+	return repo, nil
+}
+
+// ConfigPath is the path of the repository config.
+func (r *Repo) ConfigPath() string {
+	return filepath.Join(r.Dir, "config")
+}
+
+// OriginHeadPath is the symbolic ref naming the primary branch of origin.
+func (r *Repo) OriginHeadPath() string {
+	return filepath.Join(r.Dir, "refs", "remotes", "origin", "HEAD")
+}
+
+// OriginURL returns the url of [remote "origin"] ("" when there is none).
+func (r *Repo) OriginURL() (string, error) {
+	f, err := os.Open(r.ConfigPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer f.Close()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// DefaultBranch returns the branch origin/HEAD points to ("" when the clone
+// has no origin/HEAD, e.g. most CI checkouts).
+func (r *Repo) DefaultBranch() (string, error) {
+	data, err := os.ReadFile(r.OriginHeadPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	branch, _ := strings.CutPrefix(strings.TrimSpace(string(data)), "ref: refs/remotes/origin/")
+	if branch == strings.TrimSpace(string(data)) {
+		return "", nil
+	}
+	return branch, nil
+}
+
+// CurrentBranch returns the checked out branch ("" when HEAD is detached).
+func (r *Repo) CurrentBranch() (string, error) {
+	// HEAD is per worktree, so read it from the worktree's own git directory
+	data, err := os.ReadFile(filepath.Join(r.WorktreeDir, "HEAD"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	branch, _ := strings.CutPrefix(strings.TrimSpace(string(data)), "ref: refs/heads/")
+	if branch == strings.TrimSpace(string(data)) {
+		return "", nil
+	}
+	return branch, nil
+}
+
+// CurrentBranch returns the branch checked out in projectRoot ("" when HEAD is
+// detached or projectRoot is not a git repository).
+func CurrentBranch(projectRoot string) (string, error) {
+	repo, err := Open(projectRoot)
+	if err != nil || repo == nil {
+		return "", err
+	}
+	return repo.CurrentBranch()
+}
+
+// NormalizeRemoteURL turns ssh and scp-like remotes into https URLs and drops
+// credentials and the .git suffix, so every clone yields the same URL.
+func NormalizeRemoteURL(remote string) string {
+	if remote == "" {
+		return ""
+	}
+	// scp-like syntax: git@github.com:org/repo.git
+	if !strings.Contains(remote, "://") {
+		host, path, ok := strings.Cut(remote, ":")
+		if !ok || strings.Contains(host, "/") {
+			return "" // a local path
+		}
+		if _, h, ok := strings.Cut(host, "@"); ok {
+			host = h
+		}
+		remote = "https://" + host + "/" + strings.TrimPrefix(path, "/")
+	}
+	u, err := url.Parse(remote)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	switch u.Scheme {
+	case "ssh", "git", "git+ssh":
+		u.Scheme = "https"
+		u.Host = u.Hostname()
+	case "http", "https":
+	default:
+		return ""
+	}
+	u.User = nil
+	u.Path = strings.TrimSuffix(u.Path, ".git")
+	return u.String()
+}
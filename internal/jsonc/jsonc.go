@@ -0,0 +1,99 @@
+// Package jsonc parses JSON with comments and trailing commas, as found in
+// tsconfig.json, devcontainer.json and other config-style files.
+package jsonc
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+var bom = []byte("\xef\xbb\xbf")
+
+// Standardize returns data as plain JSON: comments, trailing commas and a
+// leading byte order mark are replaced by spaces. Newlines and byte offsets
+// are kept, so JSON errors point at the right line of the original file.
+func Standardize(data []byte) []byte {
+	out := bytes.Clone(data)
+	if bytes.HasPrefix(out, bom) {
+		blank(out[:len(bom)])
+	}
+
+	// First pass: comments
+	inString := false
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			end := bytes.IndexByte(out[i:], '\n')
+			if end < 0 {
+				end = len(out) - i
+			}
+			blank(out[i : i+end])
+			i += end
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			end := bytes.Index(out[i+2:], []byte("*/"))
+			if end < 0 {
+				end = len(out) - i
+			} else {
+				end += 4
+			}
+			blank(out[i : i+end])
+			i += end - 1
+		}
+	}
+
+	// Second pass: trailing commas (comments are blank by now)
+	inString = false
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case ',':
+			j := i + 1
+			for j < len(out) && isSpace(out[j]) {
+				j++
+			}
+			if j < len(out) && (out[j] == '}' || out[j] == ']') {
+				out[i] = ' '
+			}
+		}
+	}
+	return out
+}
+
+// Unmarshal parses JSONC data into v (see Standardize).
+func Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(Standardize(data), v)
+}
+
+// blank replaces everything but newlines by spaces.
+func blank(b []byte) {
+	for i, c := range b {
+		if c != '\n' && c != '\r' {
+			b[i] = ' '
+		}
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
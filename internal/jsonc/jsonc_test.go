@@ -0,0 +1,148 @@
+package jsonc
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestStandardize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "line comments",
+			in:   "{\n  // the target\n  \"target\": \"ES2022\" // trailing\n}",
+			want: "{\n               \n  \"target\": \"ES2022\"            \n}",
+		},
+		{
+			name: "block comments keep newlines",
+			in:   "{/* a\nb */\"a\": 1}",
+			want: "{    \n    \"a\": 1}",
+		},
+		{
+			name: "comment markers inside strings",
+			in:   `{"url": "https://example.com/*x*/", "glob": "src/**/*.ts"}`,
+			want: `{"url": "https://example.com/*x*/", "glob": "src/**/*.ts"}`,
+		},
+		{
+			name: "escaped quotes inside strings",
+			in:   `{"a": "say \"// hi\"", "b": 1 // c` + "\n}",
+			want: `{"a": "say \"// hi\"", "b": 1     ` + "\n}",
+		},
+		{
+			name: "unterminated block comment",
+			in:   "{\"a\": 1}\n/* never closed\n",
+			want: "{\"a\": 1}\n               \n",
+		},
+		{
+			name: "trailing commas",
+			in:   "{\"a\": [1, 2,], \"b\": {\"c\": true,\n},\n}",
+			want: "{\"a\": [1, 2 ], \"b\": {\"c\": true \n} \n}",
+		},
+		{
+			name: "trailing comma before a comment",
+			in:   "[1, // last\n]",
+			want: "[1         \n]",
+		},
+		{
+			name: "commas inside strings",
+			in:   `{"a": ",]", "b": ",}"}`,
+			want: `{"a": ",]", "b": ",}"}`,
+		},
+		{
+			name: "byte order mark",
+			in:   "\xef\xbb\xbf{\"a\": 1}",
+			want: "   {\"a\": 1}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(Standardize([]byte(tt.in)))
+			if got != tt.want {
+				t.Errorf("Standardize() =\n%q\nwant\n%q", got, tt.want)
+			}
+			if len(got) != len(tt.in) {
+				t.Errorf("Standardize() changed the length from %d to %d", len(tt.in), len(got))
+			}
+		})
+	}
+}
+
+func TestStandardizeKeepsInput(t *testing.T) {
+	in := []byte("{// c\n}")
+	Standardize(in)
+	if string(in) != "{// c\n}" {
+		t.Errorf("Standardize() modified its input: %q", in)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var v struct {
+		CompilerOptions struct {
+			Strict bool     `json:"strict"`
+			Paths  []string `json:"paths"`
+		} `json:"compilerOptions"`
+	}
+	data := "\xef\xbb\xbf{\n  // tsconfig\n  \"compilerOptions\": {\n    \"strict\": true, /* always */\n    \"paths\": [\"src/*\",],\n  },\n}\n"
+	if err := Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !v.CompilerOptions.Strict || len(v.CompilerOptions.Paths) != 1 || v.CompilerOptions.Paths[0] != "src/*" {
+		t.Errorf("Unmarshal() = %+v", v)
+	}
+}
+
+func TestUnmarshalErrorPointsAtOriginalOffset(t *testing.T) {
+	data := "{\n  // comment\n  \"a\": ?\n}"
+	var v any
+	err := Unmarshal([]byte(data), &v)
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("Unmarshal() error = %v, want a syntax error", err)
+	}
+	if got := data[syntaxErr.Offset-1]; got != '?' {
+		t.Errorf("error offset %d points at %q, want '?'", syntaxErr.Offset, got)
+	}
+}
+
+func TestParseObjectKeepsOrder(t *testing.T) {
+	data := "{\n\t// dev container\n\t\"name\": \"app\",\n\t\"image\": \"node\",\n\t\"customizations\": {\"vscode\": {\"extensions\": [\"a\",]}},\n}"
+	o, err := ParseObject([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseObject() error = %v", err)
+	}
+	o.Object("customizations").Object("vscode").Set("settings", NewObject())
+	o.Set("features", map[string]any{})
+
+	got, err := o.Indent("\t")
+	if err != nil {
+		t.Fatalf("Indent() error = %v", err)
+	}
+	want := "{\n\t\"name\": \"app\",\n\t\"image\": \"node\",\n\t\"customizations\": {\n\t\t\"vscode\": {\n\t\t\t\"extensions\": [\n\t\t\t\t\"a\"\n\t\t\t],\n\t\t\t\"settings\": {}\n\t\t}\n\t},\n\t\"features\": {}\n}\n"
+	if string(got) != want {
+		t.Errorf("Indent() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseObjectRejectsNonObjects(t *testing.T) {
+	for _, data := range []string{"[1]", `"a"`, "{} {}", "{\"a\": 1"} {
+		if _, err := ParseObject([]byte(data)); err == nil {
+			t.Errorf("ParseObject(%q) succeeded", data)
+		}
+	}
+}
+
+func TestMarshalDoesNotEscapeHTML(t *testing.T) {
+	o := NewObject()
+	o.Set("cmd", "a && b > c")
+	got, err := o.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"cmd":"a && b > c"}`; string(got) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+package links
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Link is a markdown link (or image) found in rule content.
+type Link struct {
+	Target string
+	Line   int
+}
+
+// Problem describes a link that could not be verified.
+type Problem struct {
+	Rule   string
+	Link   Link
+	Reason string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("rules/%s.md:%d: %s: %s", p.Rule, p.Link.Line, p.Link.Target, p.Reason)
+}
+
+// Options controls how links are verified.
+type Options struct {
+	// ProjectRoot is used to resolve relative links to project files.
+	ProjectRoot string
+	// RuleExists reports whether a rule identifier (without .md) exists.
+	RuleExists func(id string) bool
+	// AssetExists reports whether an embedded asset (relative path) exists.
+	AssetExists func(name string) bool
+	// HTTP enables HEAD requests against external links.
+	HTTP bool
+	// Client is used for HTTP checks (defaults to a client with a short timeout).
+	Client *http.Client
+}
+
+var linkPattern = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+
+// Extract returns all inline links in the markdown, skipping fenced code blocks.
+func Extract(markdown string) []Link {
+	var out []Link
+	inFence := false
+	for i, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, m := range linkPattern.FindAllStringSubmatch(line, -1) {
+			out = append(out, Link{Target: m[1], Line: i + 1})
+		}
+	}
+	return out
+}
+
+// Check verifies all links in the content of the given rule.
+func Check(ruleID, content string, opts Options) []Problem {
+	var problems []Problem
+	for _, l := range Extract(content) {
+		if reason := checkLink(ruleID, l.Target, opts); reason != "" {
+			problems = append(problems, Problem{Rule: ruleID, Link: l, Reason: reason})
+		}
+	}
+	return problems
+}
+
+func checkLink(ruleID, target string, opts Options) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "malformed link: " + err.Error()
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return "malformed external link: missing host"
+		}
+		if opts.HTTP {
+			return checkHTTP(target, opts.Client)
+		}
+		return ""
+	case "mailto", "tel":
+		return ""
+	case "":
+		// relative link, handled below
+	default:
+		return "unsupported link scheme '" + u.Scheme + "'"
+	}
+
+	// Pure anchors point into the generated document itself.
+	if u.Path == "" {
+		return ""
+	}
+
+	// Another rule, relative to the linking rule
+	if strings.HasSuffix(u.Path, ".md") && opts.RuleExists != nil {
+		id := strings.TrimSuffix(path.Join(path.Dir(ruleID), u.Path), ".md")
+		if opts.RuleExists(id) {
+			return ""
+		}
+	}
+
+	// An asset stored alongside the rule
+	if opts.AssetExists != nil && opts.AssetExists(path.Join(path.Dir(ruleID), u.Path)) {
+		return ""
+	}
+
+	// A project file, relative to the project root
+	root := opts.ProjectRoot
+	if root == "" {
+		root = "."
+	}
+	if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(u.Path, "/")))); err == nil {
+		return ""
+	}
+
+	return "does not resolve to a rule, asset or project file"
+}
+
+func checkHTTP(target string, client *http.Client) string {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	resp, err := client.Head(target)
+	if err == nil && resp.StatusCode == http.StatusMethodNotAllowed {
+		// Some servers refuse HEAD; fall back to GET
+		resp.Body.Close()
+		resp, err = client.Get(target)
+	}
+	if err != nil {
+		return "request failed: " + err.Error()
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Sprintf("returned HTTP %d", resp.StatusCode)
+	}
+	return ""
+}
@@ -0,0 +1,178 @@
+package markdown
+
+import (
+	"strings"
+)
+
+// BlockKind is the type of a top-level markdown block.
+type BlockKind int
+
+const (
+	BlockParagraph BlockKind = iota
+	BlockHeading
+	// BlockListItem is one item of a bullet or ordered list, with its
+	// continuation lines.
+	BlockListItem
+	BlockCode
+	BlockQuote
+	BlockTable
+	BlockRule
+	BlockComment
+)
+
+// Block is one block of a markdown document, the model the other output
+// formats are rendered from.
+type Block struct {
+	Kind BlockKind
+	// Level is the heading level, or the nesting depth (from 1) of a list item.
+	Level int
+	// Ordered marks items of numbered lists.
+	Ordered bool
+	// Lang is the info string of fenced code.
+	Lang string
+	// Text is the heading, paragraph or list item text (lines joined by
+	// newlines, without markers), or the comment text.
+	Text string
+	// Lines are the code lines or the quoted lines (without "> ").
+	Lines []string
+	// Rows are the table rows split into trimmed cells, without the
+	// delimiter row.
+	Rows [][]string
+}
+
+// Document is a parsed markdown document. Source is kept so that markdown
+// output stays byte-for-byte identical.
+type Document struct {
+	Source string
+	Blocks []Block
+}
+
+// Parse splits md into blocks: ATX headings, paragraphs, list items, fenced
+// code, quotes, pipe tables, horizontal rules and HTML comments.
+func Parse(md string) Document {
+	doc := Document{Source: md}
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	var listIndents []int
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if !isListItem(trimmed) && indentOf(line) == 0 {
+			listIndents = nil
+		}
+
+		switch {
+		case isFence(line):
+			// Code nested in a list item loses the item's indentation
+			fence, indent := trimmed[:3], line[:indentOf(line)]
+			b := Block{Kind: BlockCode, Lang: strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1]))}
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				b.Lines = append(b.Lines, strings.TrimPrefix(lines[i], indent))
+			}
+			doc.Blocks = append(doc.Blocks, b)
+
+		case strings.HasPrefix(trimmed, "<!--"):
+			text := trimmed
+			for !strings.Contains(text, "-->") && i+1 < len(lines) {
+				i++
+				text += "\n" + lines[i]
+			}
+			text = strings.TrimSuffix(strings.TrimPrefix(text, "<!--"), "-->")
+			doc.Blocks = append(doc.Blocks, Block{Kind: BlockComment, Text: strings.TrimSpace(text)})
+
+		case HeadingLevel(line) > 0:
+			doc.Blocks = append(doc.Blocks, Block{Kind: BlockHeading, Level: HeadingLevel(line), Text: HeadingText(line)})
+
+		case trimmed == "---" || trimmed == "***" || trimmed == "___":
+			doc.Blocks = append(doc.Blocks, Block{Kind: BlockRule})
+
+		case isListItem(trimmed):
+			indent := indentOf(line)
+			for len(listIndents) > 0 && listIndents[len(listIndents)-1] > indent {
+				listIndents = listIndents[:len(listIndents)-1]
+			}
+			if len(listIndents) == 0 || listIndents[len(listIndents)-1] < indent {
+				listIndents = append(listIndents, indent)
+			}
+			b := Block{Kind: BlockListItem, Level: len(listIndents), Ordered: orderedPattern.MatchString(trimmed)}
+			text := []string{stripListMarker(trimmed)}
+			// Continuation lines are indented beyond the marker
+			for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" && indentOf(lines[i+1]) > indent &&
+				!isListItem(strings.TrimSpace(lines[i+1])) && !isFence(lines[i+1]) {
+				i++
+				text = append(text, strings.TrimSpace(lines[i]))
+			}
+			b.Text = strings.Join(text, "\n")
+			doc.Blocks = append(doc.Blocks, b)
+
+		case strings.HasPrefix(trimmed, ">"):
+			b := Block{Kind: BlockQuote}
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quoted := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				b.Lines = append(b.Lines, strings.TrimPrefix(quoted, " "))
+			}
+			i--
+			doc.Blocks = append(doc.Blocks, b)
+
+		case strings.HasPrefix(trimmed, "|"):
+			b := Block{Kind: BlockTable}
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				row := tableCells(lines[i])
+				if !isDelimiterRow(row) {
+					b.Rows = append(b.Rows, row)
+				}
+			}
+			i--
+			doc.Blocks = append(doc.Blocks, b)
+
+		default:
+			text := []string{trimmed}
+			for i+1 < len(lines) && continuesParagraph(lines[i+1]) {
+				i++
+				text = append(text, strings.TrimSpace(lines[i]))
+			}
+			doc.Blocks = append(doc.Blocks, Block{Kind: BlockParagraph, Text: strings.Join(text, "\n")})
+		}
+	}
+	return doc
+}
+
+// continuesParagraph reports whether line belongs to the paragraph above it.
+func continuesParagraph(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed != "" && !isFence(line) && HeadingLevel(line) == 0 && !isListItem(trimmed) &&
+		!strings.HasPrefix(trimmed, ">") && !strings.HasPrefix(trimmed, "|") && !strings.HasPrefix(trimmed, "<!--") &&
+		trimmed != "---" && trimmed != "***" && trimmed != "___"
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+func stripListMarker(trimmed string) string {
+	if loc := orderedPattern.FindStringIndex(trimmed); loc != nil {
+		return trimmed[loc[1]:]
+	}
+	return trimmed[2:]
+}
+
+func tableCells(line string) []string {
+	line = strings.Trim(strings.TrimSpace(line), "|")
+	cells := strings.Split(line, "|")
+	for i, c := range cells {
+		cells[i] = strings.TrimSpace(c)
+	}
+	return cells
+}
+
+func isDelimiterRow(cells []string) bool {
+	for _, c := range cells {
+		if strings.Trim(c, ":-") != "" || c == "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package markdown
+
+import "strings"
+
+// block is a run of non-blank lines (fenced code may contain blank lines).
+type block struct {
+	start, end int // [start, end) line range, excluding the blank lines after it
+	next       int // first line of the following block
+}
+
+// splitBlocks returns the paragraphs, list runs, headings and code blocks of lines.
+func splitBlocks(lines []string) []block {
+	var blocks []block
+	inFence := false
+	start := -1
+	for i, line := range lines {
+		blank := strings.TrimSpace(line) == ""
+		if isFence(line) {
+			inFence = !inFence
+		}
+		switch {
+		case start < 0 && !blank:
+			start = i
+		case start >= 0 && blank && !inFence:
+			blocks = append(blocks, block{start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		blocks = append(blocks, block{start: start, end: len(lines)})
+	}
+	for i := range blocks {
+		if i+1 < len(blocks) {
+			blocks[i].next = blocks[i+1].start
+		} else {
+			blocks[i].next = len(lines)
+		}
+	}
+	return blocks
+}
+
+// DedupeParagraphs removes paragraphs (and list runs) that repeat an earlier
+// one word for word, as happens when merged rules share boilerplate. Headings,
+// code blocks, comments, horizontal rules and paragraphs shorter than minLen
+// are kept. A heading whose section became empty by the removal is dropped
+// too, and so is a horizontal rule left directly after another one. It
+// returns the number of paragraphs removed.
+func DedupeParagraphs(md string, minLen int) (string, int) {
+	lines := strings.Split(md, "\n")
+	blocks := splitBlocks(lines)
+
+	seen := map[string]bool{}
+	removed := make([]bool, len(blocks))
+	count := 0
+	for i, b := range blocks {
+		text := normalizeBlock(lines[b.start:b.end])
+		if !dedupable(lines[b.start:b.end], text, minLen) {
+			continue
+		}
+		if seen[text] {
+			removed[i] = true
+			count++
+			continue
+		}
+		seen[text] = true
+	}
+	if count == 0 {
+		return md, 0
+	}
+
+	// Drop headings emptied by the removal (a heading alone in its block,
+	// followed by removed blocks up to the next heading, rule or the end)
+	for i := len(blocks) - 1; i >= 0; i-- {
+		b := blocks[i]
+		if removed[i] || b.end-b.start != 1 || HeadingLevel(lines[b.start]) == 0 {
+			continue
+		}
+		emptied, j := false, i+1
+		for ; j < len(blocks) && removed[j]; j++ {
+			emptied = true
+		}
+		if emptied && (j == len(blocks) || blockBoundary(lines, blocks[j], HeadingLevel(lines[b.start]))) {
+			removed[i] = true
+		}
+	}
+
+	// Collapse horizontal rules that became adjacent
+	lastRule := false
+	for i, b := range blocks {
+		if removed[i] {
+			continue
+		}
+		rule := b.end-b.start == 1 && isRule(lines[b.start])
+		if rule && lastRule {
+			removed[i] = true
+		}
+		lastRule = rule
+	}
+
+	var out []string
+	for i, b := range blocks {
+		if i == 0 {
+			out = append(out, lines[:b.start]...)
+		}
+		if !removed[i] {
+			out = append(out, lines[b.start:b.next]...)
+		}
+	}
+	return strings.TrimRight(strings.Join(out, "\n"), "\n") + trailingNewlines(md), count
+}
+
+// blockBoundary reports whether b ends the section of a heading at level.
+func blockBoundary(lines []string, b block, level int) bool {
+	first := lines[b.start]
+	if l := HeadingLevel(first); l > 0 && l <= level {
+		return true
+	}
+	return b.end-b.start == 1 && isRule(first)
+}
+
+func dedupable(lines []string, text string, minLen int) bool {
+	if len(text) < minLen {
+		return false
+	}
+	for _, line := range lines {
+		if HeadingLevel(line) > 0 || isFence(line) {
+			return false
+		}
+	}
+	if len(lines) == 1 && isRule(lines[0]) {
+		return false
+	}
+	return !(strings.HasPrefix(text, "<!--") && strings.HasSuffix(text, "-->"))
+}
+
+// normalizeBlock makes blocks that differ only in whitespace compare equal:
+// runs of spaces and tabs within a line count as one and trailing ones are
+// dropped, while the indentation (list nesting) is kept.
+func normalizeBlock(lines []string) string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		body := strings.TrimLeft(line, " \t")
+		out[i] = line[:len(line)-len(body)] + strings.Join(strings.Fields(body), " ")
+	}
+	return strings.Join(out, "\n")
+}
+
+func isRule(line string) bool {
+	switch strings.TrimSpace(line) {
+	case "---", "***", "___":
+		return true
+	}
+	return false
+}
+
+func trailingNewlines(s string) string {
+	return s[len(strings.TrimRight(s, "\n")):]
+}
@@ -0,0 +1,99 @@
+package markdown
+
+import "testing"
+
+func TestDedupeParagraphs(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		removed int
+	}{
+		{
+			name: "duplicate paragraphs across rules",
+			in: "# Laravel\n\nAlways run the test suite before committing.\n\n" +
+				"---\n\n# Laravel 11\n\nUse the slim skeleton.\n\nAlways run the test suite before committing.\n",
+			want: "# Laravel\n\nAlways run the test suite before committing.\n\n" +
+				"---\n\n# Laravel 11\n\nUse the slim skeleton.\n",
+			removed: 1,
+		},
+		{
+			name:    "duplicate list runs",
+			in:      "- Use strict types.\n- Prefer final classes.\n\nText.\n\n- Use strict types.\n- Prefer final classes.\n",
+			want:    "- Use strict types.\n- Prefer final classes.\n\nText.\n",
+			removed: 1,
+		},
+		{
+			name:    "near-duplicates differing only in whitespace",
+			in:      "Validate   input at the\tboundary.  \n\nValidate input at the boundary.\n",
+			want:    "Validate   input at the\tboundary.  \n",
+			removed: 1,
+		},
+		{
+			name:    "different indentation is not whitespace noise",
+			in:      "- Outer item.\n  - Nested item.\n\n- Outer item.\n- Nested item.\n",
+			want:    "- Outer item.\n  - Nested item.\n\n- Outer item.\n- Nested item.\n",
+			removed: 0,
+		},
+		{
+			name: "code blocks are never merged",
+			in: "```php\n$user->save();\n\n$user->refresh();\n```\n\n" +
+				"```php\n$user->save();\n\n$user->refresh();\n```\n",
+			want: "```php\n$user->save();\n\n$user->refresh();\n```\n\n" +
+				"```php\n$user->save();\n\n$user->refresh();\n```\n",
+			removed: 0,
+		},
+		{
+			name:    "paragraphs inside code blocks do not count as seen",
+			in:      "```\nKeep controllers thin.\n```\n\nKeep controllers thin.\n",
+			want:    "```\nKeep controllers thin.\n```\n\nKeep controllers thin.\n",
+			removed: 0,
+		},
+		{
+			name: "headings are kept in order",
+			in: "# General\n\nShared paragraph.\n\n## Testing\n\nWrite feature tests.\n\n" +
+				"# Specific\n\nOwn paragraph.\n\n## Testing\n\nWrite unit tests.\n",
+			want: "# General\n\nShared paragraph.\n\n## Testing\n\nWrite feature tests.\n\n" +
+				"# Specific\n\nOwn paragraph.\n\n## Testing\n\nWrite unit tests.\n",
+			removed: 0,
+		},
+		{
+			name: "heading emptied by the removal is dropped",
+			in: "# General\n\nShared paragraph.\n\n---\n\n# Specific\n\n## Shared\n\nShared paragraph.\n\n" +
+				"## Own\n\nOwn paragraph.\n",
+			want: "# General\n\nShared paragraph.\n\n---\n\n# Specific\n\n" +
+				"## Own\n\nOwn paragraph.\n",
+			removed: 1,
+		},
+		{
+			name:    "horizontal rules left adjacent are collapsed",
+			in:      "Shared paragraph.\n\n---\n\nShared paragraph.\n\n---\n\nEnd.\n",
+			want:    "Shared paragraph.\n\n---\n\nEnd.\n",
+			removed: 1,
+		},
+		{
+			name:    "comments are kept",
+			in:      "<!-- generated -->\n\n<!-- generated -->\n",
+			want:    "<!-- generated -->\n\n<!-- generated -->\n",
+			removed: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, removed := DedupeParagraphs(tt.in, 0)
+			if got != tt.want {
+				t.Errorf("DedupeParagraphs() =\n%q\nwant\n%q", got, tt.want)
+			}
+			if removed != tt.removed {
+				t.Errorf("DedupeParagraphs() removed %d, want %d", removed, tt.removed)
+			}
+		})
+	}
+}
+
+func TestDedupeParagraphsMinLen(t *testing.T) {
+	in := "Yes.\n\nYes.\n"
+	if got, removed := DedupeParagraphs(in, 10); got != in || removed != 0 {
+		t.Errorf("short paragraphs were deduplicated: %q (%d removed)", got, removed)
+	}
+}
@@ -0,0 +1,153 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	inlineCodePattern = regexp.MustCompile("`([^`]+)`")
+	imagePattern      = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	linkPattern       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	boldPattern       = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	italicPattern     = regexp.MustCompile(`(^|[^*])\*([^*\s][^*]*)\*`)
+	orderedPattern    = regexp.MustCompile(`^\d+[.)] `)
+)
+
+// ToHTML renders a practical subset of markdown (headings, paragraphs, nested
+// lists, fenced code, quotes, rules, tables as text, inline formatting) to HTML.
+func ToHTML(md string) string {
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+
+	var b strings.Builder
+	var para []string
+	var listStack []string // open list tags ("ul"/"ol")
+	var listIndent []int
+
+	flushPara := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>" + inline(strings.Join(para, " ")) + "</p>\n")
+			para = nil
+		}
+	}
+	closeLists := func(toDepth int) {
+		for len(listStack) > toDepth {
+			b.WriteString("</li></" + listStack[len(listStack)-1] + ">\n")
+			listStack = listStack[:len(listStack)-1]
+			listIndent = listIndent[:len(listIndent)-1]
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case isFence(line):
+			flushPara()
+			closeLists(0)
+			lang := strings.TrimLeft(trimmed, "`~")
+			var code []string
+			for i+1 < len(lines) && !isFence(lines[i+1]) {
+				i++
+				code = append(code, lines[i])
+			}
+			i++ // closing fence
+			class := ""
+			if lang != "" {
+				class = ` class="language-` + html.EscapeString(lang) + `"`
+			}
+			b.WriteString("<pre><code" + class + ">" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case trimmed == "":
+			flushPara()
+
+		case strings.HasPrefix(trimmed, "<!--"):
+			flushPara()
+			for !strings.Contains(lines[i], "-->") && i+1 < len(lines) {
+				i++
+			}
+
+		case HeadingLevel(line) > 0:
+			flushPara()
+			closeLists(0)
+			level := string(rune('0' + HeadingLevel(line)))
+			b.WriteString("<h" + level + ">" + inline(HeadingText(line)) + "</h" + level + ">\n")
+
+		case trimmed == "---" || trimmed == "***" || trimmed == "___":
+			flushPara()
+			closeLists(0)
+			b.WriteString("<hr>\n")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushPara()
+			closeLists(0)
+			b.WriteString("<blockquote>" + inline(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))) + "</blockquote>\n")
+
+		case isListItem(trimmed):
+			flushPara()
+			indent := len(line) - len(strings.TrimLeft(line, " \t"))
+			tag := "ul"
+			text := trimmed[2:]
+			if loc := orderedPattern.FindStringIndex(trimmed); loc != nil {
+				tag = "ol"
+				text = trimmed[loc[1]:]
+			}
+
+			for len(listStack) > 0 && indent < listIndent[len(listIndent)-1] {
+				closeLists(len(listStack) - 1)
+			}
+			if len(listStack) == 0 || indent > listIndent[len(listIndent)-1] {
+				b.WriteString("<" + tag + ">\n<li>")
+				listStack = append(listStack, tag)
+				listIndent = append(listIndent, indent)
+			} else {
+				b.WriteString("</li>\n<li>")
+			}
+			b.WriteString(inline(text))
+
+		default:
+			if len(listStack) > 0 && strings.HasPrefix(line, " ") {
+				b.WriteString(" " + inline(trimmed))
+				continue
+			}
+			closeLists(0)
+			para = append(para, trimmed)
+		}
+	}
+	flushPara()
+	closeLists(0)
+	return b.String()
+}
+
+func isListItem(trimmed string) bool {
+	for _, marker := range []string{"- ", "* ", "+ "} {
+		if strings.HasPrefix(trimmed, marker) {
+			return true
+		}
+	}
+	return orderedPattern.MatchString(trimmed)
+}
+
+// inline renders inline formatting of already-trimmed text.
+func inline(text string) string {
+	// Protect code spans from further formatting
+	var codes []string
+	text = inlineCodePattern.ReplaceAllStringFunc(text, func(m string) string {
+		codes = append(codes, "<code>"+html.EscapeString(m[1:len(m)-1])+"</code>")
+		return "\x00" + strconv.Itoa(len(codes)-1) + "\x00"
+	})
+
+	text = html.EscapeString(text)
+	text = imagePattern.ReplaceAllString(text, `<img alt="$1" src="$2">`)
+	text = linkPattern.ReplaceAllString(text, `<a href="$2">$1</a>`)
+	text = boldPattern.ReplaceAllString(text, "<strong>$1</strong>")
+	text = italicPattern.ReplaceAllString(text, "$1<em>$2</em>")
+
+	for i, c := range codes {
+		text = strings.Replace(text, "\x00"+strconv.Itoa(i)+"\x00", c, 1)
+	}
+	return text
+}
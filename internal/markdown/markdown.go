@@ -0,0 +1,404 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// HeadingLevel returns the ATX heading level of a line (0 if not a heading).
+func HeadingLevel(line string) int {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return 0
+	}
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0
+	}
+	if level < len(trimmed) && trimmed[level] != ' ' && trimmed[level] != '\t' {
+		return 0
+	}
+	return level
+}
+
+// HeadingText returns the text of a heading line without the leading hashes.
+func HeadingText(line string) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+}
+
+// isFence reports whether the line opens or closes a fenced code block.
+func isFence(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// headingLevels returns the heading level of every line, ignoring fenced code.
+func headingLevels(lines []string) []int {
+	levels := make([]int, len(lines))
+	inFence := false
+	for i, line := range lines {
+		if isFence(line) {
+			inFence = !inFence
+			continue
+		}
+		if !inFence {
+			levels[i] = HeadingLevel(line)
+		}
+	}
+	return levels
+}
+
+// findSection returns the line range [start, end) of the section with the given
+// heading (case-insensitive), including the heading line itself.
+func findSection(lines []string, heading string) (int, int, bool) {
+	levels := headingLevels(lines)
+	for i, line := range lines {
+		if levels[i] == 0 || !strings.EqualFold(HeadingText(line), strings.TrimSpace(heading)) {
+			continue
+		}
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if levels[j] > 0 && levels[j] <= levels[i] {
+				end = j
+				break
+			}
+		}
+		return i, end, true
+	}
+	return 0, 0, false
+}
+
+// RemoveSection removes the section with the given heading and everything
+// below it up to the next heading of the same or a higher level.
+func RemoveSection(md, heading string) (string, bool) {
+	lines := strings.Split(md, "\n")
+	start, end, ok := findSection(lines, heading)
+	if !ok {
+		return md, false
+	}
+	out := append(append([]string{}, lines[:start]...), lines[end:]...)
+	return strings.Join(out, "\n"), true
+}
+
+// ExtractSections returns the sections with the given headings
+// (case-insensitive, including their subsections) in document order, below
+// the document's level 1 title when it has one. It returns "" when no heading
+// matches.
+func ExtractSections(md string, headings []string) string {
+	lines := strings.Split(md, "\n")
+	levels := headingLevels(lines)
+
+	var title string
+	var parts []string
+	for i := 0; i < len(lines); i++ {
+		if levels[i] == 0 {
+			continue
+		}
+		if levels[i] == 1 && title == "" && len(parts) == 0 {
+			title = lines[i]
+			continue
+		}
+		if !containsFold(headings, HeadingText(lines[i])) {
+			continue
+		}
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if levels[j] > 0 && levels[j] <= levels[i] {
+				end = j
+				break
+			}
+		}
+		parts = append(parts, strings.TrimSpace(strings.Join(lines[i:end], "\n")))
+		i = end - 1
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	if title != "" {
+		parts = append([]string{title}, parts...)
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShiftHeadings moves every heading (outside fenced code) down by levels, so
+// a document can be nested below another heading. Levels stop at 6.
+func ShiftHeadings(md string, levels int) string {
+	lines := strings.Split(md, "\n")
+	for i, level := range headingLevels(lines) {
+		if level == 0 {
+			continue
+		}
+		to := level + levels
+		if to > 6 {
+			to = 6
+		}
+		lines[i] = strings.Repeat("#", to) + " " + HeadingText(lines[i])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ReplaceSection keeps the heading of the section but replaces its content.
+func ReplaceSection(md, heading, content string) (string, bool) {
+	lines := strings.Split(md, "\n")
+	start, end, ok := findSection(lines, heading)
+	if !ok {
+		return md, false
+	}
+	out := append([]string{}, lines[:start+1]...)
+	out = append(out, "")
+	out = append(out, strings.Split(strings.TrimRight(content, "\n"), "\n")...)
+	out = append(out, "")
+	out = append(out, lines[end:]...)
+	return strings.Join(out, "\n"), true
+}
+
+// bulletText returns the indentation and text of a list item line.
+func bulletText(line string) (string, string, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := line[:len(line)-len(trimmed)]
+	for _, marker := range []string{"- ", "* ", "+ "} {
+		if strings.HasPrefix(trimmed, marker) {
+			return indent, strings.TrimSpace(trimmed[len(marker):]), true
+		}
+	}
+	return "", "", false
+}
+
+// bulletMatches reports whether the bullet text starts with prefix, ignoring
+// case and leading emphasis markers.
+func bulletMatches(text, prefix string) bool {
+	norm := func(s string) string {
+		return strings.ToLower(strings.TrimLeft(strings.TrimSpace(s), "*_`"))
+	}
+	return strings.HasPrefix(norm(text), norm(prefix))
+}
+
+// ReplaceBullets replaces (or, with an empty replacement, removes) every list
+// item whose text starts with prefix, including its indented continuation lines.
+// It returns the number of bullets affected.
+func ReplaceBullets(md, prefix, replacement string) (string, int) {
+	lines := strings.Split(md, "\n")
+	var out []string
+	count := 0
+	inFence := false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if isFence(line) {
+			inFence = !inFence
+		}
+		indent, text, ok := bulletText(line)
+		if inFence || !ok || !bulletMatches(text, prefix) {
+			out = append(out, line)
+			continue
+		}
+
+		count++
+		// Skip continuation lines (more indented, non-empty)
+		for i+1 < len(lines) {
+			next := lines[i+1]
+			if strings.TrimSpace(next) == "" || len(next)-len(strings.TrimLeft(next, " \t")) <= len(indent) {
+				break
+			}
+			i++
+		}
+		if replacement != "" {
+			out = append(out, indent+"- "+replacement)
+		}
+	}
+	return strings.Join(out, "\n"), count
+}
+
+// SectionOf returns the heading text of the section containing each bullet
+// whose text starts with prefix.
+func SectionOf(md, prefix string) []string {
+	var out []string
+	lines := strings.Split(md, "\n")
+	levels := headingLevels(lines)
+	current := ""
+	inFence := false
+	for i, line := range lines {
+		if isFence(line) {
+			inFence = !inFence
+		}
+		if levels[i] > 0 {
+			current = HeadingText(line)
+			continue
+		}
+		if _, text, ok := bulletText(line); ok && !inFence && bulletMatches(text, prefix) {
+			out = append(out, current)
+		}
+	}
+	return out
+}
+
+// AnnotateSections appends the comment returned by note after the own content
+// of every section (the text up to the next heading). The preamble before the
+// first heading is passed an empty heading. Empty notes are skipped.
+func AnnotateSections(md string, note func(heading string) string) string {
+	lines := strings.Split(md, "\n")
+	levels := headingLevels(lines)
+
+	var out []string
+	current := ""
+	flush := func(block []string) {
+		for len(block) > 0 && strings.TrimSpace(block[len(block)-1]) == "" {
+			block = block[:len(block)-1]
+		}
+		if len(block) == 0 {
+			return
+		}
+		out = append(out, block...)
+		if n := note(current); n != "" {
+			out = append(out, "", n)
+		}
+		out = append(out, "")
+	}
+
+	var block []string
+	for i, line := range lines {
+		if levels[i] > 0 {
+			flush(block)
+			block = nil
+			current = HeadingText(line)
+		}
+		block = append(block, line)
+	}
+	flush(block)
+
+	return strings.TrimRight(strings.Join(out, "\n"), "\n")
+}
+
+var (
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+	emphasisPattern    = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	linkTextPattern    = regexp.MustCompile(`!?\[([^\]]*)\]\(([^)]*)\)`)
+)
+
+// ToPlainText flattens markdown into plain text suitable for pasting into a
+// chat: headings become "Heading:" lines, emphasis, comments and horizontal
+// rules are removed, links keep their text and URL. Fenced code is kept as is.
+func ToPlainText(md string) string {
+	md = htmlCommentPattern.ReplaceAllString(md, "")
+	lines := strings.Split(md, "\n")
+	levels := headingLevels(lines)
+
+	var out []string
+	inFence := false
+	blank := false
+	for i, line := range lines {
+		if isFence(line) {
+			inFence = !inFence
+		}
+		if !inFence && !isFence(line) {
+			trimmed := strings.TrimSpace(line)
+			switch {
+			case levels[i] > 0:
+				line = HeadingText(line) + ":"
+			case trimmed == "---" || trimmed == "***" || trimmed == "___":
+				line = ""
+			default:
+				line = emphasisPattern.ReplaceAllString(line, "$2")
+				line = linkTextPattern.ReplaceAllStringFunc(line, func(m string) string {
+					parts := linkTextPattern.FindStringSubmatch(m)
+					if parts[1] == "" || parts[1] == parts[2] {
+						return parts[2]
+					}
+					return parts[1] + " (" + parts[2] + ")"
+				})
+			}
+		}
+
+		// Collapse runs of blank lines
+		if strings.TrimSpace(line) == "" {
+			if blank {
+				continue
+			}
+			blank = true
+			out = append(out, "")
+			continue
+		}
+		blank = false
+		out = append(out, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// Section is a heading together with its own content (excluding subsections).
+type Section struct {
+	Heading string
+	Level   int
+	// Line is the 1-based line number of the heading.
+	Line int
+	// ContentLines counts non-blank lines of the section's own content.
+	ContentLines int
+	// HasChildren reports whether deeper headings follow inside the section.
+	HasChildren bool
+}
+
+// Sections returns all sections of the document in order.
+func Sections(md string) []Section {
+	lines := strings.Split(md, "\n")
+	levels := headingLevels(lines)
+
+	var out []Section
+	for i, line := range lines {
+		if levels[i] == 0 {
+			continue
+		}
+		s := Section{Heading: HeadingText(line), Level: levels[i], Line: i + 1}
+		for j := i + 1; j < len(lines); j++ {
+			if levels[j] > 0 {
+				s.HasChildren = levels[j] > levels[i]
+				break
+			}
+			if strings.TrimSpace(lines[j]) != "" {
+				s.ContentLines++
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// Bullet is a list item found outside fenced code.
+type Bullet struct {
+	Text    string
+	Line    int
+	Section string
+}
+
+// Bullets returns all list items of the document with their section heading.
+func Bullets(md string) []Bullet {
+	lines := strings.Split(md, "\n")
+	levels := headingLevels(lines)
+
+	var out []Bullet
+	current := ""
+	inFence := false
+	for i, line := range lines {
+		if isFence(line) {
+			inFence = !inFence
+			continue
+		}
+		if levels[i] > 0 {
+			current = HeadingText(line)
+			continue
+		}
+		if _, text, ok := bulletText(line); ok && !inFence {
+			out = append(out, Bullet{Text: text, Line: i + 1, Section: current})
+		}
+	}
+	return out
+}
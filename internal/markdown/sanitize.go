@@ -0,0 +1,133 @@
+package markdown
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// unsafeElements are removed together with their content.
+var unsafeElements = []string{"script", "style", "iframe", "object", "embed", "noscript", "template"}
+
+var unsafeElementPatterns = func() map[string]*regexp.Regexp {
+	patterns := map[string]*regexp.Regexp{}
+	for _, name := range unsafeElements {
+		patterns[name] = regexp.MustCompile(`(?is)<` + name + `\b[^>]*>.*?</` + name + `\s*>`)
+	}
+	return patterns
+}()
+
+var (
+	tagPattern       = regexp.MustCompile(`</?([a-zA-Z][a-zA-Z0-9-]*)((?:\s[^<>]*)?)/?>`)
+	attributePattern = regexp.MustCompile(`\s+([a-zA-Z_:][-a-zA-Z0-9_:.]*)(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?`)
+)
+
+// safeTags are the HTML elements kept in rendered instructions (without
+// event handlers, styles or script URLs); other tags are escaped so that
+// they show as text.
+var safeTags = map[string]bool{
+	"a": true, "abbr": true, "b": true, "blockquote": true, "br": true, "code": true,
+	"dd": true, "del": true, "details": true, "div": true, "dl": true, "dt": true,
+	"em": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"hr": true, "i": true, "img": true, "ins": true, "kbd": true, "li": true, "ol": true,
+	"p": true, "picture": true, "pre": true, "s": true, "source": true, "span": true,
+	"strong": true, "sub": true, "summary": true, "sup": true, "table": true, "tbody": true,
+	"td": true, "tfoot": true, "th": true, "thead": true, "tr": true, "ul": true,
+}
+
+// safeAttributes are kept on safe tags; URLs only when they are not scripts.
+var safeAttributes = map[string]bool{
+	"align": true, "alt": true, "colspan": true, "height": true, "href": true, "lang": true,
+	"media": true, "open": true, "rowspan": true, "src": true, "srcset": true, "title": true, "width": true,
+}
+
+// SanitizeHTML removes script-like elements (script, style, iframe, ...) from
+// the raw HTML in md, strips unsafe attributes (event handlers, styles,
+// javascript: URLs) from the remaining safe tags and escapes all other tags.
+// Fenced code, code spans and HTML comments are left alone. It returns the
+// sanitized markdown and the sorted names of the elements changed.
+func SanitizeHTML(md string) (string, []string) {
+	changed := map[string]bool{}
+	var out []string
+	var pending []string
+	flush := func() {
+		if len(pending) > 0 {
+			out = append(out, sanitizeText(strings.Join(pending, "\n"), changed))
+			pending = nil
+		}
+	}
+
+	inFence := false
+	for _, line := range strings.Split(md, "\n") {
+		if isFence(line) {
+			if !inFence {
+				flush()
+			}
+			inFence = !inFence
+			out = append(out, line)
+			continue
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+		pending = append(pending, line)
+	}
+	flush()
+
+	names := make([]string, 0, len(changed))
+	for name := range changed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(out, "\n"), names
+}
+
+// sanitizeText sanitizes markdown without fenced code.
+func sanitizeText(text string, changed map[string]bool) string {
+	// Set code spans and comments aside
+	var kept []string
+	keep := func(m string) string {
+		kept = append(kept, m)
+		return "\x02" + strconv.Itoa(len(kept)-1) + "\x02"
+	}
+	text = htmlCommentPattern.ReplaceAllStringFunc(text, keep)
+	text = inlineCodePattern.ReplaceAllStringFunc(text, keep)
+
+	for _, name := range unsafeElements {
+		text = unsafeElementPatterns[name].ReplaceAllStringFunc(text, func(string) string {
+			changed[name] = true
+			return ""
+		})
+	}
+	text = tagPattern.ReplaceAllStringFunc(text, func(m string) string {
+		parts := tagPattern.FindStringSubmatch(m)
+		name := strings.ToLower(parts[1])
+		if !safeTags[name] {
+			changed[name] = true
+			return "&lt;" + m[1:]
+		}
+		attrs := attributePattern.ReplaceAllStringFunc(parts[2], func(a string) string {
+			sub := attributePattern.FindStringSubmatch(a)
+			if safeAttributes[strings.ToLower(sub[1])] && !scriptURL(a) {
+				return a
+			}
+			changed[name] = true
+			return ""
+		})
+		prefix := m[:strings.Index(m, parts[1])+len(parts[1])]
+		return prefix + attrs + m[len(prefix)+len(parts[2]):]
+	})
+
+	for i, k := range kept {
+		text = strings.Replace(text, "\x02"+strconv.Itoa(i)+"\x02", k, 1)
+	}
+	return text
+}
+
+// scriptURL reports whether an attribute value is a script or HTML data URL.
+func scriptURL(attr string) bool {
+	value := strings.ToLower(strings.Join(strings.Fields(attr), ""))
+	return strings.Contains(value, "javascript:") || strings.Contains(value, "vbscript:") || strings.Contains(value, "data:text/html")
+}
@@ -0,0 +1,214 @@
+// Package mdlint applies a subset of markdownlint rules to generated markdown
+// and fixes the violations, so outputs pass the linters of the target repos.
+package mdlint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Supported rules (markdownlint IDs).
+const (
+	HeadingIncrement   = "MD001" // heading levels increase by one at a time
+	TrailingSpaces     = "MD009" // no trailing whitespace
+	MultipleBlanks     = "MD012" // no consecutive blank lines
+	BlanksAroundHead   = "MD022" // headings surrounded by blank lines
+	BlanksAroundFences = "MD031" // fenced code blocks surrounded by blank lines
+	FenceLanguage      = "MD040" // fenced code blocks declare a language
+	SingleTrailingNL   = "MD047" // file ends with a single newline
+)
+
+// All lists the supported rules in the order they are applied.
+var All = []string{HeadingIncrement, TrailingSpaces, FenceLanguage, BlanksAroundHead, BlanksAroundFences, MultipleBlanks, SingleTrailingNL}
+
+// DefaultLanguage is used for MD040 when no language is configured.
+const DefaultLanguage = "text"
+
+// Options selects the rules to apply.
+type Options struct {
+	Rules []string
+	// Language is added to fenced code blocks without one (MD040).
+	Language string
+}
+
+// Validate reports unknown rule IDs.
+func (o Options) Validate() error {
+	for _, r := range o.Rules {
+		if !contains(All, strings.ToUpper(r)) {
+			return fmt.Errorf("unsupported markdownlint rule '%s' (supported: %s)", r, strings.Join(All, ", "))
+		}
+	}
+	return nil
+}
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,6})(\s+.*|)$`)
+	fenceRe   = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})(.*)$")
+)
+
+// line is a markdown line with the block it belongs to.
+type line struct {
+	text string
+	// code is true inside fenced code (fence lines included); front matter counts as code.
+	code  bool
+	fence bool // opening or closing fence line
+	open  bool // opening fence line
+}
+
+// Fix applies the selected rules to content and returns the fixed content.
+func Fix(content string, opts Options) string {
+	enabled := map[string]bool{}
+	for _, r := range opts.Rules {
+		enabled[strings.ToUpper(r)] = true
+	}
+	lang := opts.Language
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+
+	lines := split(content)
+	for _, rule := range All {
+		if !enabled[rule] {
+			continue
+		}
+		switch rule {
+		case HeadingIncrement:
+			fixHeadingIncrement(lines)
+		case TrailingSpaces:
+			for i := range lines {
+				lines[i].text = strings.TrimRight(lines[i].text, " \t")
+			}
+		case FenceLanguage:
+			for i, l := range lines {
+				if m := fenceRe.FindStringSubmatch(l.text); l.open && m != nil && strings.TrimSpace(m[2]) == "" {
+					lines[i].text = strings.TrimRight(l.text, " \t") + lang
+				}
+			}
+		case BlanksAroundHead:
+			lines = padBlocks(lines, func(l line) bool { return !l.code && headingRe.MatchString(l.text) }, true)
+		case BlanksAroundFences:
+			lines = padBlocks(lines, func(l line) bool { return l.fence }, false)
+		case MultipleBlanks:
+			var out []line
+			for _, l := range lines {
+				if !l.code && isBlank(l) && len(out) > 0 && isBlank(out[len(out)-1]) {
+					continue
+				}
+				out = append(out, l)
+			}
+			lines = out
+		}
+	}
+
+	var b strings.Builder
+	for i, l := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(l.text)
+	}
+	out := b.String()
+	if enabled[SingleTrailingNL] {
+		out = strings.TrimRight(out, "\n") + "\n"
+	}
+	return out
+}
+
+// split classifies lines, treating a leading front matter block as code.
+func split(content string) []line {
+	raw := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	lines := make([]line, len(raw))
+
+	start := 0
+	if len(raw) > 0 && raw[0] == "---" {
+		for i := 1; i < len(raw); i++ {
+			if raw[i] == "---" {
+				for j := 0; j <= i; j++ {
+					lines[j] = line{text: raw[j], code: true}
+				}
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	fence := ""
+	for i := start; i < len(raw); i++ {
+		l := line{text: raw[i]}
+		m := fenceRe.FindStringSubmatch(raw[i])
+		switch {
+		case fence == "" && m != nil:
+			fence = m[1]
+			l.code, l.fence, l.open = true, true, true
+		case fence != "" && m != nil && m[1][0] == fence[0] && len(m[1]) >= len(fence) && strings.TrimSpace(m[2]) == "":
+			fence = ""
+			l.code, l.fence = true, true
+		case fence != "":
+			l.code = true
+		}
+		lines[i] = l
+	}
+	return lines
+}
+
+// fixHeadingIncrement lowers headings that skip levels (e.g. "#" then "###").
+func fixHeadingIncrement(lines []line) {
+	prev := 0
+	for i, l := range lines {
+		if l.code {
+			continue
+		}
+		m := headingRe.FindStringSubmatch(l.text)
+		if m == nil {
+			continue
+		}
+		level := len(m[1])
+		if prev > 0 && level > prev+1 {
+			level = prev + 1
+			lines[i].text = strings.Repeat("#", level) + m[2]
+		}
+		prev = level
+	}
+}
+
+// padBlocks inserts blank lines around lines matching is. Consecutive fence
+// lines belong to one block, so only the outside of a fenced block is padded.
+func padBlocks(lines []line, is func(line) bool, single bool) []line {
+	var out []line
+	for i, l := range lines {
+		if is(l) {
+			before := single || l.open
+			if before && len(out) > 0 && !isBlank(out[len(out)-1]) && !isFrontMatterEnd(out) {
+				out = append(out, line{})
+			}
+			out = append(out, l)
+			after := single || !l.open
+			if after && i+1 < len(lines) && !isBlank(lines[i+1]) {
+				out = append(out, line{})
+			}
+			continue
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+// isFrontMatterEnd reports whether out ends with the closing front matter delimiter.
+func isFrontMatterEnd(out []line) bool {
+	last := out[len(out)-1]
+	return last.code && !last.fence && last.text == "---"
+}
+
+func isBlank(l line) bool {
+	return !l.code && strings.TrimSpace(l.text) == ""
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package mdlint
+
+import "testing"
+
+func TestFix(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		in   string
+		want string
+	}{
+		{
+			name: "MD001 lowers skipped heading levels",
+			rule: HeadingIncrement,
+			in:   "# Title\n\n### Skipped\n\n#### Deeper\n\n## Back\n",
+			want: "# Title\n\n## Skipped\n\n### Deeper\n\n## Back\n",
+		},
+		{
+			name: "MD001 skips fenced code",
+			rule: HeadingIncrement,
+			in:   "# Title\n\n```sh\n### not a heading\n```\n",
+			want: "# Title\n\n```sh\n### not a heading\n```\n",
+		},
+		{
+			name: "MD009 trims trailing whitespace",
+			rule: TrailingSpaces,
+			in:   "Line with spaces  \nLine with tab\t\n",
+			want: "Line with spaces\nLine with tab\n",
+		},
+		{
+			name: "MD012 collapses blank lines",
+			rule: MultipleBlanks,
+			in:   "One\n\n\n\nTwo\n",
+			want: "One\n\nTwo\n",
+		},
+		{
+			name: "MD012 keeps blank lines in code",
+			rule: MultipleBlanks,
+			in:   "```text\na\n\n\nb\n```\n",
+			want: "```text\na\n\n\nb\n```\n",
+		},
+		{
+			name: "MD022 pads headings",
+			rule: BlanksAroundHead,
+			in:   "Intro\n## Heading\nText\n",
+			want: "Intro\n\n## Heading\n\nText\n",
+		},
+		{
+			name: "MD022 keeps headings right after front matter",
+			rule: BlanksAroundHead,
+			in:   "---\napplyTo: '**'\n---\n# Heading\n\nText\n",
+			want: "---\napplyTo: '**'\n---\n# Heading\n\nText\n",
+		},
+		{
+			name: "MD031 pads fenced blocks on the outside only",
+			rule: BlanksAroundFences,
+			in:   "Example:\n```php\necho 1;\n```\nDone.\n",
+			want: "Example:\n\n```php\necho 1;\n```\n\nDone.\n",
+		},
+		{
+			name: "MD040 adds the default language",
+			rule: FenceLanguage,
+			in:   "```\ncode\n```\n\n```go\ncode\n```\n",
+			want: "```text\ncode\n```\n\n```go\ncode\n```\n",
+		},
+		{
+			name: "MD040 leaves the closing fence alone",
+			rule: FenceLanguage,
+			in:   "~~~~\n```\n~~~~\n",
+			want: "~~~~text\n```\n~~~~\n",
+		},
+		{
+			name: "MD047 ends with a single newline",
+			rule: SingleTrailingNL,
+			in:   "Text\n\n\n",
+			want: "Text\n",
+		},
+		{
+			name: "MD047 adds a missing newline",
+			rule: SingleTrailingNL,
+			in:   "Text",
+			want: "Text\n",
+		},
+		{
+			name: "rule IDs are case-insensitive",
+			rule: "md009",
+			in:   "Text  \n",
+			want: "Text\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fix(tt.in, Options{Rules: []string{tt.rule}}); got != tt.want {
+				t.Errorf("Fix() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixAllRules(t *testing.T) {
+	in := "# Title\r\n### Setup  \r\n```\r\nmake\r\n```\r\n\r\n\r\nDone."
+	want := "# Title\n\n## Setup\n\n```sh\nmake\n```\n\nDone.\n"
+	opts := Options{Rules: All, Language: "sh"}
+	got := Fix(in, opts)
+	if got != want {
+		t.Errorf("Fix() =\n%q\nwant\n%q", got, want)
+	}
+	if again := Fix(got, opts); again != got {
+		t.Errorf("Fix() is not idempotent:\n%q\nthen\n%q", got, again)
+	}
+}
+
+func TestFixWithoutRulesOnlyNormalizesLineEndings(t *testing.T) {
+	in := "#  Title\r\n### Setup  \r\n"
+	if got, want := Fix(in, Options{}), "#  Title\n### Setup  \n"; got != want {
+		t.Errorf("Fix() = %q, want %q", got, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Options{Rules: []string{"MD001", "md047"}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (Options{Rules: []string{"MD013"}}).Validate(); err == nil {
+		t.Errorf("Validate() accepted an unsupported rule")
+	}
+}
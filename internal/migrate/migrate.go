@@ -0,0 +1,148 @@
+// Package migrate upgrades the project config and rule front matter to the
+// current schema in place, keeping comments and key order.
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Schema lists the upgrades for one kind of YAML document.
+type Schema struct {
+	// Renames maps obsolete or misspelled top-level keys to their current name.
+	Renames map[string]string
+	// Lists are keys that hold a list; a single scalar value is wrapped in one.
+	Lists []string
+}
+
+// Config is the schema of .ai-instructions.yaml.
+var Config = Schema{
+	Renames: map[string]string{
+		"no_agents":  "noAgents",
+		"no-agents":  "noAgents",
+		"no_copilot": "noCopilot",
+		"no-copilot": "noCopilot",
+		"target":     "targets",
+		"experiment": "experiments",
+	},
+	Lists: []string{"targets", "experiments"},
+}
+
+// FrontMatter is the schema of rule front matter.
+var FrontMatter = Schema{
+	Renames: map[string]string{
+		"override": "overrides",
+		"require":  "requires",
+		"tool":     "tools",
+	},
+	Lists: []string{"requires", "tools"},
+}
+
+// YAML upgrades a YAML document and describes each change. The input is
+// returned unchanged when nothing needs migrating.
+func YAML(data []byte, s Schema) ([]byte, []string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return data, nil, nil
+	}
+
+	changes := s.apply(doc.Content[0])
+	if len(changes) == 0 {
+		return data, nil, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), changes, nil
+}
+
+// Rule upgrades the front matter of a rule file, leaving the body untouched.
+func Rule(data []byte) ([]byte, []string, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return data, nil, nil
+	}
+	end := strings.Index(text[4:], "\n---")
+	if end < 0 {
+		return nil, nil, fmt.Errorf("unterminated front matter")
+	}
+	front, rest := text[4:4+end+1], text[4+end+1:]
+
+	migrated, changes, err := YAML([]byte(front), FrontMatter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("front matter: %w", err)
+	}
+	if len(changes) == 0 {
+		return data, nil, nil
+	}
+	return []byte("---\n" + string(migrated) + rest), changes, nil
+}
+
+func (s Schema) apply(m *yaml.Node) []string {
+	var changes []string
+
+	present := map[string]bool{}
+	for i := 0; i < len(m.Content); i += 2 {
+		present[m.Content[i].Value] = true
+	}
+	for i := 0; i < len(m.Content); i += 2 {
+		key := m.Content[i]
+		to, ok := s.Renames[key.Value]
+		if !ok {
+			continue
+		}
+		if present[to] {
+			changes = append(changes, fmt.Sprintf("kept '%s': '%s' is already set (remove one by hand)", key.Value, to))
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("renamed '%s' to '%s'", key.Value, to))
+		present[to] = true
+		key.Value = to
+	}
+
+	for i := 0; i < len(m.Content); i += 2 {
+		key, value := m.Content[i], m.Content[i+1]
+		if !contains(s.Lists, key.Value) || value.Kind != yaml.ScalarNode || value.Tag == "!!null" {
+			continue
+		}
+		var items []*yaml.Node
+		for _, part := range strings.Split(value.Value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				items = append(items, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part})
+			}
+		}
+		m.Content[i+1] = &yaml.Node{
+			Kind:        yaml.SequenceNode,
+			Tag:         "!!seq",
+			Style:       yaml.FlowStyle,
+			Content:     items,
+			HeadComment: value.HeadComment,
+			LineComment: value.LineComment,
+			FootComment: value.FootComment,
+		}
+		changes = append(changes, fmt.Sprintf("converted '%s' to a list", key.Value))
+	}
+	return changes
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package migrate
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the .golden files in testdata")
+
+// Each fixture is a config or rule written for an older schema; its
+// migration is compared with testdata/<fixture>.golden.
+var goldenTests = []struct {
+	fixture string
+	migrate func([]byte) ([]byte, []string, error)
+	changes []string
+}{
+	{
+		fixture: "config-snake-case.yaml",
+		migrate: migrateConfig,
+		changes: []string{"renamed 'no_agents' to 'noAgents'", "renamed 'no_copilot' to 'noCopilot'", "renamed 'target' to 'targets'", "converted 'targets' to a list"},
+	},
+	{
+		fixture: "config-kebab-case.yaml",
+		migrate: migrateConfig,
+		changes: []string{"renamed 'target' to 'targets'", "renamed 'no-agents' to 'noAgents'", "renamed 'experiment' to 'experiments'", "converted 'targets' to a list", "converted 'experiments' to a list"},
+	},
+	{
+		fixture: "config-conflict.yaml",
+		migrate: migrateConfig,
+		changes: []string{"kept 'no_agents': 'noAgents' is already set (remove one by hand)", "kept 'target': 'targets' is already set (remove one by hand)"},
+	},
+	{
+		fixture: "config-current.yaml",
+		migrate: migrateConfig,
+	},
+	{
+		fixture: "rule-singular.md",
+		migrate: Rule,
+		changes: []string{"renamed 'override' to 'overrides'", "renamed 'require' to 'requires'", "renamed 'tool' to 'tools'", "converted 'requires' to a list", "converted 'tools' to a list"},
+	},
+	{
+		fixture: "rule-current.md",
+		migrate: Rule,
+	},
+}
+
+func migrateConfig(data []byte) ([]byte, []string, error) {
+	return YAML(data, Config)
+}
+
+func TestGolden(t *testing.T) {
+	for _, tt := range goldenTests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			in, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, changes, err := tt.migrate(in)
+			if err != nil {
+				t.Fatalf("migrate error = %v", err)
+			}
+			if !reflect.DeepEqual(changes, tt.changes) {
+				t.Errorf("changes = %q, want %q", changes, tt.changes)
+			}
+
+			golden := filepath.Join("testdata", tt.fixture+".golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("migrated =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestMigratingTwiceChangesNothing(t *testing.T) {
+	for _, tt := range goldenTests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			in, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			once, _, err := tt.migrate(in)
+			if err != nil {
+				t.Fatalf("first run error = %v", err)
+			}
+			twice, changes, err := tt.migrate(once)
+			if err != nil {
+				t.Fatalf("second run error = %v", err)
+			}
+			// Conflicts are reported again, since they are left for the user
+			if len(changes) > 0 && tt.fixture != "config-conflict.yaml" {
+				t.Errorf("second run changes = %q, want none", changes)
+			}
+			if string(twice) != string(once) {
+				t.Errorf("second run =\n%s\nwant it unchanged:\n%s", twice, once)
+			}
+		})
+	}
+}
+
+func TestRuleWithoutFrontMatter(t *testing.T) {
+	in := []byte("# Testing\n\nrequire: php\n")
+	got, changes, err := Rule(in)
+	if err != nil || changes != nil || string(got) != string(in) {
+		t.Errorf("Rule() = %q, %q, %v, want the input unchanged", got, changes, err)
+	}
+	if _, _, err := Rule([]byte("---\nrequire: php\n")); err == nil {
+		t.Errorf("Rule() accepted unterminated front matter")
+	}
+}
@@ -0,0 +1,206 @@
+// Package policy reads the organization's AI policy, a structured YAML file
+// from which the Guardrails section of the instructions is generated, so that
+// every repository states the same safety rules in the same words.
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultPath is the policy file used when the config names none.
+const DefaultPath = ".ai-instructions/policy.yaml"
+
+// Policy is the parsed policy file.
+type Policy struct {
+	// ForbiddenCommands are commands agents must never run.
+	ForbiddenCommands []Command `yaml:"forbiddenCommands,omitempty"`
+
+	// ProtectedBranches are branch names or path.Match patterns (e.g.
+	// "release/*") agents must never commit or push to directly.
+	ProtectedBranches []string `yaml:"protectedBranches,omitempty"`
+
+	// Secrets says where secrets live and how they are handled.
+	Secrets Secrets `yaml:"secrets,omitempty"`
+
+	// DataBoundaries are kinds of data and where they must stay.
+	DataBoundaries []DataBoundary `yaml:"dataBoundaries,omitempty"`
+}
+
+// Command is a forbidden command, with the reason shown to agents.
+type Command struct {
+	Command string `yaml:"command"`
+	Reason  string `yaml:"reason,omitempty"`
+}
+
+// Secrets describes the secrets handling rules.
+type Secrets struct {
+	// Store is where secrets are kept, e.g. "HashiCorp Vault".
+	Store string `yaml:"store,omitempty"`
+	// Files are path patterns of files holding secrets, e.g. ".env*".
+	Files []string `yaml:"files,omitempty"`
+	// Rules are additional secrets handling rules, one sentence each.
+	Rules []string `yaml:"rules,omitempty"`
+}
+
+// DataBoundary restricts where a kind of data may go.
+type DataBoundary struct {
+	// Data is the kind of data, e.g. "Customer personal data".
+	Data string `yaml:"data"`
+	// Rule is what agents must (not) do with it, e.g. "never leaves the EU".
+	Rule string `yaml:"rule"`
+}
+
+// Load reads and validates the policy at path (nil when the file does not
+// exist).
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return Parse(data, path)
+}
+
+// Parse parses and validates a policy. Unknown keys are errors, so that a
+// misspelled rule is never silently dropped.
+func Parse(data []byte, name string) (*Policy, error) {
+	var p Policy
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid policy %s: %w", name, err)
+	}
+	if problems := p.Validate(); len(problems) > 0 {
+		return nil, fmt.Errorf("invalid policy %s:\n  - %s", name, strings.Join(problems, "\n  - "))
+	}
+	return &p, nil
+}
+
+// Validate returns the problems of the policy: empty or duplicate entries and
+// invalid branch patterns.
+func (p *Policy) Validate() []string {
+	var problems []string
+	seen := map[string]bool{}
+	duplicate := func(kind, value string) bool {
+		key := kind + "\x00" + value
+		if seen[key] {
+			problems = append(problems, fmt.Sprintf("%s: duplicate '%s'", kind, value))
+			return true
+		}
+		seen[key] = true
+		return false
+	}
+
+	for i, c := range p.ForbiddenCommands {
+		if strings.TrimSpace(c.Command) == "" {
+			problems = append(problems, fmt.Sprintf("forbiddenCommands[%d]: command is empty", i))
+			continue
+		}
+		duplicate("forbiddenCommands", strings.TrimSpace(c.Command))
+	}
+	for i, b := range p.ProtectedBranches {
+		b = strings.TrimSpace(b)
+		if b == "" {
+			problems = append(problems, fmt.Sprintf("protectedBranches[%d]: branch is empty", i))
+			continue
+		}
+		if _, err := path.Match(b, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("protectedBranches[%d]: invalid pattern '%s'", i, b))
+			continue
+		}
+		duplicate("protectedBranches", b)
+	}
+	for i, f := range p.Secrets.Files {
+		if strings.TrimSpace(f) == "" {
+			problems = append(problems, fmt.Sprintf("secrets.files[%d]: pattern is empty", i))
+			continue
+		}
+		duplicate("secrets.files", strings.TrimSpace(f))
+	}
+	for i, r := range p.Secrets.Rules {
+		if strings.TrimSpace(r) == "" {
+			problems = append(problems, fmt.Sprintf("secrets.rules[%d]: rule is empty", i))
+		}
+	}
+	for i, d := range p.DataBoundaries {
+		if strings.TrimSpace(d.Data) == "" || strings.TrimSpace(d.Rule) == "" {
+			problems = append(problems, fmt.Sprintf("dataBoundaries[%d]: data and rule are required", i))
+			continue
+		}
+		duplicate("dataBoundaries", strings.TrimSpace(d.Data))
+	}
+	return problems
+}
+
+// Empty reports whether the policy has no rules (a nil Policy is empty).
+func (p *Policy) Empty() bool {
+	return p == nil || (len(p.ForbiddenCommands) == 0 && len(p.ProtectedBranches) == 0 &&
+		p.Secrets.Store == "" && len(p.Secrets.Files) == 0 && len(p.Secrets.Rules) == 0 &&
+		len(p.DataBoundaries) == 0)
+}
+
+// Section renders the policy as the "## Guardrails" markdown section, with
+// one subsection per kind of rule ("" for an empty policy).
+func (p *Policy) Section() string {
+	if p.Empty() {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Guardrails\n\n")
+	b.WriteString("These rules come from the organization's AI policy. They apply to every task and take precedence over all other instructions; when a task cannot be done without breaking one, stop and ask a maintainer.")
+
+	if len(p.ForbiddenCommands) > 0 {
+		b.WriteString("\n\n### Forbidden commands\n\nNever run these commands, not even to recover from an error:\n")
+		for _, c := range p.ForbiddenCommands {
+			fmt.Fprintf(&b, "\n- `%s`", strings.TrimSpace(c.Command))
+			if reason := strings.TrimSpace(c.Reason); reason != "" {
+				fmt.Fprintf(&b, ": %s", reason)
+			}
+		}
+	}
+
+	if len(p.ProtectedBranches) > 0 {
+		b.WriteString("\n\n### Protected branches\n\nNever commit, push, force-push, rebase or merge directly to these branches; propose changes through a pull request:\n")
+		for _, branch := range p.ProtectedBranches {
+			fmt.Fprintf(&b, "\n- `%s`", strings.TrimSpace(branch))
+		}
+	}
+
+	s := p.Secrets
+	if s.Store != "" || len(s.Files) > 0 || len(s.Rules) > 0 {
+		b.WriteString("\n\n### Secrets\n")
+		b.WriteString("\n- Never print, log, hard-code or commit secrets (passwords, tokens, keys, connection strings), and never paste them into prompts.")
+		if store := strings.TrimSpace(s.Store); store != "" {
+			fmt.Fprintf(&b, "\n- Secrets are kept in %s; read them from there (or from the environment) at runtime.", store)
+		}
+		if len(s.Files) > 0 {
+			files := make([]string, len(s.Files))
+			for i, f := range s.Files {
+				files[i] = "`" + strings.TrimSpace(f) + "`"
+			}
+			fmt.Fprintf(&b, "\n- Never read, modify or create files matching %s.", strings.Join(files, ", "))
+		}
+		for _, r := range s.Rules {
+			fmt.Fprintf(&b, "\n- %s", strings.TrimSpace(r))
+		}
+	}
+
+	if len(p.DataBoundaries) > 0 {
+		b.WriteString("\n\n### Data boundaries\n")
+		for _, d := range p.DataBoundaries {
+			fmt.Fprintf(&b, "\n- **%s:** %s", strings.TrimSpace(d.Data), strings.TrimSpace(d.Rule))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package policy
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const samplePolicy = `forbiddenCommands:
+  - command: git push --force
+    reason: rewrites shared history
+  - command: terraform apply
+protectedBranches:
+  - main
+  - release/*
+secrets:
+  store: HashiCorp Vault
+  files:
+    - .env*
+    - secrets/**
+  rules:
+    - Rotate a secret that was exposed, even briefly.
+dataBoundaries:
+  - data: Customer personal data
+    rule: never leaves the EU
+`
+
+func TestSection(t *testing.T) {
+	p, err := Parse([]byte(samplePolicy), "policy.yaml")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := "## Guardrails\n\n" +
+		"These rules come from the organization's AI policy. They apply to every task and take precedence over all other instructions; when a task cannot be done without breaking one, stop and ask a maintainer.\n\n" +
+		"### Forbidden commands\n\n" +
+		"Never run these commands, not even to recover from an error:\n\n" +
+		"- `git push --force`: rewrites shared history\n" +
+		"- `terraform apply`\n\n" +
+		"### Protected branches\n\n" +
+		"Never commit, push, force-push, rebase or merge directly to these branches; propose changes through a pull request:\n\n" +
+		"- `main`\n" +
+		"- `release/*`\n\n" +
+		"### Secrets\n\n" +
+		"- Never print, log, hard-code or commit secrets (passwords, tokens, keys, connection strings), and never paste them into prompts.\n" +
+		"- Secrets are kept in HashiCorp Vault; read them from there (or from the environment) at runtime.\n" +
+		"- Never read, modify or create files matching `.env*`, `secrets/**`.\n" +
+		"- Rotate a secret that was exposed, even briefly.\n\n" +
+		"### Data boundaries\n\n" +
+		"- **Customer personal data:** never leaves the EU"
+	if got := p.Section(); got != want {
+		t.Errorf("Section() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSectionOnlyRendersKindsWithRules(t *testing.T) {
+	p, err := Parse([]byte("protectedBranches: [main]\n"), "policy.yaml")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	got := p.Section()
+	if !strings.Contains(got, "### Protected branches") {
+		t.Errorf("Section() has no protected branches:\n%s", got)
+	}
+	for _, heading := range []string{"### Forbidden commands", "### Secrets", "### Data boundaries"} {
+		if strings.Contains(got, heading) {
+			t.Errorf("Section() has %q without rules:\n%s", heading, got)
+		}
+	}
+}
+
+func TestEmptyPolicy(t *testing.T) {
+	for _, data := range []string{"", "# no rules yet\n", "secrets: {}\n"} {
+		p, err := Parse([]byte(data), "policy.yaml")
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", data, err)
+		}
+		if !p.Empty() || p.Section() != "" {
+			t.Errorf("Parse(%q) is not empty: %q", data, p.Section())
+		}
+	}
+	var p *Policy
+	if p.Section() != "" {
+		t.Errorf("nil Policy has a section")
+	}
+}
+
+func TestParseRejectsInvalidPolicies(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unknown key", "forbiddenCommand:\n  - command: rm -rf /\n", "field forbiddenCommand not found"},
+		{"empty command", "forbiddenCommands:\n  - reason: no command\n", "forbiddenCommands[0]: command is empty"},
+		{"duplicate command", "forbiddenCommands:\n  - command: make deploy\n  - command: ' make deploy '\n", "forbiddenCommands: duplicate 'make deploy'"},
+		{"empty branch", "protectedBranches: ['']\n", "protectedBranches[0]: branch is empty"},
+		{"invalid branch pattern", "protectedBranches: ['release/[']\n", "protectedBranches[0]: invalid pattern 'release/['"},
+		{"empty secrets file", "secrets:\n  files: ['']\n", "secrets.files[0]: pattern is empty"},
+		{"empty secrets rule", "secrets:\n  rules: [' ']\n", "secrets.rules[0]: rule is empty"},
+		{"data boundary without rule", "dataBoundaries:\n  - data: Card numbers\n", "dataBoundaries[0]: data and rule are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data), "policy.yaml")
+			if err == nil {
+				t.Fatalf("Parse() succeeded, want an error containing %q", tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) || !strings.Contains(err.Error(), "policy.yaml") {
+				t.Errorf("Parse() error = %v, want it to name policy.yaml and contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	p, err := Load(filepath.Join(t.TempDir(), "policy.yaml"))
+	if err != nil || p != nil {
+		t.Errorf("Load() = %v, %v, want no policy and no error", p, err)
+	}
+}
@@ -0,0 +1,144 @@
+package quality
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/cego/ai-instructions/internal/markdown"
+)
+
+// Options tunes the quality analysis.
+type Options struct {
+	// MaxSectionLines is the number of content lines above which a section is over-long.
+	MaxSectionLines int
+	// Similarity is the minimum word-set similarity (0..1) for near-duplicate bullets.
+	Similarity float64
+}
+
+// DefaultOptions are used by rules lint.
+var DefaultOptions = Options{MaxSectionLines: 80, Similarity: 0.8}
+
+// Finding is a single quality issue in a rule file.
+type Finding struct {
+	Rule    string
+	Line    int
+	Kind    string // duplicate, long-section, empty-section
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("rules/%s.md:%d: %s: %s", f.Rule, f.Line, f.Kind, f.Message)
+}
+
+// Penalty per finding kind, subtracted from a perfect score of 100.
+var penalties = map[string]int{
+	"duplicate":     5,
+	"long-section":  3,
+	"empty-section": 2,
+}
+
+// Report is the result of analysing a set of rules.
+type Report struct {
+	Findings []Finding
+	// Scores holds a 0-100 quality score per rule.
+	Scores map[string]int
+}
+
+type bulletRef struct {
+	rule   string
+	bullet markdown.Bullet
+	words  map[string]bool
+}
+
+// Analyze inspects the rule bodies (keyed by rule ID) for near-duplicate
+// bullets across files, over-long sections and empty sections.
+func Analyze(bodies map[string]string, opts Options) Report {
+	ids := make([]string, 0, len(bodies))
+	for id := range bodies {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	report := Report{Scores: map[string]int{}}
+	var refs []bulletRef
+
+	for _, id := range ids {
+		body := bodies[id]
+		for _, s := range markdown.Sections(body) {
+			if s.ContentLines == 0 && !s.HasChildren {
+				report.Findings = append(report.Findings, Finding{Rule: id, Line: s.Line, Kind: "empty-section", Message: fmt.Sprintf("section '%s' has no content", s.Heading)})
+			}
+			if opts.MaxSectionLines > 0 && s.ContentLines > opts.MaxSectionLines {
+				report.Findings = append(report.Findings, Finding{Rule: id, Line: s.Line, Kind: "long-section", Message: fmt.Sprintf("section '%s' has %d lines (max %d)", s.Heading, s.ContentLines, opts.MaxSectionLines)})
+			}
+		}
+		for _, b := range markdown.Bullets(body) {
+			if w := words(b.Text); len(w) >= 4 {
+				refs = append(refs, bulletRef{rule: id, bullet: b, words: w})
+			}
+		}
+	}
+
+	for i := range refs {
+		for j := i + 1; j < len(refs); j++ {
+			a, b := refs[i], refs[j]
+			if a.rule == b.rule || similarity(a.words, b.words) < opts.Similarity {
+				continue
+			}
+			report.Findings = append(report.Findings, Finding{
+				Rule:    b.rule,
+				Line:    b.bullet.Line,
+				Kind:    "duplicate",
+				Message: fmt.Sprintf("bullet is a near-duplicate of rules/%s.md:%d", a.rule, a.bullet.Line),
+			})
+		}
+	}
+
+	for _, id := range ids {
+		report.Scores[id] = 100
+	}
+	for _, f := range report.Findings {
+		report.Scores[f.Rule] -= penalties[f.Kind]
+	}
+	for id, score := range report.Scores {
+		if score < 0 {
+			report.Scores[id] = 0
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		if report.Findings[i].Rule != report.Findings[j].Rule {
+			return report.Findings[i].Rule < report.Findings[j].Rule
+		}
+		return report.Findings[i].Line < report.Findings[j].Line
+	})
+	return report
+}
+
+// words returns the normalized word set of a bullet.
+func words(text string) map[string]bool {
+	out := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		out[w] = true
+	}
+	return out
+}
+
+// similarity is the Jaccard index of two word sets.
+func similarity(a, b map[string]bool) float64 {
+	inter := 0
+	for w := range a {
+		if b[w] {
+			inter++
+		}
+	}
+	union := len(a) + len(b) - inter
+	if union == 0 {
+		return 0
+	}
+	return float64(inter) / float64(union)
+}
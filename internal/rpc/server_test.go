@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+)
+
+func testServer() *Server {
+	return &Server{Methods: map[string]Handler{
+		"echo": func(params json.RawMessage) (any, error) {
+			var p map[string]any
+			if err := json.Unmarshal(params, &p); err != nil {
+				return nil, InvalidParams("params must be an object")
+			}
+			return p, nil
+		},
+		"fail": func(json.RawMessage) (any, error) { return nil, errors.New("boom") },
+	}}
+}
+
+func TestServeConnRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		request string
+		want    string
+	}{
+		{"result", `{"jsonrpc":"2.0","id":1,"method":"echo","params":{"path":"."}}`, `{"jsonrpc":"2.0","id":1,"result":{"path":"."}}`},
+		{"string id", `{"jsonrpc":"2.0","id":"a","method":"echo","params":{}}`, `{"jsonrpc":"2.0","id":"a","result":{}}`},
+		{"missing params", `{"jsonrpc":"2.0","id":2,"method":"echo"}`, `{"jsonrpc":"2.0","id":2,"result":{}}`},
+		{"null params", `{"jsonrpc":"2.0","id":3,"method":"echo","params":null}`, `{"jsonrpc":"2.0","id":3,"result":{}}`},
+		{"invalid params", `{"jsonrpc":"2.0","id":4,"method":"echo","params":[1]}`, `{"jsonrpc":"2.0","id":4,"error":{"code":-32602,"message":"params must be an object"}}`},
+		{"handler error", `{"jsonrpc":"2.0","id":5,"method":"fail"}`, `{"jsonrpc":"2.0","id":5,"error":{"code":-32000,"message":"boom"}}`},
+		{"unknown method", `{"jsonrpc":"2.0","id":6,"method":"nope"}`, `{"jsonrpc":"2.0","id":6,"error":{"code":-32601,"message":"method not found: nope"}}`},
+		{"parse error", `{"jsonrpc":`, `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"unexpected end of JSON input"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := roundTrip(t, tt.request); got != tt.want {
+				t.Errorf("response = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeConnSkipsNotifications(t *testing.T) {
+	// A notification (no id) gets no response; the next call is answered
+	got := roundTrip(t, `{"jsonrpc":"2.0","method":"echo","params":{"n":1}}`+"\n\n"+`{"jsonrpc":"2.0","id":7,"method":"echo","params":{"n":2}}`)
+	if want := `{"jsonrpc":"2.0","id":7,"result":{"n":2}}`; got != want {
+		t.Errorf("response = %s, want %s", got, want)
+	}
+}
+
+// roundTrip sends the request lines to a served connection and returns the
+// first response line.
+func roundTrip(t *testing.T, requests string) string {
+	t.Helper()
+	client, server := net.Pipe()
+	defer client.Close()
+	done := make(chan error, 1)
+	go func() {
+		defer server.Close()
+		done <- testServer().ServeConn(server)
+	}()
+
+	go func() {
+		_, _ = client.Write([]byte(requests + "\n"))
+	}()
+	line, err := bufio.NewReader(client).ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading the response: %v", err)
+	}
+	client.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("ServeConn() error = %v", err)
+	}
+	return line[:len(line)-1]
+}
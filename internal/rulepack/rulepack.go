@@ -0,0 +1,150 @@
+// Package rulepack fetches community instruction files (awesome-copilot,
+// awesome-cursorrules) and converts them into local rule files.
+package rulepack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Pack is a community source of instruction files.
+type Pack struct {
+	Name string
+	// URL is the raw file URL, with %s replaced by the instruction name.
+	URL string
+	// License applies to every file of the pack (SPDX identifier).
+	License string
+	// Home is the pack's repository, recorded as the rule source.
+	Home string
+}
+
+// Packs are the supported community rule packs by name.
+var Packs = map[string]Pack{
+	"awesome-copilot": {
+		Name:    "awesome-copilot",
+		URL:     "https://raw.githubusercontent.com/github/awesome-copilot/main/instructions/%s.instructions.md",
+		License: "MIT",
+		Home:    "https://github.com/github/awesome-copilot",
+	},
+	// cursor.directory publishes its rules as code; awesome-cursorrules hosts
+	// the same community rules as plain .cursorrules files.
+	"awesome-cursorrules": {
+		Name:    "awesome-cursorrules",
+		URL:     "https://raw.githubusercontent.com/PatrickJS/awesome-cursorrules/main/rules/%s/.cursorrules",
+		License: "CC0-1.0",
+		Home:    "https://github.com/PatrickJS/awesome-cursorrules",
+	},
+}
+
+// Names returns the supported pack names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(Packs))
+	for name := range Packs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validName matches instruction names (no path traversal or URL syntax).
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Parse splits "<pack>:<name>" into the pack and the instruction name.
+func Parse(spec string) (Pack, string, error) {
+	packName, name, ok := strings.Cut(spec, ":")
+	if !ok || name == "" {
+		return Pack{}, "", fmt.Errorf("invalid source '%s' (expected <pack>:<name>, packs: %s)", spec, strings.Join(Names(), ", "))
+	}
+	pack, ok := Packs[packName]
+	if !ok {
+		return Pack{}, "", fmt.Errorf("unknown rule pack '%s' (available: %s)", packName, strings.Join(Names(), ", "))
+	}
+	if !validName.MatchString(name) {
+		return Pack{}, "", fmt.Errorf("invalid instruction name '%s'", name)
+	}
+	return pack, name, nil
+}
+
+// Fetch downloads one instruction file of the pack.
+func Fetch(ctx context.Context, client *http.Client, pack Pack, name string) ([]byte, string, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	url := fmt.Sprintf(pack.URL, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", fmt.Errorf("%s has no instructions named '%s' (%s)", pack.Name, name, url)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("fetch %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return data, url, err
+}
+
+// Provenance is the front matter recorded on imported rules.
+type Provenance struct {
+	Author  string `yaml:"author,omitempty"`
+	Source  string `yaml:"source,omitempty"`
+	License string `yaml:"license,omitempty"`
+}
+
+// Convert turns an instruction file into a rule file: the source front
+// matter (applyTo, globs, ...) is dropped, its description kept as the
+// introduction, provenance recorded as front matter, and a title added when
+// the file has none. Front matter that is never closed is an error, since
+// it would end up in the rule body.
+func Convert(data []byte, title string, p Provenance) (string, error) {
+	body := strings.ReplaceAll(string(data), "\r\n", "\n")
+	var description string
+	if rest, ok := strings.CutPrefix(body, "---\n"); ok {
+		rest += "\n"
+		end := strings.Index(rest, "\n---\n")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated front matter")
+		}
+		var fm struct {
+			Description string `yaml:"description"`
+		}
+		// Unknown or invalid front matter is dropped either way
+		_ = yaml.Unmarshal([]byte(rest[:end]), &fm)
+		description = strings.TrimSpace(fm.Description)
+		body = rest[end+len("\n---\n"):]
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("instruction file is empty")
+	}
+
+	heading := "# " + title
+	if strings.HasPrefix(body, "# ") {
+		heading, body, _ = strings.Cut(body, "\n")
+		body = strings.TrimSpace(body)
+	}
+	if description != "" {
+		body = description + "\n\n" + body
+	}
+	body = strings.TrimSpace(heading + "\n\n" + body)
+
+	fm, err := yaml.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return "---\n" + string(fm) + "---\n" + body + "\n", nil
+}
@@ -0,0 +1,132 @@
+package rulepack
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the .golden files in testdata")
+
+var provenance = Provenance{Source: "https://example.com/pack/file", License: "MIT"}
+
+// Each fixture is a community instruction file; its conversion is compared
+// with testdata/<fixture>.golden.
+func TestConvertGolden(t *testing.T) {
+	for _, fixture := range []string{
+		"front-matter.instructions.md",
+		"invalid-yaml.instructions.md",
+		"front-matter-list.instructions.md",
+		"crlf.instructions.md",
+		"plain.cursorrules",
+	} {
+		t.Run(fixture, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Convert(data, "Imported Guidelines", provenance)
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+
+			golden := filepath.Join("testdata", fixture+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("Convert() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestConvertRejects(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    string
+	}{
+		{"unterminated.instructions.md", "unterminated front matter"},
+		{"empty.instructions.md", "instruction file is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Convert(data, "Imported Guidelines", provenance); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Convert() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	pack, name, err := Parse("awesome-copilot:vuejs3")
+	if err != nil || pack.Name != "awesome-copilot" || name != "vuejs3" {
+		t.Errorf("Parse() = %q, %q, %v", pack.Name, name, err)
+	}
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"vuejs3", "invalid source"},
+		{"awesome-copilot:", "invalid source"},
+		{"unknown:vuejs3", "unknown rule pack 'unknown'"},
+		{"awesome-copilot:../../etc/passwd", "invalid instruction name"},
+		{"awesome-copilot:..", "invalid instruction name"},
+		{"awesome-copilot:nested/name", "invalid instruction name"},
+		{`awesome-copilot:..\windows`, "invalid instruction name"},
+		{"awesome-copilot:.hidden", "invalid instruction name"},
+		{"awesome-copilot:%2e%2e", "invalid instruction name"},
+		{"awesome-copilot:name?ref=evil", "invalid instruction name"},
+		{"awesome-copilot:name#fragment", "invalid instruction name"},
+		{"awesome-copilot:https://example.com/x", "invalid instruction name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			if _, _, err := Parse(tt.spec); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/vuejs3.md":
+			_, _ = w.Write([]byte("# Vue\n"))
+		case "/broken.md":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	pack := Pack{Name: "test", URL: server.URL + "/%s.md"}
+
+	data, url, err := Fetch(context.Background(), server.Client(), pack, "vuejs3")
+	if err != nil || string(data) != "# Vue\n" || url != server.URL+"/vuejs3.md" {
+		t.Errorf("Fetch() = %q, %q, %v", data, url, err)
+	}
+	if _, _, err := Fetch(context.Background(), server.Client(), pack, "missing"); err == nil || !strings.Contains(err.Error(), "test has no instructions named 'missing'") {
+		t.Errorf("Fetch() error = %v, want a not found error", err)
+	}
+	if _, _, err := Fetch(context.Background(), server.Client(), pack, "broken"); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Fetch() error = %v, want the status", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Package schema derives JSON Schemas (draft 2020-12) from Go types, so the
+// published schemas cannot drift from the structs that are decoded.
+package schema
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Draft is the JSON Schema dialect of generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema.
+type Schema map[string]any
+
+// For returns the schema of t, naming properties after the given struct tag
+// ("json" or "yaml"). Struct schemas reject unknown properties.
+func For(t reflect.Type, tag, title string) Schema {
+	s := of(t, tag)
+	s["$schema"] = Draft
+	if title != "" {
+		s["title"] = title
+	}
+	return s
+}
+
+func of(t reflect.Type, tag string) Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": of(t.Elem(), tag)}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": of(t.Elem(), tag)}
+	case reflect.Struct:
+		props := Schema{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(f.Tag.Get(tag), ",")
+			if name == "-" {
+				continue
+			}
+			// Embedded structs without a name are inlined, as encoding/json does
+			if f.Anonymous && name == "" {
+				embedded := of(f.Type, tag)
+				if inner, ok := embedded["properties"].(Schema); ok {
+					for k, v := range inner {
+						props[k] = v
+					}
+					if req, ok := embedded["required"].([]string); ok {
+						required = append(required, req...)
+					}
+					continue
+				}
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = of(f.Type, tag)
+			if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+				required = append(required, name)
+			}
+		}
+		s := Schema{"type": "object", "properties": props, "additionalProperties": false}
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		return s
+	}
+	return Schema{}
+}
+
+// Property returns the subschema at the dotted property path (e.g.
+// "markdownlint.rules"), or nil when it does not exist.
+func (s Schema) Property(path string) Schema {
+	current := s
+	for _, name := range strings.Split(path, ".") {
+		props, ok := current["properties"].(Schema)
+		if !ok {
+			return nil
+		}
+		if current, ok = props[name].(Schema); !ok {
+			return nil
+		}
+	}
+	return current
+}
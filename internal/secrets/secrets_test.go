@@ -0,0 +1,117 @@
+package secrets
+
+import (
+	"strings"
+	"testing"
+)
+
+// The secrets are assembled at run time, so that secret scanners do not flag
+// this file.
+var (
+	awsKey       = "AKIA" + "Q3ZT7M2K9PLW4XRV"
+	githubToken  = "ghp_" + strings.Repeat("a1B2c3", 6)
+	githubPAT    = "github_pat_" + strings.Repeat("A1b2_", 13)
+	gitlabToken  = "glpat-" + "Zx81_kPq2-Lm93vNb7Rt"
+	slackToken   = "xoxb-" + "1234567890-abcdEFGH"
+	stripeKey    = "sk_live_" + "4eC39HqLyjWDarjtT1zdp7dc"
+	googleKey    = "AIza" + "SyD-9tSrke72PouQMnMX-a7eZSW0jkFMBWY"
+	anthropicKey = "sk-ant-" + "api03-Rk2mZ8pLqW4nX7vB9cT1"
+	openAIKey    = "sk-proj-" + "Qw3rTy7UiOp1AsDfGhJkLzXcVbNm4567"
+	jwt          = "eyJ" + "hbGciOiJIUzI1NiJ9" + ".eyJ" + "zdWIiOiIxMjM0NTY3ODkwIn0" + ".SflKxwRJSMeKKF2QT4fwpMeJf36POk6yJV_adQssw5c"
+	privateKey   = "-----BEGIN " + "RSA PRIVATE KEY-----"
+)
+
+func TestScanPatterns(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		kind  string
+		value string
+	}{
+		{"private key", privateKey, "private key", privateKey},
+		{"AWS access key", "aws_access_key_id = " + awsKey, "AWS access key", awsKey},
+		{"GitHub token", "token: " + githubToken, "GitHub token", githubToken},
+		{"GitHub fine-grained token", githubPAT, "GitHub token", githubPAT},
+		{"GitLab token", "GITLAB_TOKEN=" + gitlabToken, "GitLab token", gitlabToken},
+		{"Slack token", slackToken, "Slack token", slackToken},
+		{"Stripe key", "stripe: " + stripeKey, "Stripe key", stripeKey},
+		{"Google API key", googleKey, "Google API key", googleKey},
+		{"Anthropic API key", "ANTHROPIC_API_KEY=" + anthropicKey, "Anthropic API key", anthropicKey},
+		{"OpenAI API key", "OPENAI_API_KEY=" + openAIKey, "OpenAI API key", openAIKey},
+		{"JSON web token", "Authorization: Bearer " + jwt, "JSON web token", jwt},
+		{"credential assignment", `'password' => 'Tr0ub4dor3-horse-battery'`, "credential assignment", "Tr0ub4dor3-horse-battery"},
+		{"credential assignment in YAML", `api_key: "q8W2e4R6t8Y0u2I4o6P8"`, "credential assignment", "q8W2e4R6t8Y0u2I4o6P8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Scan("first line\n" + tt.line + "\n")
+			if len(findings) != 1 {
+				t.Fatalf("Scan() found %d secrets, want 1: %v", len(findings), findings)
+			}
+			f := findings[0]
+			if f.Kind != tt.kind || f.Line != 2 {
+				t.Errorf("Scan() = %s on line %d, want %s on line 2", f.Kind, f.Line, tt.kind)
+			}
+			if want := mask(tt.value); f.Match != want {
+				t.Errorf("Scan() match = %q, want %q", f.Match, want)
+			}
+			if strings.Contains(f.String(), tt.value) {
+				t.Errorf("Finding.String() shows the secret: %s", f)
+			}
+		})
+	}
+}
+
+func TestScanIgnoresPlaceholders(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"prose about keys", "Never commit API keys, tokens or passwords; read them from the environment."},
+		{"env reference", "api_key: env('STRIPE_SECRET')"},
+		{"placeholder value", `api_key: "your-api-key-here-0000"`},
+		{"example value", `'password' => 'example-password-123'`},
+		{"masked value", `secret: "****************"`},
+		{"AWS documentation key", "AKIA" + "IOSFODNN7EXAMPLE"},
+		{"short value", `password: "secret"`},
+		{"key names only", "Set OPENAI_API_KEY and ANTHROPIC_API_KEY in .env."},
+		{"sk prefix in prose", "Use the sk- prefix check in the key validator."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if findings := Scan(tt.text); len(findings) != 0 {
+				t.Errorf("Scan(%q) = %v, want no findings", tt.text, findings)
+			}
+			if got := Redact(tt.text); got != tt.text {
+				t.Errorf("Redact(%q) = %q, want it unchanged", tt.text, got)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	in := "# Setup\n\nexport AWS_KEY=" + awsKey + " GITHUB=" + githubToken + "\nplain line\n"
+	want := "# Setup\n\nexport AWS_KEY=[REDACTED AWS access key] GITHUB=[REDACTED GitHub token]\nplain line\n"
+	if got := Redact(in); got != want {
+		t.Errorf("Redact() =\n%q\nwant\n%q", got, want)
+	}
+	if findings := Scan(Redact(in)); len(findings) != 0 {
+		t.Errorf("Scan(Redact()) = %v, want no findings", findings)
+	}
+}
+
+func TestRedactOnlyTheValueOfAssignments(t *testing.T) {
+	in := `DB_PASSWORD: "q8W2e4R6t8Y0u2I4o6P8"`
+	want := `DB_PASSWORD: "[REDACTED credential assignment]"`
+	if got := Redact(in); got != want {
+		t.Errorf("Redact() = %q, want %q", got, want)
+	}
+}
+
+func TestOverlappingPatternsReportOnce(t *testing.T) {
+	// An Anthropic key also matches the OpenAI pattern; the first pattern wins
+	findings := Scan("key " + anthropicKey)
+	if len(findings) != 1 || findings[0].Kind != "Anthropic API key" {
+		t.Errorf("Scan() = %v, want one Anthropic API key", findings)
+	}
+}
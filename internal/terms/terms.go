@@ -0,0 +1,147 @@
+package terms
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Dictionary holds terminology and spelling rules.
+type Dictionary struct {
+	// Terminology maps a preferred term to variants that must not be used
+	// (matched case-sensitively), e.g. "Nuxt UI": ["NuxtUI"].
+	Terminology map[string][]string
+	// Spelling maps common misspellings to their correction (matched case-insensitively).
+	Spelling map[string]string
+}
+
+// Default is the built-in dictionary used by rules lint.
+var Default = Dictionary{
+	Terminology: map[string][]string{
+		"Nuxt UI":    {"NuxtUI", "Nuxt-UI", "NuxtUi"},
+		"TypeScript": {"Typescript", "TypeSCript"},
+		"JavaScript": {"Javascript"},
+		"GitHub":     {"Github"},
+		"Laravel":    {"laravel framework"},
+		"Vue":        {"VueJS", "VueJs", "Vuejs"},
+		"PHPUnit":    {"PhpUnit", "phpUnit"},
+	},
+	Spelling: map[string]string{
+		"accross":      "across",
+		"acheive":      "achieve",
+		"adress":       "address",
+		"agressive":    "aggressive",
+		"arguement":    "argument",
+		"begining":     "beginning",
+		"beleive":      "believe",
+		"calender":     "calendar",
+		"comparision":  "comparison",
+		"concious":     "conscious",
+		"definately":   "definitely",
+		"dependancy":   "dependency",
+		"dependancies": "dependencies",
+		"enviroment":   "environment",
+		"existant":     "existent",
+		"explicitely":  "explicitly",
+		"familar":      "familiar",
+		"goverment":    "government",
+		"independant":  "independent",
+		"occured":      "occurred",
+		"occurence":    "occurrence",
+		"paramter":     "parameter",
+		"perfomance":   "performance",
+		"persistant":   "persistent",
+		"posible":      "possible",
+		"prefered":     "preferred",
+		"recieve":      "receive",
+		"refered":      "referred",
+		"reponse":      "response",
+		"seperate":     "separate",
+		"sucessful":    "successful",
+		"succesful":    "successful",
+		"teh":          "the",
+		"untill":       "until",
+		"wich":         "which",
+	},
+}
+
+// Merge returns a dictionary with the entries of other added to d.
+func (d Dictionary) Merge(other Dictionary) Dictionary {
+	out := Dictionary{Terminology: map[string][]string{}, Spelling: map[string]string{}}
+	for _, src := range []Dictionary{d, other} {
+		for term, variants := range src.Terminology {
+			out.Terminology[term] = append(out.Terminology[term], variants...)
+		}
+		for wrong, right := range src.Spelling {
+			out.Spelling[strings.ToLower(wrong)] = right
+		}
+	}
+	return out
+}
+
+// Violation is a terminology or spelling issue.
+type Violation struct {
+	Rule       string
+	Line       int
+	Found      string
+	Suggestion string
+	Kind       string // terminology, spelling
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("rules/%s.md:%d: %s: use '%s' instead of '%s'", v.Rule, v.Line, v.Kind, v.Suggestion, v.Found)
+}
+
+var (
+	inlineCode = regexp.MustCompile("`[^`]*`")
+	urls       = regexp.MustCompile(`\]\([^)]*\)|https?://\S+`)
+)
+
+// Check finds violations in prose (fenced code, inline code and URLs are skipped).
+func Check(ruleID, body string, d Dictionary) []Violation {
+	type matcher struct {
+		re         *regexp.Regexp
+		suggestion string
+		kind       string
+	}
+
+	var matchers []matcher
+	for _, term := range sortedKeys(d.Terminology) {
+		for _, variant := range d.Terminology[term] {
+			matchers = append(matchers, matcher{regexp.MustCompile(`\b` + regexp.QuoteMeta(variant) + `\b`), term, "terminology"})
+		}
+	}
+	for _, wrong := range sortedKeys(d.Spelling) {
+		matchers = append(matchers, matcher{regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(wrong) + `\b`), d.Spelling[wrong], "spelling"})
+	}
+
+	var out []Violation
+	inFence := false
+	for i, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		prose := urls.ReplaceAllString(inlineCode.ReplaceAllString(line, ""), "")
+		for _, m := range matchers {
+			for _, found := range m.re.FindAllString(prose, -1) {
+				out = append(out, Violation{Rule: ruleID, Line: i + 1, Found: found, Suggestion: m.suggestion, Kind: m.kind})
+			}
+		}
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,112 @@
+package terms
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Violation
+	}{
+		{
+			name: "terminology variant",
+			body: "Write Typescript, not plain JS.",
+			want: []Violation{{Rule: "vue/general", Line: 1, Found: "Typescript", Suggestion: "TypeScript", Kind: "terminology"}},
+		},
+		{
+			name: "terminology is case-sensitive",
+			body: "Files end in .typescript or TYPESCRIPT.",
+		},
+		{
+			name: "terminology matches whole words only",
+			body: "See the GithubActions runner.",
+		},
+		{
+			name: "multi-word variant",
+			body: "Prefer the laravel framework conventions.",
+			want: []Violation{{Rule: "vue/general", Line: 1, Found: "laravel framework", Suggestion: "Laravel", Kind: "terminology"}},
+		},
+		{
+			name: "spelling ignores case and keeps what was found",
+			body: "Keep concerns Seperate.\n\nIt occured twice.",
+			want: []Violation{
+				{Rule: "vue/general", Line: 1, Found: "Seperate", Suggestion: "separate", Kind: "spelling"},
+				{Rule: "vue/general", Line: 3, Found: "occured", Suggestion: "occurred", Kind: "spelling"},
+			},
+		},
+		{
+			name: "spelling matches whole words only",
+			body: "Read the tehcnical notes.",
+		},
+		{
+			name: "inline code is skipped",
+			body: "Run `npm i Javascript-utils` and `teh`.",
+		},
+		{
+			name: "backtick fences are skipped",
+			body: "```js\n// Javascript recieve\n```\nrecieve",
+			want: []Violation{{Rule: "vue/general", Line: 4, Found: "recieve", Suggestion: "receive", Kind: "spelling"}},
+		},
+		{
+			name: "tilde fences are skipped",
+			body: "~~~\nGithub\n~~~",
+		},
+		{
+			name: "indented fences are skipped",
+			body: "- Example:\n\n  ```\n  Github\n  ```",
+		},
+		{
+			name: "link targets and URLs are skipped",
+			body: "See [the docs](https://github.com/Github/wich) or https://example.com/Javascript.",
+		},
+		{
+			name: "link text is checked",
+			body: "See [the Github docs](https://github.com).",
+			want: []Violation{{Rule: "vue/general", Line: 1, Found: "Github", Suggestion: "GitHub", Kind: "terminology"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Check("vue/general", tt.body, Default)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	d := Default.Merge(Dictionary{
+		Terminology: map[string][]string{"GitHub": {"Git Hub"}, "Cego": {"CEGO"}},
+		Spelling:    map[string]string{"Recieved": "received"},
+	})
+
+	if want := []string{"Github", "Git Hub"}; !reflect.DeepEqual(d.Terminology["GitHub"], want) {
+		t.Errorf("Terminology[GitHub] = %v, want %v", d.Terminology["GitHub"], want)
+	}
+	if got := d.Spelling["recieved"]; got != "received" {
+		t.Errorf("Spelling[recieved] = %q, want the key lowercased", got)
+	}
+	if len(Default.Terminology["GitHub"]) != 1 {
+		t.Errorf("Merge() changed Default: %v", Default.Terminology["GitHub"])
+	}
+
+	got := Check("local/general", "Ask CEGO; it was Recieved.", d)
+	want := []Violation{
+		{Rule: "local/general", Line: 1, Found: "CEGO", Suggestion: "Cego", Kind: "terminology"},
+		{Rule: "local/general", Line: 1, Found: "Recieved", Suggestion: "received", Kind: "spelling"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Check() = %v, want %v", got, want)
+	}
+}
+
+func TestViolationString(t *testing.T) {
+	v := Violation{Rule: "php/general", Line: 3, Found: "teh", Suggestion: "the", Kind: "spelling"}
+	if got, want := v.String(), "rules/php/general.md:3: spelling: use 'the' instead of 'teh'"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
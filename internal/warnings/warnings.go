@@ -0,0 +1,65 @@
+// Package warnings collects non-fatal issues found during a run (skipped
+// manifests, version fallbacks, missing rule variants) so they can be
+// summarized at the end instead of being lost.
+package warnings
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Warning is one non-fatal issue.
+type Warning struct {
+	Source  string `json:"source"` // e.g. "detect", "rules"
+	Message string `json:"message"`
+}
+
+func (w Warning) String() string {
+	return w.Source + ": " + w.Message
+}
+
+var (
+	mu   sync.Mutex
+	list []Warning
+	seen = map[Warning]bool{}
+)
+
+// Add records a warning; identical warnings are recorded once.
+func Add(source, format string, args ...any) {
+	w := Warning{Source: source, Message: fmt.Sprintf(format, args...)}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !seen[w] {
+		seen[w] = true
+		list = append(list, w)
+	}
+}
+
+// List returns the warnings recorded so far, in order.
+func List() []Warning {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Warning(nil), list...)
+}
+
+// Reset drops all recorded warnings (e.g. before a watch-mode rerun).
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	list = nil
+	seen = map[Warning]bool{}
+}
+
+// PrintSummary writes the recorded warnings to w, if there are any.
+func PrintSummary(w io.Writer) {
+	ws := List()
+	if len(ws) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nWarnings (%d):\n", len(ws))
+	for _, warning := range ws {
+		fmt.Fprintf(w, "- %s\n", warning)
+	}
+}
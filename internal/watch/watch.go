@@ -0,0 +1,84 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Fingerprint summarizes the state (paths, sizes, modification times) of the
+// given files and directories (recursively). Missing paths are recorded as such.
+func Fingerprint(paths []string) string {
+	var entries []string
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				entries = append(entries, path+":missing")
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			entries = append(entries, fmt.Sprintf("%s:%d:%d", path, info.Size(), info.ModTime().UnixNano()))
+			return nil
+		})
+		if err != nil {
+			entries = append(entries, root+":error")
+		}
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, "\n")
+}
+
+// Poll calls onChange whenever the fingerprint of paths changes, checking every
+// interval until ctx is cancelled.
+func Poll(ctx context.Context, paths []string, interval time.Duration, onChange func()) error {
+	last := Fingerprint(paths)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			current := Fingerprint(paths)
+			if current != last {
+				last = current
+				onChange()
+			}
+		}
+	}
+}
+
+// WaitStable blocks until ready reports true and the fingerprint of paths has
+// not changed for settle, checking every interval. It fails when ctx ends first.
+func WaitStable(ctx context.Context, paths []string, interval, settle time.Duration, ready func() bool) error {
+	last := Fingerprint(paths)
+	since := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if ready() && time.Since(since) >= settle {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if current := Fingerprint(paths); current != last {
+				last = current
+				since = time.Now()
+			}
+		}
+	}
+}
@@ -1,51 +1,560 @@
 package rules
 
 import (
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
+	"fmt"
 	"io/fs"
+	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"time"
+
+	"go.yaml.in/yaml/v3"
 )
 
-// Embed the entire rules directory (this directory) recursively.
+// Embed every rule directory (below this directory) recursively, including
+// assets such as images stored alongside the markdown files.
 //
-//go:embed **/*.md
+//go:embed */*
 var embeddedFS embed.FS
 
+// baseFS holds the rules that local rules are layered over: the embedded
+// rules, or the rules of a bundle (see SetBaseDir).
+var baseFS fs.FS = embeddedFS
+
+// localFS holds project-local rules layered over (and taking precedence over)
+// the embedded rules; nil when no local rules directory is in use.
+var (
+	localFS  fs.FS
+	localDir string
+)
+
+// The rule index (all IDs) is built once on first use and rule contents are
+// parsed once per ID; both are dropped whenever the rule sources change.
+var (
+	cacheMu sync.Mutex
+	index   map[string]bool
+	ids     []string
+	files   []string
+	parsed  map[string]*Rule
+)
+
+// Default size limits of rules lint (and of the embedded rules, checked by
+// the tests); they keep the binary and the generated files from growing
+// unnoticed.
+const (
+	DefaultMaxRuleBytes  = 64 << 10
+	DefaultMaxTotalBytes = 1 << 20
+)
+
+// VariantSeparator separates a rule ID from its experiment variant, as in
+// laravel/general@experiment-x (rules/laravel/general@experiment-x.md).
+const VariantSeparator = "@"
+
+// experiments are the active experiments, in order of preference.
+var experiments []string
+
+// SetLocalDir layers the rules found in dir over the embedded rules (empty disables).
+func SetLocalDir(dir string) {
+	localDir = dir
+	if dir == "" {
+		localFS = nil
+	} else {
+		localFS = os.DirFS(dir)
+	}
+	invalidate()
+}
+
+// SetBaseDir replaces the embedded rules by the rules found in dir, e.g. those
+// of an offline bundle (empty restores the embedded rules). Local rules are
+// still layered over them.
+func SetBaseDir(dir string) {
+	if dir == "" {
+		baseFS = embeddedFS
+	} else {
+		baseFS = os.DirFS(dir)
+	}
+	invalidate()
+}
+
+// Reload re-reads the local rules directory, dropping any cached content so
+// that edits made while the process runs (watch mode) are picked up.
+func Reload() {
+	if localDir != "" {
+		localFS = os.DirFS(localDir)
+	}
+	invalidate()
+}
+
+// SetExperiments makes Load return the variant of a rule for the first of
+// the given experiments that has one (e.g. general@experiment-x.md).
+func SetExperiments(names []string) {
+	cacheMu.Lock()
+	experiments = append([]string(nil), names...)
+	cacheMu.Unlock()
+	invalidate()
+}
+
+// SplitVariant splits a file ID such as laravel/general@experiment-x into the
+// rule ID and the variant ("" for the base file).
+func SplitVariant(name string) (id, variant string) {
+	id, variant, _ = strings.Cut(name, VariantSeparator)
+	return id, variant
+}
+
+func invalidate() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	index, ids, files, parsed = nil, nil, nil, nil
+}
+
+// IsLocal reports whether a rule is provided by the local rules directory.
+func IsLocal(name string) bool {
+	if localFS == nil {
+		return false
+	}
+	_, err := fs.Stat(localFS, name+".md")
+	return err == nil
+}
+
+func readFile(name string) ([]byte, error) {
+	if localFS != nil {
+		if data, err := fs.ReadFile(localFS, name); err == nil {
+			return data, nil
+		}
+	}
+	return fs.ReadFile(baseFS, name)
+}
+
+// Snapshot returns every file of the rule sources (markdown and assets) by
+// slash-separated path, local files taking precedence over base files.
+func Snapshot() (map[string][]byte, error) {
+	out := map[string][]byte{}
+	collect := func(fsys fs.FS) error {
+		return fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := fs.ReadFile(fsys, path)
+			if err != nil {
+				return err
+			}
+			out[path] = data
+			return nil
+		})
+	}
+	if err := collect(baseFS); err != nil {
+		return nil, err
+	}
+	if localFS != nil {
+		if err := collect(localFS); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// Files returns every rule file identifier, including experiment variants.
+func Files() ([]string, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if err := buildIndex(); err != nil {
+		return nil, err
+	}
+	return append([]string(nil), files...), nil
+}
+
 // List returns all markdown rule identifiers (relative path without .md).
+// Experiment variants are not listed; Load resolves them.
 func List() ([]string, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if err := buildIndex(); err != nil {
+		return nil, err
+	}
+	return append([]string(nil), ids...), nil
+}
+
+// Count returns the number of rules List returns, without copying them.
+func Count() (int, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if err := buildIndex(); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// ByPrefix returns the rule identifiers below the directory prefix, e.g.
+// ByPrefix("laravel") yields laravel/general and laravel/11/general but not
+// laravel-nova/general. An empty prefix returns every rule.
+func ByPrefix(prefix string) ([]string, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if err := buildIndex(); err != nil {
+		return nil, err
+	}
+
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return append([]string(nil), ids...), nil
+	}
+	prefix += "/"
+	// ids is sorted, so the matches form a contiguous run.
+	start := sort.SearchStrings(ids, prefix)
+	end := start
+	for end < len(ids) && strings.HasPrefix(ids[end], prefix) {
+		end++
+	}
+	return append([]string(nil), ids[start:end]...), nil
+}
+
+// Exists reports whether a rule with the given identifier exists.
+func Exists(name string) bool {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if err := buildIndex(); err != nil {
+		return false
+	}
+	return index[name]
+}
+
+// Glob returns the rule identifiers matching a path.Match pattern, e.g.
+// "php/8/*" or "*/general" ("*" does not cross "/").
+func Glob(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+	}
+
+	names, err := List()
+	if err != nil {
+		return nil, err
+	}
 	var out []string
-	err := fs.WalkDir(embeddedFS, ".", func(path string, d fs.DirEntry, walkErr error) error {
+	for _, n := range names {
+		if ok, _ := path.Match(pattern, n); ok {
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+// Children returns the names directly below prefix, e.g. Children("laravel")
+// yields rule files ("general", "review") and version directories ("11").
+func Children(prefix string) ([]string, error) {
+	names, err := ByPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	seen := map[string]bool{}
+	var out []string
+	for _, n := range names {
+		child, _, _ := strings.Cut(strings.TrimPrefix(n, prefix), "/")
+		if !seen[child] {
+			seen[child] = true
+			out = append(out, child)
+		}
+	}
+	return out, nil
+}
+
+// embeddedNames lists the embedded rule files once per process: the embedded
+// FS never changes, so invalidate only causes bundles and local rules to be
+// walked again.
+var embeddedNames = sync.OnceValues(func() ([]string, error) {
+	return ruleNames(embeddedFS)
+})
+
+// ruleNames returns the markdown files of fsys as identifiers (without .md).
+func ruleNames(fsys fs.FS) ([]string, error) {
+	var out []string
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, walkErr error) error {
 		if walkErr != nil {
 			return walkErr
 		}
-		if d.IsDir() {
+		if d.IsDir() || filepath.Ext(path) != ".md" {
 			return nil
 		}
-		if filepath.Ext(path) != ".md" {
-			return nil
-		}
-		// Remove leading "./" if present.
-		if strings.HasPrefix(path, "./") {
-			path = strings.TrimPrefix(path, "./")
-		}
-		name := strings.TrimSuffix(path, ".md")
-		out = append(out, name)
+		out = append(out, strings.TrimSuffix(strings.TrimPrefix(path, "./"), ".md"))
 		return nil
 	})
+	return out, err
+}
+
+// buildIndex lists the base and local rules once; callers hold cacheMu.
+func buildIndex() error {
+	if index != nil {
+		return nil
+	}
+
+	var base []string
+	var err error
+	if baseFS == fs.FS(embeddedFS) {
+		base, err = embeddedNames()
+	} else {
+		base, err = ruleNames(baseFS)
+	}
 	if err != nil {
-		return nil, err
+		return err
+	}
+	var local []string
+	if localFS != nil {
+		if local, err = ruleNames(localFS); err != nil {
+			return err
+		}
+	}
+
+	seen := make(map[string]bool, len(base)+len(local))
+	out := make([]string, 0, len(base)+len(local))
+	for _, name := range slices.Concat(base, local) {
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
 	}
 	sort.Strings(out)
-	return out, nil
+	var plain []string
+	for _, name := range out {
+		if !strings.Contains(name, VariantSeparator) {
+			plain = append(plain, name)
+		}
+	}
+	index, ids, files = seen, plain, out
+	return nil
 }
 
-// Get returns the markdown content for a rule (name is relative path without .md).
+// Rule is a parsed rule file: optional front matter plus markdown body.
+type Rule struct {
+	ID   string
+	Meta Meta
+	Body string
+	// Variant is the experiment whose variant file was loaded ("" for the base file).
+	Variant string
+}
+
+// Meta is the optional YAML front matter at the top of a rule file.
+type Meta struct {
+	// When is a condition on the detected stack, e.g. `stack.Laravel != "" && stack.Vue != ""`.
+	When string `yaml:"when,omitempty"`
+
+	// Overrides suppress or replace sections/bullets of less specific rules.
+	Overrides []Override `yaml:"overrides,omitempty"`
+
+	// Audience is who the rule is written for: AudienceAuthor (default, code
+	// generation) or AudienceReviewer (code review instructions).
+	Audience string `yaml:"audience,omitempty"`
+
+	// Requires lists project settings the rule depends on (e.g.
+	// RequireTypeScriptStrict); validate --strict fails when they are missing.
+	Requires []string `yaml:"requires,omitempty"`
+
+	// Tags are the topics of the whole rule (e.g. testing, security) and
+	// SectionTags those of single sections, by heading; generate --tags
+	// keeps only the matching rules and sections.
+	Tags        []string            `yaml:"tags,omitempty"`
+	SectionTags map[string][]string `yaml:"sectionTags,omitempty"`
+
+	// Optional rules (rule packs such as accessibility) are only included
+	// from detection when a pack (generate --pack, config packs) or --tags
+	// names one of their tags.
+	Optional bool `yaml:"optional,omitempty"`
+
+	// Author, Source and License record the provenance of imported rules
+	// (e.g. community rule packs); generate --attribution lists them.
+	Author  string `yaml:"author,omitempty"`
+	Source  string `yaml:"source,omitempty"`
+	License string `yaml:"license,omitempty"`
+
+	// Expires is the last day (YYYY-MM-DD) the rule is included, for
+	// temporary guidance such as "do not touch module X during the migration".
+	Expires string `yaml:"expires,omitempty"`
+
+	// Description, Mode, Tools and Model are copied into generated Copilot
+	// prompt files (rules/prompts) and chat modes (rules/chatmodes).
+	Description string   `yaml:"description,omitempty"`
+	Mode        string   `yaml:"mode,omitempty"`
+	Tools       []string `yaml:"tools,omitempty"`
+	Model       string   `yaml:"model,omitempty"`
+}
+
+// HasProvenance reports whether the rule declares an author, source or license.
+func (m Meta) HasProvenance() bool {
+	return m.Author != "" || m.Source != "" || m.License != ""
+}
+
+// ExpiresLayout is the date format of Meta.Expires.
+const ExpiresLayout = "2006-01-02"
+
+// Expired reports whether the rule's expiry date lies before now (a rule
+// is included through its expiry day). Invalid dates never expire; rules
+// lint reports them.
+func (m Meta) Expired(now time.Time) bool {
+	return Expired(m.Expires, now)
+}
+
+// Expired reports whether the expiry date (ExpiresLayout, "" for none) lies before now.
+func Expired(expires string, now time.Time) bool {
+	if expires == "" {
+		return false
+	}
+	day, err := time.Parse(ExpiresLayout, expires)
+	if err != nil {
+		return false
+	}
+	y, m, d := now.Date()
+	return day.Before(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+}
+
+// Rule audiences.
+const (
+	AudienceAuthor   = "author"
+	AudienceReviewer = "reviewer"
+)
+
+// Known requirements for Meta.Requires.
+const (
+	RequireTypeScriptStrict = "typescript-strict"
+)
+
+// IsReviewer reports whether the rule targets code review rather than authoring.
+func (m Meta) IsReviewer() bool {
+	return m.Audience == AudienceReviewer
+}
+
+// Override targets a section (by heading) or bullets (by leading text) in other
+// rules merged into the same output. An empty Replace removes the target.
+type Override struct {
+	// Rule limits the override to one rule (e.g. laravel/general); empty means
+	// the less specific rules of the same tree (laravel/general and
+	// laravel/11/general for laravel/11.2/general).
+	Rule    string `yaml:"rule,omitempty"`
+	Section string `yaml:"section,omitempty"`
+	Bullet  string `yaml:"bullet,omitempty"`
+	Replace string `yaml:"replace,omitempty"`
+}
+
+// Load returns the parsed rule (name is relative path without .md). The
+// result is a copy; rules are read and parsed only once.
+func Load(name string) (*Rule, error) {
+	cacheMu.Lock()
+	cached, ok := parsed[name]
+	cacheMu.Unlock()
+	if ok {
+		r := *cached
+		return &r, nil
+	}
+
+	file, variant := name, ""
+	if !strings.Contains(name, VariantSeparator) {
+		file, variant = resolveVariant(name)
+	}
+	data, err := readFile(file + ".md")
+	if err != nil {
+		return nil, err
+	}
+	meta, body, err := ParseFrontMatter(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("rules/%s.md: %w", file, err)
+	}
+	r := &Rule{ID: name, Meta: meta, Body: body, Variant: variant}
+
+	cacheMu.Lock()
+	if parsed == nil {
+		parsed = map[string]*Rule{}
+	}
+	parsed[name] = r
+	cacheMu.Unlock()
+
+	copied := *r
+	return &copied, nil
+}
+
+// resolveVariant returns the file to load for a rule under the active experiments.
+func resolveVariant(name string) (file, variant string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if len(experiments) == 0 || buildIndex() != nil {
+		return name, ""
+	}
+	for _, exp := range experiments {
+		if candidate := name + VariantSeparator + exp; index[candidate] {
+			return candidate, exp
+		}
+	}
+	return name, ""
+}
+
+// Get returns the markdown content for a rule (name is relative path without .md),
+// without its front matter.
 func Get(name string) (string, error) {
-	data, err := embeddedFS.ReadFile(name + ".md")
+	r, err := Load(name)
+	if err != nil {
+		return "", err
+	}
+	return r.Body, nil
+}
+
+// Hash returns a short content hash over every rule file (embedded, local and
+// variants), stable across runs as long as the rule sources do not change.
+func Hash() (string, error) {
+	names, err := Files()
 	if err != nil {
 		return "", err
 	}
-	return string(data), nil
+	h := sha256.New()
+	for _, name := range names {
+		data, err := readFile(name + ".md")
+		if err != nil {
+			return "", fmt.Errorf("read rule %s: %w", name, err)
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", name, len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12], nil
+}
+
+// ParseFrontMatter splits a leading "---" delimited YAML block from the markdown body.
+func ParseFrontMatter(data string) (Meta, string, error) {
+	var meta Meta
+
+	normalized := strings.ReplaceAll(data, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return meta, data, nil
+	}
+
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---\n")
+	if end < 0 {
+		if !strings.HasSuffix(rest, "\n---") {
+			return meta, data, nil
+		}
+		end = len(rest) - len("\n---")
+	}
+
+	if err := yaml.Unmarshal([]byte(rest[:end]), &meta); err != nil {
+		return meta, "", fmt.Errorf("invalid front matter: %w", err)
+	}
+
+	body := ""
+	if end+len("\n---\n") <= len(rest) {
+		body = rest[end+len("\n---\n"):]
+	}
+	return meta, strings.TrimLeft(body, "\n"), nil
+}
+
+// Asset returns the raw content of a non-rule file (e.g. an image) stored in
+// the rules tree (name is the relative path including extension).
+func Asset(name string) ([]byte, error) {
+	return readFile(name)
 }
@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+// benchRule is an embedded rule that every benchmark loads.
+const benchRule = "laravel/general"
+
+// BenchmarkWalk is the cost every List call had before the index: walking
+// the embedded FS.
+func BenchmarkWalk(b *testing.B) {
+	for b.Loop() {
+		if _, err := ruleNames(embeddedFS); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoadCold(b *testing.B) {
+	for b.Loop() {
+		invalidate()
+		if _, err := Load(benchRule); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoadWarm(b *testing.B) {
+	invalidate()
+	if _, err := Load(benchRule); err != nil {
+		b.Fatal(err)
+	}
+	for b.Loop() {
+		if _, err := Load(benchRule); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGlobCold(b *testing.B) {
+	for b.Loop() {
+		invalidate()
+		if _, err := Glob("*/general"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGlobWarm(b *testing.B) {
+	invalidate()
+	if _, err := Glob("*/general"); err != nil {
+		b.Fatal(err)
+	}
+	for b.Loop() {
+		if _, err := Glob("*/general"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestEmbeddedSizes keeps the embedded rules within the limits of rules
+// lint, so that go test fails before the binary balloons.
+func TestEmbeddedSizes(t *testing.T) {
+	total := 0
+	err := fs.WalkDir(embeddedFS, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += int(info.Size())
+		if strings.HasSuffix(name, ".md") && info.Size() > DefaultMaxRuleBytes {
+			t.Errorf("rules/%s: %d bytes exceeds the maximum rule size of %d bytes", name, info.Size(), DefaultMaxRuleBytes)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total > DefaultMaxTotalBytes {
+		t.Errorf("rules: %d bytes in total exceeds the maximum of %d bytes", total, DefaultMaxTotalBytes)
+	}
+}